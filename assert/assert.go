@@ -0,0 +1,275 @@
+// Package assert builds the assertions of checks and monitors. Each function
+// returns a builder for a source of the check result, which only has the
+// comparisons meaningful for that source:
+//
+//	Request: checkly.Request{
+//		URL: "https://api.example.com/users/1",
+//		Assertions: []checkly.Assertion{
+//			assert.StatusCode().Equals(200),
+//			assert.JSONBody("$.data.id").NotEmpty(),
+//			assert.ResponseTime().LessThan(500),
+//		},
+//	}
+//
+// The sources each check type supports are listed with the functions, see
+// also the Validate methods of the monitor types.
+//
+// Evaluate evaluates assertions against a recorded response, to test them
+// without running the check:
+//
+//	sample, err := assert.HTTPSample(resp, elapsed)
+//	if err != nil {
+//		return err
+//	}
+//	for _, r := range assert.Evaluate(check.Request.Assertions, sample).Failed() {
+//		fmt.Println(r) // fail STATUS_CODE EQUALS "200" (actual 503)
+//	}
+package assert
+
+import (
+	"fmt"
+	"strconv"
+
+	checkly "github.com/checkly/checkly-go-sdk"
+)
+
+// Number builds assertions about a numeric source, like a status code or a
+// response time in milliseconds.
+type Number struct {
+	source   string
+	property string
+}
+
+// Equals asserts that the source equals n.
+func (b Number) Equals(n int) checkly.Assertion {
+	return assertion(b.source, b.property, checkly.Equals, strconv.Itoa(n))
+}
+
+// NotEquals asserts that the source doesn't equal n.
+func (b Number) NotEquals(n int) checkly.Assertion {
+	return assertion(b.source, b.property, checkly.NotEquals, strconv.Itoa(n))
+}
+
+// GreaterThan asserts that the source is greater than n.
+func (b Number) GreaterThan(n int) checkly.Assertion {
+	return assertion(b.source, b.property, checkly.GreaterThan, strconv.Itoa(n))
+}
+
+// LessThan asserts that the source is less than n.
+func (b Number) LessThan(n int) checkly.Assertion {
+	return assertion(b.source, b.property, checkly.LessThan, strconv.Itoa(n))
+}
+
+// Code builds assertions about a source holding one of a fixed set of codes,
+// like the response code of a DNS query.
+type Code struct {
+	source string
+}
+
+// Equals asserts that the source is code.
+func (b Code) Equals(code string) checkly.Assertion {
+	return assertion(b.source, "", checkly.Equals, code)
+}
+
+// NotEquals asserts that the source isn't code.
+func (b Code) NotEquals(code string) checkly.Assertion {
+	return assertion(b.source, "", checkly.NotEquals, code)
+}
+
+// Text builds assertions about a text source, like a response body or
+// header.
+type Text struct {
+	source   string
+	property string
+}
+
+// Equals asserts that the source is s.
+func (b Text) Equals(s string) checkly.Assertion {
+	return assertion(b.source, b.property, checkly.Equals, s)
+}
+
+// NotEquals asserts that the source isn't s.
+func (b Text) NotEquals(s string) checkly.Assertion {
+	return assertion(b.source, b.property, checkly.NotEquals, s)
+}
+
+// Contains asserts that the source contains s.
+func (b Text) Contains(s string) checkly.Assertion {
+	return assertion(b.source, b.property, checkly.Contains, s)
+}
+
+// NotContains asserts that the source doesn't contain s.
+func (b Text) NotContains(s string) checkly.Assertion {
+	return assertion(b.source, b.property, checkly.NotContains, s)
+}
+
+// IsEmpty asserts that the source is empty.
+func (b Text) IsEmpty() checkly.Assertion {
+	return assertion(b.source, b.property, checkly.IsEmpty, "")
+}
+
+// NotEmpty asserts that the source isn't empty.
+func (b Text) NotEmpty() checkly.Assertion {
+	return assertion(b.source, b.property, checkly.NotEmpty, "")
+}
+
+// Value builds assertions about a value selected from a structured source,
+// like a JSON path into a response body or a certificate field.
+type Value struct {
+	source   string
+	property string
+}
+
+// Equals asserts that the value is v, a string, number or boolean.
+func (b Value) Equals(v any) checkly.Assertion {
+	return assertion(b.source, b.property, checkly.Equals, fmt.Sprint(v))
+}
+
+// NotEquals asserts that the value isn't v, a string, number or boolean.
+func (b Value) NotEquals(v any) checkly.Assertion {
+	return assertion(b.source, b.property, checkly.NotEquals, fmt.Sprint(v))
+}
+
+// GreaterThan asserts that the value is a number greater than n.
+func (b Value) GreaterThan(n float64) checkly.Assertion {
+	return assertion(b.source, b.property, checkly.GreaterThan, formatFloat(n))
+}
+
+// LessThan asserts that the value is a number less than n.
+func (b Value) LessThan(n float64) checkly.Assertion {
+	return assertion(b.source, b.property, checkly.LessThan, formatFloat(n))
+}
+
+// Contains asserts that the value contains s.
+func (b Value) Contains(s string) checkly.Assertion {
+	return assertion(b.source, b.property, checkly.Contains, s)
+}
+
+// NotContains asserts that the value doesn't contain s.
+func (b Value) NotContains(s string) checkly.Assertion {
+	return assertion(b.source, b.property, checkly.NotContains, s)
+}
+
+// IsEmpty asserts that the value is empty.
+func (b Value) IsEmpty() checkly.Assertion {
+	return assertion(b.source, b.property, checkly.IsEmpty, "")
+}
+
+// NotEmpty asserts that the value isn't empty.
+func (b Value) NotEmpty() checkly.Assertion {
+	return assertion(b.source, b.property, checkly.NotEmpty, "")
+}
+
+// IsNull asserts that the value is null.
+func (b Value) IsNull() checkly.Assertion {
+	return assertion(b.source, b.property, checkly.IsNull, "")
+}
+
+// NotNull asserts that the value isn't null.
+func (b Value) NotNull() checkly.Assertion {
+	return assertion(b.source, b.property, checkly.NotNull, "")
+}
+
+// StatusCode asserts on the HTTP status code of API checks and URL monitors.
+func StatusCode() Number {
+	return Number{source: checkly.StatusCode}
+}
+
+// ResponseTime asserts on the response time in milliseconds of API checks
+// and TCP, DNS, gRPC and traceroute monitors.
+func ResponseTime() Number {
+	return Number{source: checkly.ResponseTime}
+}
+
+// JSONBody asserts on the value the JSON path selects from the response body
+// of API checks, e.g. "$.data.id".
+func JSONBody(path string) Value {
+	return Value{source: checkly.JSONBody, property: path}
+}
+
+// TextBody asserts on the response body of API checks.
+func TextBody() Text {
+	return Text{source: checkly.TextBody}
+}
+
+// Header asserts on the value of the response header name of API checks.
+func Header(name string) Text {
+	return Text{source: checkly.Headers, property: name}
+}
+
+// ResponseData asserts on the data received by TCP monitors.
+func ResponseData() Text {
+	return Text{source: checkly.ResponseData}
+}
+
+// ResponseCode asserts on the response code of DNS monitors, e.g. "NOERROR"
+// or "NXDOMAIN".
+func ResponseCode() Code {
+	return Code{source: checkly.ResponseCode}
+}
+
+// TextAnswer asserts on the answers of DNS monitors as text, one record per
+// line in the format "name ttl class type data".
+func TextAnswer() Text {
+	return Text{source: checkly.TextAnswer}
+}
+
+// JSONAnswer asserts on the value the JSON path selects from the answers of
+// DNS monitors, e.g. JSONAnswer("$[0].data").
+func JSONAnswer(path string) Value {
+	return Value{source: checkly.JSONAnswer, property: path}
+}
+
+// Latency asserts on a latency statistic in milliseconds of ICMP and
+// traceroute monitors: "avg", "min", "max" or "stdDev".
+func Latency(stat string) Number {
+	return Number{source: checkly.Latency, property: stat}
+}
+
+// Hops asserts on the number of hops to the target of traceroute monitors.
+func Hops() Number {
+	return Number{source: checkly.Hops}
+}
+
+// GRPCStatus asserts on the status code returned to gRPC monitors, 0 for OK.
+func GRPCStatus() Number {
+	return Number{source: checkly.GRPCStatus}
+}
+
+// Certificate asserts on a field of the certificate of SSL monitors, e.g.
+// "daysUntilExpiry" or "issuer".
+func Certificate(field string) Value {
+	return Value{source: checkly.Certificate, property: field}
+}
+
+// Connection asserts on a field of the connection of SSL monitors, e.g.
+// "tlsVersion".
+func Connection(field string) Value {
+	return Value{source: checkly.Connection, property: field}
+}
+
+// JSONResponse asserts on the value the JSON path selects from the result of
+// SSL, DNS, ICMP, gRPC and traceroute monitors, e.g.
+// "$.certificate.keySizeBits".
+func JSONResponse(path string) Value {
+	return Value{source: checkly.JSONResponse, property: path}
+}
+
+// TextResponse asserts on the matches of the regular expression pattern in
+// the serialized result of SSL, gRPC and traceroute monitors.
+func TextResponse(pattern string) Value {
+	return Value{source: checkly.TextResponse, property: pattern}
+}
+
+func assertion(source, property, comparison, target string) checkly.Assertion {
+	return checkly.Assertion{
+		Source:     source,
+		Property:   property,
+		Comparison: comparison,
+		Target:     target,
+	}
+}
+
+func formatFloat(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
@@ -0,0 +1,169 @@
+package assert_test
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	checkly "github.com/checkly/checkly-go-sdk"
+	"github.com/checkly/checkly-go-sdk/assert"
+)
+
+func TestAssertions(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		got  checkly.Assertion
+		want checkly.Assertion
+	}{
+		{
+			"status code",
+			assert.StatusCode().Equals(200),
+			checkly.Assertion{Source: "STATUS_CODE", Comparison: "EQUALS", Target: "200"},
+		},
+		{
+			"response time",
+			assert.ResponseTime().LessThan(500),
+			checkly.Assertion{Source: "RESPONSE_TIME", Comparison: "LESS_THAN", Target: "500"},
+		},
+		{
+			"json body",
+			assert.JSONBody("$.data.id").NotEmpty(),
+			checkly.Assertion{Source: "JSON_BODY", Property: "$.data.id", Comparison: "NOT_EMPTY"},
+		},
+		{
+			"json body boolean",
+			assert.JSONBody("$.ok").Equals(true),
+			checkly.Assertion{Source: "JSON_BODY", Property: "$.ok", Comparison: "EQUALS", Target: "true"},
+		},
+		{
+			"json body fraction",
+			assert.JSONBody("$.ratio").LessThan(0.25),
+			checkly.Assertion{Source: "JSON_BODY", Property: "$.ratio", Comparison: "LESS_THAN", Target: "0.25"},
+		},
+		{
+			"header",
+			assert.Header("Content-Type").Contains("json"),
+			checkly.Assertion{Source: "HEADERS", Property: "Content-Type", Comparison: "CONTAINS", Target: "json"},
+		},
+		{
+			"text body",
+			assert.TextBody().NotContains("error"),
+			checkly.Assertion{Source: "TEXT_BODY", Comparison: "NOT_CONTAINS", Target: "error"},
+		},
+		{
+			"response data",
+			assert.ResponseData().Equals("PONG"),
+			checkly.Assertion{Source: "RESPONSE_DATA", Comparison: "EQUALS", Target: "PONG"},
+		},
+		{
+			"hops",
+			assert.Hops().LessThan(10),
+			checkly.Assertion{Source: "HOPS", Comparison: "LESS_THAN", Target: "10"},
+		},
+		{
+			"text answer",
+			assert.TextAnswer().Contains("IN A"),
+			checkly.Assertion{Source: "TEXT_ANSWER", Comparison: "CONTAINS", Target: "IN A"},
+		},
+		{
+			"json answer",
+			assert.JSONAnswer("$[0].ttl").GreaterThan(60),
+			checkly.Assertion{Source: "JSON_ANSWER", Property: "$[0].ttl", Comparison: "GREATER_THAN", Target: "60"},
+		},
+		{
+			"json response null",
+			assert.JSONResponse("$.error").IsNull(),
+			checkly.Assertion{Source: "JSON_RESPONSE", Property: "$.error", Comparison: "IS_NULL"},
+		},
+	}
+	for _, tc := range tests {
+		if diff := cmp.Diff(tc.want, tc.got); diff != "" {
+			t.Errorf("%s: (-want +got):\n%s", tc.name, diff)
+		}
+	}
+}
+
+// TestAssertionsMatchFixtures builds the assertions of the monitor fixtures,
+// which hold the assertions as the API returns them.
+func TestAssertionsMatchFixtures(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		fixture    string
+		assertions []checkly.Assertion
+	}{
+		{"GetSSLMonitor", []checkly.Assertion{
+			assert.Certificate("daysUntilExpiry").GreaterThan(14),
+			assert.Connection("tlsVersion").Equals("TLSv1.3"),
+			assert.JSONResponse("$.certificate.keySizeBits").GreaterThan(2048),
+			assert.TextResponse("Issuer:.*Let's Encrypt").Contains("true"),
+		}},
+		{"GetDNSMonitor", []checkly.Assertion{assert.ResponseCode().Equals("NOERROR")}},
+		{"GetICMPMonitor", []checkly.Assertion{assert.Latency("avg").LessThan(200)}},
+		{"GetTracerouteMonitor", []checkly.Assertion{assert.Latency("avg").LessThan(200)}},
+		{"GetGRPCMonitor", []checkly.Assertion{assert.GRPCStatus().Equals(0)}},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.fixture, func(t *testing.T) {
+			t.Parallel()
+			data, err := os.ReadFile("../fixtures/" + tc.fixture + ".json")
+			if err != nil {
+				t.Fatal(err)
+			}
+			m, err := checkly.DecodeMonitor(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var fixture struct {
+				Request struct {
+					Assertions []checkly.Assertion `json:"assertions"`
+				} `json:"request"`
+			}
+			if err := json.Unmarshal(data, &fixture); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(fixture.Request.Assertions, tc.assertions); diff != "" {
+				t.Errorf("(-fixture +built):\n%s", diff)
+			}
+			if err := m.Validate(); err != nil {
+				t.Errorf("fixture doesn't validate: %v", err)
+			}
+		})
+	}
+}
+
+func TestAssertionsValidate(t *testing.T) {
+	t.Parallel()
+	check := checkly.Check{
+		Name:      "API",
+		Type:      checkly.TypeAPI,
+		Frequency: 5,
+		Request: checkly.Request{
+			URL: "https://example.com",
+			Assertions: []checkly.Assertion{
+				assert.StatusCode().Equals(200),
+				assert.JSONBody("$.data.id").NotEmpty(),
+				assert.Header("Content-Type").Contains("json"),
+				assert.TextBody().Contains("id"),
+				assert.ResponseTime().LessThan(500),
+			},
+		},
+	}
+	if err := check.Validate(); err != nil {
+		t.Error(err)
+	}
+	monitor := checkly.URLMonitor{
+		Name:      "URL",
+		Frequency: 5,
+		Request: checkly.URLRequest{
+			URL:        "https://example.com",
+			Assertions: []checkly.Assertion{assert.JSONBody("$.data.id").NotEmpty()},
+		},
+	}
+	if !checkly.IsValidationError(monitor.Validate()) {
+		t.Error("want a validation error for a JSON body assertion of a URL monitor")
+	}
+}
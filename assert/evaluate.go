@@ -0,0 +1,504 @@
+package assert
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	checkly "github.com/checkly/checkly-go-sdk"
+)
+
+// Sample is a recorded response of a check run which assertions are evaluated
+// against, see Evaluate. Create one with HTTPSample, TCPSample, DNSSample,
+// SSLSample, GRPCSample or TracerouteSample.
+type Sample struct {
+	// sources returns the value of a source for the property of an
+	// assertion.
+	sources map[string]func(property string) (any, error)
+}
+
+// DNSResponse is the response to the query of a DNS monitor.
+type DNSResponse struct {
+	// ResponseCode is the response code, e.g. "NOERROR" or "NXDOMAIN".
+	ResponseCode string `json:"responseCode"`
+	// Answers are the records of the answer section.
+	Answers []DNSRecord `json:"answers"`
+	// ResponseTime is the time it took to receive the response.
+	ResponseTime time.Duration `json:"-"`
+}
+
+// DNSRecord is a resource record of a DNS response.
+type DNSRecord struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Class string `json:"class"`
+	TTL   uint32 `json:"ttl"`
+	// Data is the data of the record in presentation format, e.g.
+	// "93.184.216.34" for an A record or "10 mail.example.com." for an MX
+	// record.
+	Data string `json:"data"`
+}
+
+// String returns the record in presentation format, e.g.
+// "example.com. 300 IN A 93.184.216.34".
+func (r DNSRecord) String() string {
+	return fmt.Sprintf("%s %d %s %s %s", r.Name, r.TTL, r.Class, r.Type, r.Data)
+}
+
+// HTTPSample returns a sample of the response of an API check or URL monitor,
+// which took responseTime. It reads the body of resp and replaces it, so it
+// can be read again.
+func HTTPSample(resp *http.Response, responseTime time.Duration) (Sample, error) {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return Sample{}, fmt.Errorf("reading response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return Sample{sources: map[string]func(string) (any, error){
+		checkly.StatusCode:   constant(resp.StatusCode),
+		checkly.ResponseTime: constant(milliseconds(responseTime)),
+		checkly.Headers: func(name string) (any, error) {
+			if name == "" {
+				return nil, fmt.Errorf("the header name is required as property")
+			}
+			if values, ok := resp.Header[http.CanonicalHeaderKey(name)]; ok {
+				return strings.Join(values, ", "), nil
+			}
+			return nil, nil
+		},
+		checkly.TextBody: text(string(body)),
+		checkly.JSONBody: jsonText(body),
+	}}, nil
+}
+
+// TCPSample returns a sample of the data received by a TCP monitor, which
+// took responseTime.
+func TCPSample(data string, responseTime time.Duration) Sample {
+	return Sample{sources: map[string]func(string) (any, error){
+		checkly.ResponseData: text(data),
+		checkly.ResponseTime: constant(milliseconds(responseTime)),
+	}}
+}
+
+// DNSSample returns a sample of the response of a DNS monitor. The
+// TEXT_ANSWER source is the answer records in presentation format, one per
+// line, JSON_ANSWER is the list of answer records and JSON_RESPONSE the whole
+// response, both as encoded to JSON.
+func DNSSample(resp DNSResponse) Sample {
+	answers := make([]string, len(resp.Answers))
+	for i, a := range resp.Answers {
+		answers[i] = a.String()
+	}
+	if resp.Answers == nil {
+		resp.Answers = []DNSRecord{}
+	}
+	return Sample{sources: map[string]func(string) (any, error){
+		checkly.ResponseCode: constant(resp.ResponseCode),
+		checkly.ResponseTime: constant(milliseconds(resp.ResponseTime)),
+		checkly.TextAnswer:   text(strings.Join(answers, "\n")),
+		checkly.JSONAnswer:   jsonValue(resp.Answers),
+		checkly.JSONResponse: jsonValue(resp),
+	}}
+}
+
+// SSLSample returns a sample of the result of an SSL monitor. The CERTIFICATE
+// source selects the fields of the certificate of Response, plus
+// daysUntilExpiry, and the CONNECTION source the other fields of Response,
+// with tlsVersion for its protocol. JSON_RESPONSE and TEXT_RESPONSE are
+// Response as encoded to JSON.
+func SSLSample(result *checkly.SSLCheckResult) Sample {
+	resp := result.Response
+	if resp == nil {
+		resp = &checkly.SSLCheckResponse{}
+	}
+	certificate := map[string]any{}
+	for k, v := range resp.Certificate {
+		certificate[k] = v
+	}
+	certificate["daysUntilExpiry"] = resp.DaysUntilExpiry
+	if result.DaysUntilExpiry != nil {
+		certificate["daysUntilExpiry"] = *result.DaysUntilExpiry
+	}
+	connection := map[string]any{
+		"tlsVersion":       firstNonEmpty(resp.Protocol, result.TLSVersion),
+		"cipherSuite":      firstNonEmpty(resp.CipherSuite, result.CipherSuite),
+		"handshakeTimeMs":  resp.HandshakeTimeMs,
+		"hostnameVerified": resp.HostnameVerified,
+		"chainTrusted":     resp.ChainTrusted,
+		"ocspStapled":      resp.OCSPStapled,
+		"resolvedIp":       resp.ResolvedIP,
+	}
+	data, _ := json.Marshal(resp)
+	return Sample{sources: map[string]func(string) (any, error){
+		checkly.Certificate:  field(certificate),
+		checkly.Connection:   field(connection),
+		checkly.JSONResponse: jsonText(data),
+		checkly.TextResponse: text(string(data)),
+	}}
+}
+
+// GRPCSample returns a sample of the result of a gRPC monitor. The response
+// time is the total of its timing phases. JSON_RESPONSE and TEXT_RESPONSE are
+// Response as encoded to JSON.
+func GRPCSample(result *checkly.GRPCCheckResult) Sample {
+	resp := result.Response
+	if resp == nil {
+		resp = &checkly.GRPCCheckResponse{}
+	}
+	status := resp.GRPCStatusCode
+	if result.GRPCStatusCode != nil {
+		status = *result.GRPCStatusCode
+	}
+	data, _ := json.Marshal(resp)
+	return Sample{sources: map[string]func(string) (any, error){
+		checkly.GRPCStatus:   constant(status),
+		checkly.ResponseTime: constant(result.TimingPhases["total"]),
+		checkly.JSONResponse: jsonText(data),
+		checkly.TextResponse: text(string(data)),
+	}}
+}
+
+// TracerouteSample returns a sample of the result of a traceroute monitor.
+// The LATENCY source selects from the latency of the final hop, where "avg",
+// "min" and "max" are its average, best and worst latency. The response time
+// is the total of its timing phases. JSON_RESPONSE and TEXT_RESPONSE are
+// Response as encoded to JSON.
+func TracerouteSample(result *checkly.TracerouteCheckResult) Sample {
+	resp := result.Response
+	if resp == nil {
+		resp = &checkly.TracerouteCheckResponse{}
+	}
+	hops := resp.TotalHops
+	if result.TotalHops != nil {
+		hops = *result.TotalHops
+	}
+	latency := map[string]any{}
+	for k, v := range resp.FinalHopLatency {
+		latency[k] = v
+	}
+	for k, v := range result.FinalHopLatency {
+		latency[k] = v
+	}
+	for stat, key := range map[string]string{"avg": "avgMs", "min": "bestMs", "max": "worstMs", "stdDev": "stdDevMs"} {
+		if _, ok := latency[stat]; !ok {
+			latency[stat] = latency[key]
+		}
+	}
+	data, _ := json.Marshal(resp)
+	return Sample{sources: map[string]func(string) (any, error){
+		checkly.Hops:         constant(hops),
+		checkly.Latency:      field(latency),
+		checkly.ResponseTime: constant(result.TimingPhases["total"]),
+		checkly.JSONResponse: jsonText(data),
+		checkly.TextResponse: text(string(data)),
+	}}
+}
+
+// Result is the outcome of evaluating a single assertion.
+type Result struct {
+	Assertion checkly.Assertion
+	// Actual is the value the assertion selected from the sample, nil if
+	// there is none. Values selected from JSON have the types encoding/json
+	// decodes to.
+	Actual any
+	// Passed reports whether the assertion holds.
+	Passed bool
+	// Err is set when the assertion could not be evaluated, e.g. for a
+	// source the sample doesn't have or an invalid JSON path.
+	Err error
+}
+
+// String describes the result, e.g.
+// `pass STATUS_CODE EQUALS "200" (actual 200)`.
+func (r Result) String() string {
+	status := "pass"
+	if !r.Passed {
+		status = "fail"
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s", status, r.Assertion.Source)
+	if r.Assertion.Property != "" {
+		fmt.Fprintf(&b, " %s", r.Assertion.Property)
+	}
+	fmt.Fprintf(&b, " %s %q", r.Assertion.Comparison, r.Assertion.Target)
+	if r.Err != nil {
+		fmt.Fprintf(&b, ": %v", r.Err)
+	} else {
+		fmt.Fprintf(&b, " (actual %s)", format(r.Actual))
+	}
+	return b.String()
+}
+
+// Results are the outcomes of evaluating assertions, in their order.
+type Results []Result
+
+// Passed reports whether every assertion holds.
+func (rs Results) Passed() bool {
+	for _, r := range rs {
+		if !r.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// Failed returns the results of the assertions which don't hold.
+func (rs Results) Failed() Results {
+	var failed Results
+	for _, r := range rs {
+		if !r.Passed {
+			failed = append(failed, r)
+		}
+	}
+	return failed
+}
+
+// Evaluate evaluates assertions against sample, the way a check run does.
+// The property of JSON sources is a JSONPath expression selecting the value
+// to compare, see JSONBody. The property of text sources is an optional
+// regular expression, which selects its first capturing group, or the whole
+// match if it has none, and nothing if it doesn't match. Numeric comparisons
+// compare numbers, other comparisons the values as text; CONTAINS of a list
+// of values holds if one of them equals the target.
+func Evaluate(assertions []checkly.Assertion, sample Sample) Results {
+	results := make(Results, len(assertions))
+	for i, a := range assertions {
+		results[i] = evaluate(a, sample)
+	}
+	return results
+}
+
+func evaluate(a checkly.Assertion, sample Sample) Result {
+	r := Result{Assertion: a}
+	source, ok := sample.sources[a.Source]
+	if !ok {
+		r.Err = fmt.Errorf("the sample has no %s source", a.Source)
+		return r
+	}
+	r.Actual, r.Err = source(a.Property)
+	if r.Err == nil {
+		r.Passed, r.Err = compare(a.Comparison, r.Actual, a.Target)
+	}
+	return r
+}
+
+func compare(comparison string, actual any, target string) (bool, error) {
+	switch comparison {
+	case checkly.Equals:
+		return equals(actual, target), nil
+	case checkly.NotEquals:
+		return !equals(actual, target), nil
+	case checkly.Contains:
+		return contains(actual, target), nil
+	case checkly.NotContains:
+		return !contains(actual, target), nil
+	case checkly.IsEmpty:
+		return isEmpty(actual), nil
+	case checkly.NotEmpty:
+		return !isEmpty(actual), nil
+	case checkly.IsNull:
+		return actual == nil, nil
+	case checkly.NotNull:
+		return actual != nil, nil
+	case checkly.GreaterThan, checkly.LessThan:
+		want, err := strconv.ParseFloat(strings.TrimSpace(target), 64)
+		if err != nil {
+			return false, fmt.Errorf("target %q is not a number", target)
+		}
+		got, ok := number(actual)
+		if !ok {
+			return false, nil
+		}
+		if comparison == checkly.GreaterThan {
+			return got > want, nil
+		}
+		return got < want, nil
+	default:
+		return false, fmt.Errorf("unknown comparison %q", comparison)
+	}
+}
+
+func equals(actual any, target string) bool {
+	if actual == nil {
+		return false
+	}
+	if got, ok := number(actual); ok {
+		if want, err := strconv.ParseFloat(strings.TrimSpace(target), 64); err == nil {
+			return got == want
+		}
+	}
+	return format(actual) == target
+}
+
+func contains(actual any, target string) bool {
+	switch actual := actual.(type) {
+	case nil:
+		return false
+	case []any:
+		for _, v := range actual {
+			if equals(v, target) {
+				return true
+			}
+		}
+		return false
+	default:
+		return strings.Contains(format(actual), target)
+	}
+}
+
+func isEmpty(actual any) bool {
+	switch actual := actual.(type) {
+	case nil:
+		return true
+	case string:
+		return actual == ""
+	case []any:
+		return len(actual) == 0
+	case map[string]any:
+		return len(actual) == 0
+	default:
+		return false
+	}
+}
+
+// number returns v as a number, if it is one or a string holding one.
+func number(v any) (float64, bool) {
+	switch v := v.(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case float64:
+		return v, true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return f, err == nil && !math.IsNaN(f)
+	default:
+		return 0, false
+	}
+}
+
+// format returns v as text: strings as they are, numbers in their shortest
+// form and other values as JSON.
+func format(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	}
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// constant is a source with a single value, which ignores the property.
+func constant(v any) func(string) (any, error) {
+	return func(string) (any, error) {
+		return v, nil
+	}
+}
+
+// text is a text source, whose property is an optional regular expression.
+func text(s string) func(string) (any, error) {
+	return func(pattern string) (any, error) {
+		if pattern == "" {
+			return s, nil
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression: %w", err)
+		}
+		match := re.FindStringSubmatch(s)
+		switch {
+		case match == nil:
+			return nil, nil
+		case len(match) > 1:
+			return match[1], nil
+		default:
+			return match[0], nil
+		}
+	}
+}
+
+// jsonText is a source with the JSON document data, whose property is a
+// JSONPath expression.
+func jsonText(data []byte) func(string) (any, error) {
+	return func(path string) (any, error) {
+		var doc any
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("the response is not JSON: %w", err)
+		}
+		return selectPath(doc, path)
+	}
+}
+
+// jsonValue is a source with v as encoded to JSON, whose property is a
+// JSONPath expression.
+func jsonValue(v any) func(string) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return func(string) (any, error) {
+			return nil, err
+		}
+	}
+	return jsonText(data)
+}
+
+func selectPath(doc any, path string) (any, error) {
+	if path == "" {
+		return doc, nil
+	}
+	p, err := parseJSONPath(path)
+	if err != nil {
+		return nil, err
+	}
+	v, _ := p.selectFrom(doc)
+	return v, nil
+}
+
+// field is a source selecting the field named by the property from fields.
+func field(fields map[string]any) func(string) (any, error) {
+	return func(name string) (any, error) {
+		if name == "" {
+			return nil, fmt.Errorf("the field name is required as property")
+		}
+		return fields[name], nil
+	}
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
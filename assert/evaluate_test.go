@@ -0,0 +1,189 @@
+package assert_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	checkly "github.com/checkly/checkly-go-sdk"
+	"github.com/checkly/checkly-go-sdk/assert"
+)
+
+func httpSample(t *testing.T) assert.Sample {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	rec.Header().Set("Content-Type", "application/json; charset=utf-8")
+	rec.WriteHeader(201)
+	rec.WriteString(`{
+		"data": {"id": 42, "name": "Alice", "email": null, "ratio": 0.5, "active": true},
+		"items": [{"sku": "a-1", "price": 10}, {"sku": "b-2", "price": 25}],
+		"tags": ["new", "sale"],
+		"version": "v1.2.3"
+	}`)
+	resp := rec.Result()
+	sample, err := assert.HTTPSample(resp, 230*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The body can still be read after sampling.
+	if body, err := io.ReadAll(resp.Body); err != nil || len(body) == 0 {
+		t.Fatalf("body not restored: %q, %v", body, err)
+	}
+	return sample
+}
+
+func checkResult(t *testing.T, fixture string) checkly.CheckResult {
+	t.Helper()
+	data, err := os.ReadFile("../fixtures/" + fixture + ".json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result checkly.CheckResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func TestEvaluate(t *testing.T) {
+	t.Parallel()
+	api := httpSample(t)
+	tcp := assert.TCPSample("220 mail.example.com ESMTP ready\r\n", 12*time.Millisecond)
+	dns := assert.DNSSample(assert.DNSResponse{
+		ResponseCode: "NOERROR",
+		Answers: []assert.DNSRecord{
+			{Name: "example.com.", Type: "A", Class: "IN", TTL: 300, Data: "93.184.216.34"},
+			{Name: "example.com.", Type: "A", Class: "IN", TTL: 300, Data: "93.184.216.35"},
+		},
+		ResponseTime: 8 * time.Millisecond,
+	})
+	ssl := assert.SSLSample(checkResult(t, "GetSSLCheckResult").SSLCheckResult)
+	grpc := assert.GRPCSample(checkResult(t, "GetGRPCCheckResult").GRPCCheckResult)
+	traceroute := assert.TracerouteSample(checkResult(t, "GetTracerouteCheckResult").TracerouteCheckResult)
+
+	tests := []struct {
+		name      string
+		sample    assert.Sample
+		assertion checkly.Assertion
+		actual    any
+		passed    bool
+	}{
+		{"status code", api, assert.StatusCode().Equals(201), 201, true},
+		{"status code mismatch", api, assert.StatusCode().Equals(200), 201, false},
+		{"status code not equals", api, assert.StatusCode().NotEquals(500), 201, true},
+		{"response time", api, assert.ResponseTime().LessThan(500), 230.0, true},
+		{"response time slow", api, assert.ResponseTime().LessThan(100), 230.0, false},
+		{"header", api, assert.Header("content-type").Contains("json"), "application/json; charset=utf-8", true},
+		{"missing header", api, assert.Header("X-Request-Id").IsEmpty(), nil, true},
+		{"text body", api, assert.TextBody().Contains(`"Alice"`), nil, true},
+		{"text body regex", api, checkly.Assertion{Source: "TEXT_BODY", Property: `"version": "v(\d+)`, Comparison: "EQUALS", Target: "1"}, "1", true},
+		{"text body regex no match", api, checkly.Assertion{Source: "TEXT_BODY", Property: `build-\d+`, Comparison: "IS_NULL"}, nil, true},
+		{"json number", api, assert.JSONBody("$.data.id").Equals(42), 42.0, true},
+		{"json number as text", api, assert.JSONBody("$.data.id").Equals("42.0"), 42.0, true},
+		{"json not empty", api, assert.JSONBody("$.data.name").NotEmpty(), "Alice", true},
+		{"json null", api, assert.JSONBody("$.data.email").IsNull(), nil, true},
+		{"json missing", api, assert.JSONBody("$.data.phone").NotNull(), nil, false},
+		{"json boolean", api, assert.JSONBody("$.data.active").Equals(true), true, true},
+		{"json fraction", api, assert.JSONBody("$.data.ratio").GreaterThan(0.25), 0.5, true},
+		{"json without root", api, assert.JSONBody("data.name").Equals("Alice"), "Alice", true},
+		{"json bracket member", api, assert.JSONBody("$['data']['name']").Equals("Alice"), "Alice", true},
+		{"json index", api, assert.JSONBody("$.items[1].sku").Equals("b-2"), "b-2", true},
+		{"json negative index", api, assert.JSONBody("$.items[-1].price").LessThan(30), 25.0, true},
+		{"json length", api, assert.JSONBody("$.items.length").Equals(2), 2.0, true},
+		{"json wildcard", api, assert.JSONBody("$.items[*].sku").Contains("a-1"), []any{"a-1", "b-2"}, true},
+		{"json recursive", api, assert.JSONBody("$..price").NotContains("99"), []any{10.0, 25.0}, true},
+		{"json array contains", api, assert.JSONBody("$.tags").Contains("sale"), []any{"new", "sale"}, true},
+		{"json array not contains", api, assert.JSONBody("$.tags").Contains("sal"), []any{"new", "sale"}, false},
+		{"json text contains", api, assert.JSONBody("$.version").Contains("1.2"), "v1.2.3", true},
+		{"json text not number", api, assert.JSONBody("$.version").GreaterThan(1), "v1.2.3", false},
+		{"response data", tcp, assert.ResponseData().Contains("ESMTP"), "220 mail.example.com ESMTP ready\r\n", true},
+		{"response data regex", tcp, checkly.Assertion{Source: "RESPONSE_DATA", Property: `^(\d{3})`, Comparison: "EQUALS", Target: "220"}, "220", true},
+		{"tcp response time", tcp, assert.ResponseTime().LessThan(10), 12.0, false},
+		{"dns response code", dns, assert.ResponseCode().Equals("NOERROR"), "NOERROR", true},
+		{"dns text answer", dns, assert.TextAnswer().Contains("IN A 93.184.216.35"), nil, true},
+		{"dns json answer", dns, assert.JSONAnswer("$[0].ttl").GreaterThan(60), 300.0, true},
+		{"dns json response", dns, assert.JSONResponse("$.answers[*].data").Contains("93.184.216.34"), []any{"93.184.216.34", "93.184.216.35"}, true},
+		{"ssl certificate", ssl, assert.Certificate("daysUntilExpiry").GreaterThan(7), -3, false},
+		{"ssl certificate field", ssl, assert.Certificate("issuer").Equals("Example CA"), "Example CA", true},
+		{"ssl connection", ssl, assert.Connection("tlsVersion").Equals("TLS 1.3"), "TLS 1.3", true},
+		{"ssl json response", ssl, assert.JSONResponse("$.securityBaseline.grade").Equals("A"), "C", false},
+		{"ssl text response", ssl, assert.TextResponse(`"issuer":"([^"]+)"`).Equals("Example CA"), "Example CA", true},
+		{"grpc status", grpc, assert.GRPCStatus().Equals(0), 14, false},
+		{"grpc response time", grpc, assert.ResponseTime().LessThan(100), 90.0, true},
+		{"grpc json response", grpc, assert.JSONResponse("$.healthStatusLabel").Equals("NOT_SERVING"), "NOT_SERVING", true},
+		{"traceroute hops", traceroute, assert.Hops().LessThan(10), 12, false},
+		{"traceroute latency", traceroute, assert.Latency("avg").LessThan(30), 24.1, true},
+		{"traceroute latency max", traceroute, assert.Latency("max").LessThan(30), 31.4, false},
+		{"traceroute json response", traceroute, assert.JSONResponse("$.hops[0].address").Equals("10.0.0.1"), "10.0.0.1", true},
+	}
+	for _, tc := range tests {
+		r := assert.Evaluate([]checkly.Assertion{tc.assertion}, tc.sample)[0]
+		if r.Err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.name, r.Err)
+			continue
+		}
+		if r.Passed != tc.passed {
+			t.Errorf("%s: want passed %v, got %v", tc.name, tc.passed, r)
+		}
+		if tc.actual != nil || r.Actual == nil {
+			got, _ := json.Marshal(r.Actual)
+			want, _ := json.Marshal(tc.actual)
+			if string(got) != string(want) {
+				t.Errorf("%s: want actual %s, got %s", tc.name, want, got)
+			}
+		}
+	}
+}
+
+func TestEvaluateErrors(t *testing.T) {
+	t.Parallel()
+	tcp := assert.TCPSample("PONG", time.Millisecond)
+	tests := []struct {
+		name      string
+		assertion checkly.Assertion
+	}{
+		{"unsupported source", assert.StatusCode().Equals(200)},
+		{"unknown comparison", checkly.Assertion{Source: "RESPONSE_DATA", Comparison: "MATCHES", Target: "PONG"}},
+		{"invalid regular expression", checkly.Assertion{Source: "RESPONSE_DATA", Property: "(", Comparison: "NOT_NULL"}},
+		{"non-numeric target", checkly.Assertion{Source: "RESPONSE_TIME", Comparison: "LESS_THAN", Target: "fast"}},
+	}
+	for _, tc := range tests {
+		r := assert.Evaluate([]checkly.Assertion{tc.assertion}, tcp)[0]
+		if r.Err == nil || r.Passed {
+			t.Errorf("%s: want an error, got %v", tc.name, r)
+		}
+	}
+
+	api := httpSample(t)
+	for _, path := range []string{"$.", "$.items[x]", "$.items[0", "$data"} {
+		r := assert.Evaluate([]checkly.Assertion{assert.JSONBody(path).NotNull()}, api)[0]
+		if r.Err == nil {
+			t.Errorf("%s: want an error for an invalid JSON path", path)
+		}
+	}
+}
+
+func TestResults(t *testing.T) {
+	t.Parallel()
+	results := assert.Evaluate([]checkly.Assertion{
+		assert.StatusCode().Equals(201),
+		assert.StatusCode().Equals(200),
+		assert.JSONBody("$.data.name").Equals("Alice"),
+	}, httpSample(t))
+	if results.Passed() {
+		t.Error("want results to fail")
+	}
+	failed := results.Failed()
+	if len(failed) != 1 {
+		t.Fatalf("want 1 failed result, got %v", failed)
+	}
+	if got, want := failed[0].String(), `fail STATUS_CODE EQUALS "200" (actual 201)`; got != want {
+		t.Errorf("want %s, got %s", want, got)
+	}
+	if got, want := results[2].String(), `pass JSON_BODY $.data.name EQUALS "Alice" (actual Alice)`; got != want {
+		t.Errorf("want %s, got %s", want, got)
+	}
+}
@@ -0,0 +1,170 @@
+package assert
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// jsonPath is a compiled JSONPath expression. It supports the subset used by
+// assertions: the root "$", child members as ".name" or "['name']", array
+// indexes as "[0]" or "[-1]" counting from the end, the wildcards ".*" and
+// "[*]", and recursive descent as "..name". The "length" member of an array
+// is its number of elements, like in the JavaScript the checks run in.
+type jsonPath struct {
+	steps []pathStep
+	// multiple reports whether the path can select several values, in which
+	// case its value is the list of the values selected.
+	multiple bool
+}
+
+type pathStep struct {
+	name      string
+	index     int
+	isIndex   bool
+	wildcard  bool
+	recursive bool
+}
+
+func parseJSONPath(path string) (*jsonPath, error) {
+	s := strings.TrimSpace(path)
+	if s == "" {
+		return nil, fmt.Errorf("empty JSON path")
+	}
+	// Paths without the root, like "data.id", are relative to it.
+	if s[0] == '$' {
+		s = s[1:]
+	} else if s[0] != '.' && s[0] != '[' {
+		s = "." + s
+	}
+	p := &jsonPath{}
+	for s != "" {
+		var step pathStep
+		if strings.HasPrefix(s, "..") {
+			// Recursive descent applies to the member or bracket which
+			// follows, as in "..name" or "..[0]".
+			step.recursive = true
+			s = s[1:]
+			if strings.HasPrefix(s, ".[") {
+				s = s[1:]
+			}
+		}
+		switch s[0] {
+		case '.':
+			end := strings.IndexAny(s[1:], ".[") + 1
+			if end == 0 {
+				end = len(s)
+			}
+			step.name, s = s[1:end], s[end:]
+			switch step.name {
+			case "":
+				return nil, fmt.Errorf("invalid JSON path %q: empty member name", path)
+			case "*":
+				step.name, step.wildcard = "", true
+			}
+		case '[':
+			end := strings.IndexByte(s, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("invalid JSON path %q: missing ]", path)
+			}
+			inner := strings.TrimSpace(s[1:end])
+			s = s[end+1:]
+			switch {
+			case inner == "*":
+				step.wildcard = true
+			case len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0]:
+				step.name = inner[1 : len(inner)-1]
+			default:
+				i, err := strconv.Atoi(inner)
+				if err != nil {
+					return nil, fmt.Errorf("invalid JSON path %q: invalid index %q", path, inner)
+				}
+				step.index, step.isIndex = i, true
+			}
+		default:
+			return nil, fmt.Errorf("invalid JSON path %q: unexpected %q", path, s[0])
+		}
+		p.multiple = p.multiple || step.wildcard || step.recursive
+		p.steps = append(p.steps, step)
+	}
+	return p, nil
+}
+
+// selectFrom returns the value the path selects from doc, a decoded JSON
+// document, and whether it exists. Paths which can select several values
+// always exist and return a []any.
+func (p *jsonPath) selectFrom(doc any) (any, bool) {
+	values := []any{doc}
+	for _, step := range p.steps {
+		var next []any
+		for _, v := range values {
+			if step.recursive {
+				for _, d := range descendants(v) {
+					next = append(next, step.apply(d)...)
+				}
+			} else {
+				next = append(next, step.apply(v)...)
+			}
+		}
+		values = next
+	}
+	if p.multiple {
+		if values == nil {
+			values = []any{}
+		}
+		return values, true
+	}
+	if len(values) == 0 {
+		return nil, false
+	}
+	return values[0], true
+}
+
+// apply returns the values the step selects from v.
+func (s pathStep) apply(v any) []any {
+	switch v := v.(type) {
+	case map[string]any:
+		if s.wildcard {
+			values := make([]any, 0, len(v))
+			for _, k := range sortedKeys(v) {
+				values = append(values, v[k])
+			}
+			return values
+		}
+		if child, ok := v[s.name]; ok && !s.isIndex {
+			return []any{child}
+		}
+	case []any:
+		switch {
+		case s.wildcard:
+			return v
+		case s.isIndex:
+			i := s.index
+			if i < 0 {
+				i += len(v)
+			}
+			if i >= 0 && i < len(v) {
+				return []any{v[i]}
+			}
+		case s.name == "length":
+			return []any{float64(len(v))}
+		}
+	}
+	return nil
+}
+
+// descendants returns v and every value nested in it, in document order.
+func descendants(v any) []any {
+	values := []any{v}
+	switch v := v.(type) {
+	case map[string]any:
+		for _, k := range sortedKeys(v) {
+			values = append(values, descendants(v[k])...)
+		}
+	case []any:
+		for _, e := range v {
+			values = append(values, descendants(e)...)
+		}
+	}
+	return values
+}
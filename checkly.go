@@ -13,6 +13,7 @@ import (
 	"net/netip"
 	"net/url"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -25,7 +26,8 @@ func getEnv(key, fallback string) string {
 	return fallback
 }
 
-// NewClient constructs a Checkly API client.
+// NewClient constructs a Checkly API client. It is equivalent to calling New
+// with WithBaseURL, WithAPIKey, WithHTTPClient and WithDebug.
 func NewClient(
 	//checkly API's base url
 	baseURL,
@@ -35,18 +37,12 @@ func NewClient(
 	httpClient *http.Client,
 	debug io.Writer,
 ) Client {
-	c := &client{
-		apiKey:     apiKey,
-		url:        baseURL,
-		httpClient: httpClient,
-		debug:      debug,
-	}
-	if httpClient != nil {
-		c.httpClient = httpClient
-	} else {
-		c.httpClient = http.DefaultClient
-	}
-	return c
+	return New(
+		WithBaseURL(baseURL),
+		WithAPIKey(apiKey),
+		WithHTTPClient(httpClient),
+		WithDebug(debug),
+	)
 }
 
 // SetAccountId sets ID on a client which is required when using User API keys.
@@ -59,6 +55,18 @@ func (c *client) SetChecklySource(source string) {
 	c.source = source
 }
 
+// SetRetryPolicy sets the policy used to retry failed API requests. The zero
+// RetryPolicy, which is the default, disables retries.
+func (c *client) SetRetryPolicy(policy RetryPolicy) {
+	c.retryPolicy = policy
+}
+
+// SetRateLimiter sets a rate limiter that every API request waits on. Pass
+// nil, the default, to disable client-side rate limiting.
+func (c *client) SetRateLimiter(limiter *RateLimiter) {
+	c.rateLimiter = limiter
+}
+
 // Create creates a new check with the specified details. It returns the
 // newly-created check, or an error.
 //
@@ -131,34 +139,10 @@ func (c *client) CreateCheck(
 	ctx context.Context,
 	check Check,
 ) (*Check, error) {
-	var endpoint string
-	switch check.Type {
-	case "BROWSER":
-		endpoint = "checks/browser"
-	case "API":
-		endpoint = "checks/api"
-	case "HEARTBEAT":
-		endpoint = "checks/heartbeat"
-	case "MULTI_STEP":
-		endpoint = "checks/multistep"
-	case "TCP":
-		return nil, fmt.Errorf("user error: use CreateTCPMonitor to create TCP monitors")
-	case "URL":
-		return nil, fmt.Errorf("user error: use CreateURLMonitor to create URL monitors")
-	case "DNS":
-		return nil, fmt.Errorf("user error: use CreateDNSMonitor to create DNS monitors")
-	case "ICMP":
-		return nil, fmt.Errorf("user error: use CreateICMPMonitor to create ICMP monitors")
-	case "GRPC":
-		return nil, fmt.Errorf("user error: use CreateGRPCMonitor to create GRPC monitors")
-	case "TRACEROUTE":
-		return nil, fmt.Errorf("user error: use CreateTracerouteMonitor to create TRACEROUTE monitors")
-	case "SSL":
-		return nil, fmt.Errorf("user error: use CreateSSLMonitor to create SSL monitors")
-	default:
-		return nil, fmt.Errorf("unknown check type: %s", check.Type)
+	if err := CreateCheckError(check.Type); err != nil {
+		return nil, err
 	}
-	return c.createCheck(ctx, check, endpoint)
+	return c.createCheck(ctx, check, checkTypes[check.Type].endpoint)
 }
 
 type checkPayload struct {
@@ -194,6 +178,9 @@ func (c *client) createCheck(
 	check Check,
 	endpoint string,
 ) (*Check, error) {
+	if err := c.validateMonitor(&check); err != nil {
+		return nil, err
+	}
 	payload := createCheckPayload(check)
 	data, err := json.Marshal(payload)
 	if err != nil {
@@ -230,6 +217,9 @@ func (c *client) CreateHeartbeatMonitor(
 	ctx context.Context,
 	monitor HeartbeatMonitor,
 ) (*HeartbeatMonitor, error) {
+	if err := c.validateMonitor(&monitor); err != nil {
+		return nil, err
+	}
 	data, err := json.Marshal(monitor)
 	if err != nil {
 		return nil, err
@@ -272,7 +262,7 @@ func createTCPMonitorPayload(monitor TCPMonitor) tcpMonitorPayload {
 	payload := tcpMonitorPayload{
 		TCPMonitor: monitor,
 		// Unfortunately `checkType` is required for this endpoint.
-		Type: "TCP",
+		Type: TypeTCP,
 		// Unfortunately, this will default to true if not set.
 		DoubleCheck: false,
 	}
@@ -299,6 +289,9 @@ func (c *client) CreateTCPMonitor(
 	ctx context.Context,
 	monitor TCPMonitor,
 ) (*TCPMonitor, error) {
+	if err := c.validateMonitor(&monitor); err != nil {
+		return nil, err
+	}
 	payload := createTCPMonitorPayload(monitor)
 	data, err := json.Marshal(payload)
 	if err != nil {
@@ -334,7 +327,7 @@ func createGRPCMonitorPayload(monitor GRPCMonitor) grpcMonitorPayload {
 	payload := grpcMonitorPayload{
 		GRPCMonitor: monitor,
 		// Unfortunately `checkType` is required for this endpoint.
-		Type: "GRPC",
+		Type: TypeGRPC,
 		// Unfortunately, this will default to true if not set.
 		DoubleCheck: false,
 	}
@@ -361,6 +354,9 @@ func (c *client) CreateGRPCMonitor(
 	ctx context.Context,
 	monitor GRPCMonitor,
 ) (*GRPCMonitor, error) {
+	if err := c.validateMonitor(&monitor); err != nil {
+		return nil, err
+	}
 	payload := createGRPCMonitorPayload(monitor)
 	data, err := json.Marshal(payload)
 	if err != nil {
@@ -396,7 +392,7 @@ func createTracerouteMonitorPayload(monitor TracerouteMonitor) tracerouteMonitor
 	payload := tracerouteMonitorPayload{
 		TracerouteMonitor: monitor,
 		// Unfortunately `checkType` is required for this endpoint.
-		Type: "TRACEROUTE",
+		Type: TypeTraceroute,
 		// Unfortunately, this will default to true if not set.
 		DoubleCheck: false,
 	}
@@ -424,6 +420,9 @@ func (c *client) CreateTracerouteMonitor(
 	ctx context.Context,
 	monitor TracerouteMonitor,
 ) (*TracerouteMonitor, error) {
+	if err := c.validateMonitor(&monitor); err != nil {
+		return nil, err
+	}
 	payload := createTracerouteMonitorPayload(monitor)
 	data, err := json.Marshal(payload)
 	if err != nil {
@@ -459,7 +458,7 @@ func createSSLMonitorPayload(monitor SSLMonitor) sslMonitorPayload {
 	payload := sslMonitorPayload{
 		SSLMonitor: monitor,
 		// Unfortunately `checkType` is required for this endpoint.
-		Type: "SSL",
+		Type: TypeSSL,
 		// Unfortunately, this will default to true if not set.
 		DoubleCheck: false,
 	}
@@ -486,6 +485,9 @@ func (c *client) CreateSSLMonitor(
 	ctx context.Context,
 	monitor SSLMonitor,
 ) (*SSLMonitor, error) {
+	if err := c.validateMonitor(&monitor); err != nil {
+		return nil, err
+	}
 	payload := createSSLMonitorPayload(monitor)
 	data, err := json.Marshal(payload)
 	if err != nil {
@@ -522,7 +524,7 @@ func createURLMonitorPayload(monitor URLMonitor) urlMonitorPayload {
 	payload := urlMonitorPayload{
 		URLMonitor: monitor,
 		// Unfortunately `checkType` is required for this endpoint.
-		Type:    "URL",
+		Type:    TypeURL,
 		Request: monitor.Request.toRequest(),
 		// Unfortunately, this will default to true if not set.
 		DoubleCheck: false,
@@ -550,6 +552,9 @@ func (c *client) CreateURLMonitor(
 	ctx context.Context,
 	monitor URLMonitor,
 ) (*URLMonitor, error) {
+	if err := c.validateMonitor(&monitor); err != nil {
+		return nil, err
+	}
 	payload := createURLMonitorPayload(monitor)
 	data, err := json.Marshal(payload)
 	if err != nil {
@@ -589,7 +594,7 @@ func createDNSMonitorPayload(monitor DNSMonitor) dnsMonitorPayload {
 	payload := dnsMonitorPayload{
 		DNSMonitor: monitor,
 		// Unfortunately `checkType` is required for this endpoint.
-		Type: "DNS",
+		Type: TypeDNS,
 		// Unfortunately, this will default to true if not set.
 		DoubleCheck: false,
 		// Unfortunately, this will default to true if not set.
@@ -614,6 +619,9 @@ func (c *client) CreateDNSMonitor(
 	ctx context.Context,
 	monitor DNSMonitor,
 ) (*DNSMonitor, error) {
+	if err := c.validateMonitor(&monitor); err != nil {
+		return nil, err
+	}
 	payload := createDNSMonitorPayload(monitor)
 	data, err := json.Marshal(payload)
 	if err != nil {
@@ -653,7 +661,7 @@ func createICMPMonitorPayload(monitor ICMPMonitor) icmpMonitorPayload {
 	payload := icmpMonitorPayload{
 		ICMPMonitor: monitor,
 		// Unfortunately `checkType` is required for this endpoint.
-		Type: "ICMP",
+		Type: TypeICMP,
 		// Unfortunately, this will default to true if not set.
 		DoubleCheck: false,
 		// Unfortunately, this will default to true if not set.
@@ -678,6 +686,9 @@ func (c *client) CreateICMPMonitor(
 	ctx context.Context,
 	monitor ICMPMonitor,
 ) (*ICMPMonitor, error) {
+	if err := c.validateMonitor(&monitor); err != nil {
+		return nil, err
+	}
 	payload := createICMPMonitorPayload(monitor)
 	data, err := json.Marshal(payload)
 	if err != nil {
@@ -714,7 +725,7 @@ func createPlaywrightCheckPayload(check PlaywrightCheck) playwrightCheckPayload
 	payload := playwrightCheckPayload{
 		PlaywrightCheck: check,
 		// Unfortunately `checkType` is required for this endpoint.
-		Type: "PLAYWRIGHT",
+		Type: TypePlaywright,
 	}
 
 	if payload.Browsers == nil {
@@ -728,6 +739,9 @@ func (c *client) CreatePlaywrightCheck(
 	ctx context.Context,
 	check PlaywrightCheck,
 ) (*PlaywrightCheck, error) {
+	if err := c.validateMonitor(&check); err != nil {
+		return nil, err
+	}
 	payload := createPlaywrightCheckPayload(check)
 	data, err := json.Marshal(payload)
 	if err != nil {
@@ -752,6 +766,93 @@ func (c *client) CreatePlaywrightCheck(
 	return &result, nil
 }
 
+type multiStepCheckPayload struct {
+	MultiStepCheck
+	GroupID *int64 `json:"groupId"`
+}
+
+func createMultiStepCheckPayload(check MultiStepCheck) multiStepCheckPayload {
+	payload := multiStepCheckPayload{
+		MultiStepCheck: check,
+	}
+	// Unfortunately `checkType` is required for this endpoint.
+	payload.Type = TypeMultiStep
+
+	// GroupID must be null if empty or the group will not get unset on update.
+	if check.GroupID != 0 {
+		payload.GroupID = &check.GroupID
+	}
+
+	// A nil value for a list will cause the backend to not update the value.
+	// We must send empty lists instead.
+	if check.Locations == nil {
+		payload.Locations = []string{}
+	}
+
+	if check.PrivateLocations == nil {
+		payload.PrivateLocations = &[]string{}
+	}
+
+	return payload
+}
+
+// validateMultiStepRuntime returns an error if the runtime set on check does
+// not support multistep checks, when the client validates checks, see
+// WithValidation. Checks without a runtime use the account's default runtime
+// and are left for the API to validate.
+func (c *client) validateMultiStepRuntime(
+	ctx context.Context,
+	check MultiStepCheck,
+) error {
+	if !c.validate || check.RuntimeID == nil {
+		return nil
+	}
+	runtime, err := c.GetRuntime(ctx, *check.RuntimeID)
+	if err != nil {
+		return err
+	}
+	if !runtime.MultiStepSupport {
+		return fmt.Errorf("user error: runtime %s does not support multistep checks", *check.RuntimeID)
+	}
+	return nil
+}
+
+// CreateMultiStepCheck creates a new multistep check with the specified
+// details. It returns the newly-created check, or an error.
+func (c *client) CreateMultiStepCheck(
+	ctx context.Context,
+	check MultiStepCheck,
+) (*MultiStepCheck, error) {
+	if err := c.validateMonitor(&check); err != nil {
+		return nil, err
+	}
+	if err := c.validateMultiStepRuntime(ctx, check); err != nil {
+		return nil, err
+	}
+	payload := createMultiStepCheckPayload(check)
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	status, res, err := c.apiCall(
+		ctx,
+		http.MethodPost,
+		withAutoAssignAlertsFlag("checks/multistep"),
+		data,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusCreated {
+		return nil, fmt.Errorf("unexpected response status %d: %q", status, res)
+	}
+	var result MultiStepCheck
+	if err = json.NewDecoder(strings.NewReader(res)).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decoding error for data %s: %v", res, err)
+	}
+	return &result, nil
+}
+
 // Update updates an existing check with the specified details. It returns the
 // updated check, or an error.
 func (c *client) UpdateCheck(
@@ -759,6 +860,9 @@ func (c *client) UpdateCheck(
 	ID string,
 	check Check,
 ) (*Check, error) {
+	if err := c.validateMonitor(&check); err != nil {
+		return nil, err
+	}
 	payload := createCheckPayload(check)
 	data, err := json.Marshal(payload)
 	if err != nil {
@@ -797,6 +901,9 @@ func (c *client) UpdateHeartbeatMonitor(
 	ID string,
 	monitor HeartbeatMonitor,
 ) (*HeartbeatMonitor, error) {
+	if err := c.validateMonitor(&monitor); err != nil {
+		return nil, err
+	}
 	data, err := json.Marshal(monitor)
 	if err != nil {
 		return nil, err
@@ -835,6 +942,9 @@ func (c *client) UpdateTCPMonitor(
 	ID string,
 	monitor TCPMonitor,
 ) (*TCPMonitor, error) {
+	if err := c.validateMonitor(&monitor); err != nil {
+		return nil, err
+	}
 	payload := createTCPMonitorPayload(monitor)
 	data, err := json.Marshal(payload)
 	if err != nil {
@@ -865,6 +975,9 @@ func (c *client) UpdateGRPCMonitor(
 	ID string,
 	monitor GRPCMonitor,
 ) (*GRPCMonitor, error) {
+	if err := c.validateMonitor(&monitor); err != nil {
+		return nil, err
+	}
 	payload := createGRPCMonitorPayload(monitor)
 	data, err := json.Marshal(payload)
 	if err != nil {
@@ -895,6 +1008,9 @@ func (c *client) UpdateTracerouteMonitor(
 	ID string,
 	monitor TracerouteMonitor,
 ) (*TracerouteMonitor, error) {
+	if err := c.validateMonitor(&monitor); err != nil {
+		return nil, err
+	}
 	payload := createTracerouteMonitorPayload(monitor)
 	data, err := json.Marshal(payload)
 	if err != nil {
@@ -925,6 +1041,9 @@ func (c *client) UpdateSSLMonitor(
 	ID string,
 	monitor SSLMonitor,
 ) (*SSLMonitor, error) {
+	if err := c.validateMonitor(&monitor); err != nil {
+		return nil, err
+	}
 	payload := createSSLMonitorPayload(monitor)
 	data, err := json.Marshal(payload)
 	if err != nil {
@@ -955,6 +1074,9 @@ func (c *client) UpdateURLMonitor(
 	ID string,
 	monitor URLMonitor,
 ) (*URLMonitor, error) {
+	if err := c.validateMonitor(&monitor); err != nil {
+		return nil, err
+	}
 	payload := createURLMonitorPayload(monitor)
 	data, err := json.Marshal(payload)
 	if err != nil {
@@ -985,6 +1107,9 @@ func (c *client) UpdateDNSMonitor(
 	ID string,
 	monitor DNSMonitor,
 ) (*DNSMonitor, error) {
+	if err := c.validateMonitor(&monitor); err != nil {
+		return nil, err
+	}
 	payload := createDNSMonitorPayload(monitor)
 	data, err := json.Marshal(payload)
 	if err != nil {
@@ -1015,6 +1140,9 @@ func (c *client) UpdateICMPMonitor(
 	ID string,
 	monitor ICMPMonitor,
 ) (*ICMPMonitor, error) {
+	if err := c.validateMonitor(&monitor); err != nil {
+		return nil, err
+	}
 	payload := createICMPMonitorPayload(monitor)
 	data, err := json.Marshal(payload)
 	if err != nil {
@@ -1045,6 +1173,9 @@ func (c *client) UpdatePlaywrightCheck(
 	ID string,
 	check PlaywrightCheck,
 ) (*PlaywrightCheck, error) {
+	if err := c.validateMonitor(&check); err != nil {
+		return nil, err
+	}
 	payload := createPlaywrightCheckPayload(check)
 	data, err := json.Marshal(payload)
 	if err != nil {
@@ -1070,6 +1201,44 @@ func (c *client) UpdatePlaywrightCheck(
 	return &result, nil
 }
 
+// UpdateMultiStepCheck updates an existing multistep check with the specified
+// details. It returns the updated check, or an error.
+func (c *client) UpdateMultiStepCheck(
+	ctx context.Context,
+	ID string,
+	check MultiStepCheck,
+) (*MultiStepCheck, error) {
+	if err := c.validateMonitor(&check); err != nil {
+		return nil, err
+	}
+	if err := c.validateMultiStepRuntime(ctx, check); err != nil {
+		return nil, err
+	}
+	payload := createMultiStepCheckPayload(check)
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	status, res, err := c.apiCall(
+		ctx,
+		http.MethodPut,
+		withAutoAssignAlertsFlag(fmt.Sprintf("checks/multistep/%s", ID)),
+		data,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response status %d: %q", status, res)
+	}
+	var result MultiStepCheck
+	err = json.NewDecoder(strings.NewReader(res)).Decode(&result)
+	if err != nil {
+		return nil, fmt.Errorf("decoding error for data %s: %v", res, err)
+	}
+	return &result, nil
+}
+
 // Delete deletes the check with the specified ID.
 func (c *client) DeleteCheck(
 	ctx context.Context,
@@ -1153,6 +1322,14 @@ func (c *client) DeletePlaywrightCheck(
 	return c.DeleteCheck(ctx, ID)
 }
 
+// DeleteMultiStepCheck deletes the multistep check with the specified ID.
+func (c *client) DeleteMultiStepCheck(
+	ctx context.Context,
+	ID string,
+) error {
+	return c.DeleteCheck(ctx, ID)
+}
+
 // Get takes the ID of an existing check, and returns the check parameters, or
 // an error.
 func (c *client) GetCheck(
@@ -1429,6 +1606,105 @@ func (c *client) GetPlaywrightCheck(
 	return &result, nil
 }
 
+// GetMultiStepCheck takes the ID of an existing multistep check, and returns
+// the check parameters, or an error.
+func (c *client) GetMultiStepCheck(
+	ctx context.Context,
+	ID string,
+) (*MultiStepCheck, error) {
+	status, res, err := c.apiCall(
+		ctx,
+		http.MethodGet,
+		fmt.Sprintf("checks/%s", ID),
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response status %d: %q", status, res)
+	}
+	var result MultiStepCheck
+	err = json.NewDecoder(strings.NewReader(res)).Decode(&result)
+	if err != nil {
+		return nil, fmt.Errorf("decoding error for data %s: %v", res, err)
+	}
+	return &result, nil
+}
+
+// GetAnyCheck takes the ID of an existing check or monitor of any type, and
+// returns it decoded into the concrete type matching its check type, or an
+// error.
+func (c *client) GetAnyCheck(
+	ctx context.Context,
+	ID string,
+) (Monitor, error) {
+	status, res, err := c.apiCall(
+		ctx,
+		http.MethodGet,
+		fmt.Sprintf("checks/%s", ID),
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response status %d: %q", status, res)
+	}
+	return DecodeMonitor([]byte(res))
+}
+
+// ListChecks returns a Pager over all checks and monitors matching opts. The
+// filters are applied client-side while walking the pages of all checks in
+// the account.
+func (c *client) ListChecks(
+	ctx context.Context,
+	opts ListChecksOptions,
+) *Pager[Monitor] {
+	return newListPager(c, opts.ListOptions, "checks", nil, func(data []byte) (Monitor, bool, error) {
+		match, err := opts.match(data)
+		if err != nil || !match {
+			return nil, false, err
+		}
+		monitor, err := DecodeMonitor(data)
+		return monitor, err == nil, err
+	})
+}
+
+// match reports whether the check encoded in data matches the filters. It
+// works on the encoded check, as the fields filtered on are common to every
+// check type but not to every Go type.
+func (opts ListChecksOptions) match(data []byte) (bool, error) {
+	var check struct {
+		Type      string   `json:"checkType"`
+		Tags      []string `json:"tags"`
+		GroupID   *int64   `json:"groupId"`
+		Activated bool     `json:"activated"`
+		Muted     bool     `json:"muted"`
+	}
+	if err := json.Unmarshal(data, &check); err != nil {
+		return false, fmt.Errorf("decoding error for data %s: %v", data, err)
+	}
+	if opts.CheckType != "" && check.Type != opts.CheckType {
+		return false, nil
+	}
+	if opts.GroupID != 0 && (check.GroupID == nil || *check.GroupID != opts.GroupID) {
+		return false, nil
+	}
+	if opts.Activated != nil && check.Activated != *opts.Activated {
+		return false, nil
+	}
+	if opts.Muted != nil && check.Muted != *opts.Muted {
+		return false, nil
+	}
+	for _, tag := range opts.Tags {
+		if !slices.Contains(check.Tags, tag) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
 type groupPayload struct {
 	Group
 }
@@ -1562,6 +1838,14 @@ func (c *client) GetGroup(
 	return &result, nil
 }
 
+// ListGroups returns a Pager over all check groups in the account.
+func (c *client) ListGroups(
+	ctx context.Context,
+	opts ListOptions,
+) *Pager[Group] {
+	return newListPager(c, opts, "check-groups", nil, decodeListItem[Group])
+}
+
 // GetGroupV2 takes the ID of an existing check group, and returns the
 // corresponding group, or an error.
 func (c *client) GetGroupV2(
@@ -1589,6 +1873,15 @@ func (c *client) GetGroupV2(
 	return &result, nil
 }
 
+// ListGroupsV2 returns a Pager over all check groups in the account, decoded as
+// GroupV2.
+func (c *client) ListGroupsV2(
+	ctx context.Context,
+	opts ListOptions,
+) *Pager[GroupV2] {
+	return newListPager(c, opts, "check-groups", nil, decodeListItem[GroupV2])
+}
+
 // UpdateGroup takes the ID of an existing check group, and updates the
 // corresponding check group to match the supplied group. It returns the updated
 // group, or an error.
@@ -1712,6 +2005,9 @@ func (c *client) GetCheckResult(
 }
 
 // GetCheckResults gets the results of the given Check
+//
+// Deprecated: GetCheckResults only returns a single page of results, use
+// ListCheckResults instead.
 func (c *client) GetCheckResults(
 	ctx context.Context,
 	checkID string,
@@ -1719,28 +2015,13 @@ func (c *client) GetCheckResults(
 ) ([]CheckResult, error) {
 	uri := fmt.Sprintf("check-results/%s", checkID)
 	if filters != nil {
-		q := url.Values{}
+		q := filters.query()
 		if filters.Page > 0 {
 			q.Add("page", fmt.Sprintf("%d", filters.Page))
 		}
 		if filters.Limit > 0 {
 			q.Add("limit", fmt.Sprintf("%d", filters.Limit))
 		}
-		if filters.From > 0 {
-			q.Add("from", fmt.Sprintf("%d", filters.From))
-		}
-		if filters.To > 0 {
-			q.Add("to", fmt.Sprintf("%d", filters.To))
-		}
-		if filters.CheckType == TypeBrowser || filters.CheckType == TypeAPI {
-			q.Add("checkType", string(filters.CheckType))
-		}
-		if filters.HasFailures {
-			q.Add("hasFailures", "1")
-		}
-		if len(filters.Location) > 0 {
-			q.Add("location", filters.Location)
-		}
 		uri = uri + "?" + q.Encode()
 	}
 
@@ -1765,6 +2046,45 @@ func (c *client) GetCheckResults(
 	return result, nil
 }
 
+// ListCheckResults returns a Pager over the results of the given Check
+// matching filters, whose Page and Limit fields are ignored in favour of opts.
+func (c *client) ListCheckResults(
+	ctx context.Context,
+	checkID string,
+	filters CheckResultsFilter,
+	opts ListOptions,
+) *Pager[CheckResult] {
+	return newListPager(
+		c,
+		opts,
+		fmt.Sprintf("check-results/%s", checkID),
+		filters.query(),
+		decodeListItem[CheckResult],
+	)
+}
+
+// query returns the query parameters for the filters other than Page and
+// Limit.
+func (filters CheckResultsFilter) query() url.Values {
+	q := url.Values{}
+	if filters.From > 0 {
+		q.Add("from", fmt.Sprintf("%d", filters.From))
+	}
+	if filters.To > 0 {
+		q.Add("to", fmt.Sprintf("%d", filters.To))
+	}
+	if filters.CheckType == TypeBrowser || filters.CheckType == TypeAPI {
+		q.Add("checkType", string(filters.CheckType))
+	}
+	if filters.HasFailures {
+		q.Add("hasFailures", "1")
+	}
+	if len(filters.Location) > 0 {
+		q.Add("location", filters.Location)
+	}
+	return q
+}
+
 // CreateSnippet creates a new snippet with the specified details. It returns
 // the newly-created snippet, or an error.
 func (c *client) CreateSnippet(
@@ -1811,6 +2131,14 @@ func (c *client) GetSnippet(
 	return &result, nil
 }
 
+// ListSnippets returns a Pager over all snippets in the account.
+func (c *client) ListSnippets(
+	ctx context.Context,
+	opts ListOptions,
+) *Pager[Snippet] {
+	return newListPager(c, opts, "snippets", nil, decodeListItem[Snippet])
+}
+
 // UpdateSnippet takes the ID of an existing snippet, and updates the
 // corresponding snippet to match the supplied snippet. It returns the updated
 // snippet, or an error.
@@ -1909,6 +2237,14 @@ func (c *client) GetEnvironmentVariable(
 	return &result, nil
 }
 
+// ListEnvironmentVariables returns a Pager over all environment variables in the account.
+func (c *client) ListEnvironmentVariables(
+	ctx context.Context,
+	opts ListOptions,
+) *Pager[EnvironmentVariable] {
+	return newListPager(c, opts, "variables", nil, decodeListItem[EnvironmentVariable])
+}
+
 // UpdateEnvironmentVariable takes the ID of an existing environment variable, and updates the
 // corresponding environment variable to match the supplied environment variable. It returns the updated
 // environment variable, or an error.
@@ -1997,6 +2333,20 @@ func (c *client) GetAlertChannel(
 	return alertChannelFromJSON(res)
 }
 
+// ListAlertChannels returns a Pager over all alert channels in the account.
+func (c *client) ListAlertChannels(
+	ctx context.Context,
+	opts ListOptions,
+) *Pager[AlertChannel] {
+	return newListPager(c, opts, "alert-channels", nil, func(data []byte) (AlertChannel, bool, error) {
+		ac, err := alertChannelFromJSON(string(data))
+		if err != nil {
+			return AlertChannel{}, false, err
+		}
+		return *ac, true, nil
+	})
+}
+
 // UpdateAlertChannel takes the ID of an existing alert channel, and updates the
 // corresponding alert channel to match the supplied alert channel. It returns the updated
 // alert channel, or an error.
@@ -2087,6 +2437,14 @@ func (c *client) GetDashboard(
 	return &result, nil
 }
 
+// ListDashboards returns a Pager over all dashboards in the account.
+func (c *client) ListDashboards(
+	ctx context.Context,
+	opts ListOptions,
+) *Pager[Dashboard] {
+	return newListPager(c, opts, "dashboards", nil, decodeListItem[Dashboard])
+}
+
 // DeleteDashboard deletes the dashboard with the specified ID.
 func (c *client) DeleteDashboard(
 	ctx context.Context,
@@ -2185,6 +2543,14 @@ func (c *client) GetMaintenanceWindow(
 	return &result, nil
 }
 
+// ListMaintenanceWindows returns a Pager over all maintenance windows in the account.
+func (c *client) ListMaintenanceWindows(
+	ctx context.Context,
+	opts ListOptions,
+) *Pager[MaintenanceWindow] {
+	return newListPager(c, opts, "maintenance-windows", nil, decodeListItem[MaintenanceWindow])
+}
+
 // DeleteMaintenanceWindow deletes the window with the specified ID.
 func (c *client) DeleteMaintenanceWindow(
 	ctx context.Context,
@@ -2281,6 +2647,15 @@ func (c *client) GetPrivateLocation(
 	return &result, nil
 }
 
+// ListPrivateLocations returns a Pager over all private locations in the account.
+// The API returns them in one response, so PageSize has no effect.
+func (c *client) ListPrivateLocations(
+	ctx context.Context,
+	opts ListOptions,
+) *Pager[PrivateLocation] {
+	return newUnpaginatedListPager(c, opts, "private-locations", decodeListItem[PrivateLocation])
+}
+
 // DeletePrivateLocation deletes the private location with the specified ID.
 func (c *client) DeletePrivateLocation(
 	ctx context.Context,
@@ -2506,6 +2881,14 @@ func (c *client) GetClientCertificate(
 	return &result, nil
 }
 
+// ListClientCertificates returns a Pager over all client certificates in the account.
+func (c *client) ListClientCertificates(
+	ctx context.Context,
+	opts ListOptions,
+) *Pager[ClientCertificate] {
+	return newCursorListPager(c, opts, "client-certificates", decodeListItem[ClientCertificate])
+}
+
 func (c *client) DeleteClientCertificate(
 	ctx context.Context,
 	ID string,
@@ -2562,6 +2945,14 @@ func (c *client) GetStatusPage(
 	return &result, nil
 }
 
+// ListStatusPages returns a Pager over all status pages in the account.
+func (c *client) ListStatusPages(
+	ctx context.Context,
+	opts ListOptions,
+) *Pager[StatusPage] {
+	return newCursorListPager(c, opts, "status-pages", decodeListItem[StatusPage])
+}
+
 func (c *client) UpdateStatusPage(
 	ctx context.Context,
 	ID string,
@@ -2642,6 +3033,14 @@ func (c *client) GetStatusPageService(
 	return &result, nil
 }
 
+// ListStatusPageServices returns a Pager over all status page services in the account.
+func (c *client) ListStatusPageServices(
+	ctx context.Context,
+	opts ListOptions,
+) *Pager[StatusPageService] {
+	return newCursorListPager(c, opts, "status-pages/services", decodeListItem[StatusPageService])
+}
+
 func (c *client) UpdateStatusPageService(
 	ctx context.Context,
 	ID string,
@@ -2882,6 +3281,9 @@ func (c *client) addAuthHeaders(req *http.Request) error {
 	} else {
 		req.Header.Add("x-checkly-source", "go-sdk")
 	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
 
 	return nil
 }
@@ -2904,6 +3306,9 @@ func (c *client) apiCallV1(
 	return c.apiCallV(ctx, 1, method, URL, data)
 }
 
+// apiCallV performs a request against version v of the public API. A non-2xx
+// reply is returned as an *APIError alongside the status code and body.
+// Failed attempts are retried according to the client's RetryPolicy.
 func (c *client) apiCallV(
 	ctx context.Context,
 	v int,
@@ -2912,15 +3317,58 @@ func (c *client) apiCallV(
 	data []byte,
 ) (statusCode int, response string, err error) {
 	requestURL := fmt.Sprintf("%s/v%d/%s", c.url, v, URL)
-	req, err := http.NewRequest(method, requestURL, bytes.NewBuffer(data))
+	for attempt := 1; ; attempt++ {
+		// The request is rebuilt from data for every attempt, so the body is
+		// always sent in full.
+		req, err := c.newAPIRequest(ctx, method, requestURL, data)
+		if err != nil {
+			return 0, "", err
+		}
+
+		if err := c.waitRateLimit(ctx); err != nil {
+			return 0, "", err
+		}
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			if ctx.Err() == nil && c.retryPolicy.shouldRetry(method, attempt) {
+				if err := sleepContext(ctx, c.retryPolicy.backoff(attempt)); err != nil {
+					return 0, "", err
+				}
+				continue
+			}
+			return 0, "", fmt.Errorf("HTTP request failed with: %v", err)
+		}
+
+		c.observeRateLimit(resp)
+		statusCode, response, err = c.readAPIResponse(req, resp)
+		if isRetryableStatus(statusCode) && c.retryPolicy.shouldRetry(method, attempt) {
+			delay, ok := serverRetryDelay(resp.Header)
+			if !ok {
+				delay = c.retryPolicy.backoff(attempt)
+			}
+			if err := sleepContext(ctx, delay); err != nil {
+				return statusCode, response, err
+			}
+			continue
+		}
+		return statusCode, response, err
+	}
+}
 
+func (c *client) newAPIRequest(
+	ctx context.Context,
+	method string,
+	requestURL string,
+	data []byte,
+) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, requestURL, bytes.NewReader(data))
 	if err != nil {
-		return 0, "", fmt.Errorf("failed to create HTTP request: %v", err)
+		return nil, fmt.Errorf("failed to create HTTP request: %v", err)
 	}
 
 	err = c.addAuthHeaders(req)
 	if err != nil {
-		return 0, "", err
+		return nil, err
 	}
 
 	req.Header.Add("content-type", "application/json")
@@ -2928,18 +3376,37 @@ func (c *client) apiCallV(
 	if c.debug != nil {
 		requestDump, err := httputil.DumpRequestOut(req, true)
 		if err != nil {
-			return 0, "", fmt.Errorf("error dumping HTTP request: %v", err)
+			return nil, fmt.Errorf("error dumping HTTP request: %v", err)
 		}
 		fmt.Fprintln(c.debug, string(requestDump))
 		fmt.Fprintln(c.debug)
 	}
 
-	req = req.WithContext(ctx)
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return 0, "", fmt.Errorf("HTTP request failed with: %v", err)
+	return req, nil
+}
+
+// waitRateLimit blocks until the client's rate limiter, if any, allows
+// another request.
+func (c *client) waitRateLimit(ctx context.Context) error {
+	if c.rateLimiter == nil {
+		return nil
 	}
+	return c.rateLimiter.Wait(ctx)
+}
 
+// observeRateLimit feeds the rate limit headers of resp to the client's rate
+// limiter, if any.
+func (c *client) observeRateLimit(resp *http.Response) {
+	if c.rateLimiter == nil {
+		return
+	}
+	c.rateLimiter.observe(resp.StatusCode, resp.Header)
+}
+
+func (c *client) readAPIResponse(
+	req *http.Request,
+	resp *http.Response,
+) (statusCode int, response string, err error) {
 	defer resp.Body.Close()
 	if c.debug != nil {
 		c.dumpResponse(resp)
@@ -2949,6 +3416,15 @@ func (c *client) apiCallV(
 	if err != nil {
 		return resp.StatusCode, "", fmt.Errorf("HTTP request failed: %v", err)
 	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, string(res), newAPIError(
+			req.Method,
+			req.URL.Path,
+			resp.StatusCode,
+			resp.Header,
+			string(res),
+		)
+	}
 	return resp.StatusCode, string(res), nil
 }
 
@@ -2988,11 +3464,15 @@ func (c *client) UploadCodeBundle(
 		req.Header.Add("x-bundle-checksum-sha256", options.ChecksumSha256)
 	}
 
+	if err := c.waitRateLimit(ctx); err != nil {
+		return nil, err
+	}
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
+	c.observeRateLimit(resp)
 
 	switch {
 	case resp.StatusCode == 200:
@@ -3006,7 +3486,17 @@ func (c *client) UploadCodeBundle(
 
 		return &output, nil
 	default:
-		return nil, fmt.Errorf("unexpected HTTP %d response code: %v", resp.StatusCode, resp.Status)
+		res, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("HTTP request failed: %v", err)
+		}
+		return nil, newAPIError(
+			req.Method,
+			req.URL.Path,
+			resp.StatusCode,
+			resp.Header,
+			string(res),
+		)
 	}
 }
 
@@ -3046,11 +3536,15 @@ func (c *client) PeekCodeBundle(
 
 	req.Header.Add("content-type", "application/json")
 
+	if err := c.waitRateLimit(ctx); err != nil {
+		return nil, err
+	}
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
+	c.observeRateLimit(resp)
 
 	switch {
 	case resp.StatusCode == 404, resp.StatusCode == 403:
@@ -3066,6 +3560,16 @@ func (c *client) PeekCodeBundle(
 
 		return &output, nil
 	default:
-		return nil, fmt.Errorf("unexpected HTTP %d response code: %v", resp.StatusCode, resp.Status)
+		res, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("HTTP request failed: %v", err)
+		}
+		return nil, newAPIError(
+			req.Method,
+			req.URL.Path,
+			resp.StatusCode,
+			resp.Header,
+			string(res),
+		)
 	}
 }
@@ -14,15 +14,22 @@ import (
 	"github.com/google/go-cmp/cmp/cmpopts"
 
 	checkly "github.com/checkly/checkly-go-sdk"
+	"github.com/checkly/checkly-go-sdk/checklytest"
 )
 
 func setupClient(t *testing.T) checkly.Client {
 	var debug io.Writer // to enable debug output set to => os.Stdout
 	baseUrl := os.Getenv("CHECKLY_API_URL")
+	apiKey := os.Getenv("CHECKLY_API_KEY")
+	if baseUrl == "" && apiKey == "" {
+		// Without an API to run against, run against a local stand-in.
+		server := checklytest.NewServer()
+		t.Cleanup(server.Close)
+		return checkly.NewClient(server.URL, "dummy-key", server.Client(), debug)
+	}
 	if baseUrl == "" {
 		baseUrl = "http://localhost:3000"
 	}
-	apiKey := os.Getenv("CHECKLY_API_KEY")
 	if apiKey == "" {
 		t.Fatal("'CHECKLY_API_KEY' must be set for integration tests")
 	}
@@ -335,7 +342,13 @@ func TestGetTriggerCheckIntegration(t *testing.T) {
 
 func TestCreateTriggerGroupIntegration(t *testing.T) {
 	client := setupClient(t)
-	gotGroup, err := client.CreateGroup(context.Background(), wantGroup)
+	wantGroupCopy := wantGroup
+	ac, err := makeTestAlertChannel(client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantGroupCopy.AlertChannelSubscriptions[0].ChannelID = ac.ID
+	gotGroup, err := client.CreateGroup(context.Background(), wantGroupCopy)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -351,7 +364,13 @@ func TestCreateTriggerGroupIntegration(t *testing.T) {
 
 func TestGetTriggerGroupIntegration(t *testing.T) {
 	client := setupClient(t)
-	gotGroup, err := client.CreateGroup(context.Background(), wantGroup)
+	wantGroupCopy := wantGroup
+	ac, err := makeTestAlertChannel(client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantGroupCopy.AlertChannelSubscriptions[0].ChannelID = ac.ID
+	gotGroup, err := client.CreateGroup(context.Background(), wantGroupCopy)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -3,6 +3,7 @@ package checkly_test
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -15,6 +16,7 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -2959,3 +2961,620 @@ func TestSSLConfigOmitsSecurityBaselineWhenNil(t *testing.T) {
 		t.Errorf("SSL request must omit `sslClientCertificateId` when nil, got: %s", body)
 	}
 }
+
+// routedResponseServer replies to GET requests with the fixture registered
+// for the request URL (path and query), and with 404 to any other request.
+func routedResponseServer(t *testing.T, routes map[string]string) *httptest.Server {
+	return httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		filename, ok := routes[r.URL.String()]
+		if r.Method != http.MethodGet || !ok {
+			t.Errorf("unexpected request %s %q", r.Method, r.URL.String())
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		data, err := os.Open(fmt.Sprintf("fixtures/%s", filename))
+		if err != nil {
+			t.Error(err)
+		}
+		defer data.Close()
+		io.Copy(w, data)
+	}))
+}
+
+func listChecksServer(t *testing.T) *httptest.Server {
+	return routedResponseServer(t, map[string]string{
+		"/v1/checks?limit=3&page=1": "ListChecksPage1.json",
+		"/v1/checks?limit=3&page=2": "ListChecksPage2.json",
+	})
+}
+
+func TestListChecks(t *testing.T) {
+	t.Parallel()
+	ts := listChecksServer(t)
+	defer ts.Close()
+	client := checkly.NewClient(ts.URL, "dummy-key", ts.Client(), nil)
+	ctx := context.Background()
+	pager := client.ListChecks(ctx, checkly.ListChecksOptions{
+		ListOptions: checkly.ListOptions{PageSize: 3},
+	})
+	var got []checkly.Monitor
+	for pager.Next(ctx) {
+		got = append(got, pager.Value())
+	}
+	if err := pager.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 5 {
+		t.Fatalf("want 5 checks, got %d", len(got))
+	}
+	check, ok := got[0].(*checkly.Check)
+	if !ok || check.Request.URL != "https://example.com/api" || check.GroupID != 15 {
+		t.Errorf("want API check to be decoded as *checkly.Check, got %#v", got[0])
+	}
+	tcp, ok := got[1].(*checkly.TCPMonitor)
+	if !ok || tcp.Request.Hostname != "example.com" || tcp.Request.Port != 443 {
+		t.Errorf("want TCP monitor to be decoded as *checkly.TCPMonitor, got %#v", got[1])
+	}
+	heartbeat, ok := got[2].(*checkly.HeartbeatMonitor)
+	if !ok || heartbeat.Heartbeat.PingToken != "token" {
+		t.Errorf("want heartbeat to be decoded as *checkly.HeartbeatMonitor, got %#v", got[2])
+	}
+	urlMonitor, ok := got[3].(*checkly.URLMonitor)
+	if !ok || urlMonitor.Request.URL != "https://example.com" {
+		t.Errorf("want URL monitor to be decoded as *checkly.URLMonitor, got %#v", got[3])
+	}
+	unknown, ok := got[4].(*checkly.UnknownMonitor)
+	if !ok || unknown.CheckType() != "QUANTUM" || len(unknown.Raw) == 0 {
+		t.Errorf("want unknown check type to be decoded as *checkly.UnknownMonitor, got %#v", got[4])
+	}
+}
+
+func TestListChecksFilters(t *testing.T) {
+	t.Parallel()
+	yes, no := true, false
+	tcs := map[string]struct {
+		opts    checkly.ListChecksOptions
+		wantIDs []string
+	}{
+		"tags": {
+			opts: checkly.ListChecksOptions{Tags: []string{"production", "web"}},
+			wantIDs: []string{
+				"6f8f7bd5-2340-4de4-9df5-5db45b309ad4",
+			},
+		},
+		"check type": {
+			opts: checkly.ListChecksOptions{CheckType: checkly.TypeTCP},
+			wantIDs: []string{
+				"af5df2b2-1b2c-4d59-a5a4-7b2a4e1ae6a3",
+			},
+		},
+		"group": {
+			opts: checkly.ListChecksOptions{GroupID: 15},
+			wantIDs: []string{
+				"73d29e72-6540-4bb5-967e-e07fa2c9465e",
+				"6f8f7bd5-2340-4de4-9df5-5db45b309ad4",
+			},
+		},
+		"deactivated": {
+			opts: checkly.ListChecksOptions{Activated: &no},
+			wantIDs: []string{
+				"1c6c0f6e-94a2-4a53-8a3e-5b0b5bf0b6c1",
+			},
+		},
+		"muted": {
+			opts: checkly.ListChecksOptions{Muted: &yes},
+			wantIDs: []string{
+				"af5df2b2-1b2c-4d59-a5a4-7b2a4e1ae6a3",
+			},
+		},
+	}
+	for name, tc := range tcs {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ts := listChecksServer(t)
+			defer ts.Close()
+			client := checkly.NewClient(ts.URL, "dummy-key", ts.Client(), nil)
+			ctx := context.Background()
+			tc.opts.PageSize = 3
+			pager := client.ListChecks(ctx, tc.opts)
+			var gotIDs []string
+			for pager.Next(ctx) {
+				raw, err := json.Marshal(pager.Value())
+				if err != nil {
+					t.Fatal(err)
+				}
+				var id struct{ ID string }
+				json.Unmarshal(raw, &id)
+				gotIDs = append(gotIDs, id.ID)
+			}
+			if err := pager.Err(); err != nil {
+				t.Fatal(err)
+			}
+			if !cmp.Equal(tc.wantIDs, gotIDs) {
+				t.Error(cmp.Diff(tc.wantIDs, gotIDs))
+			}
+		})
+	}
+}
+
+func TestListChecksError(t *testing.T) {
+	t.Parallel()
+	ts := cannedResponseServer(t,
+		http.MethodGet,
+		"/v1/checks?limit=100&page=1",
+		validateEmptyBody,
+		http.StatusInternalServerError,
+		"Empty.json",
+	)
+	defer ts.Close()
+	client := checkly.NewClient(ts.URL, "dummy-key", ts.Client(), nil)
+	ctx := context.Background()
+	pager := client.ListChecks(ctx, checkly.ListChecksOptions{})
+	if pager.Next(ctx) {
+		t.Fatal("want no checks, got one")
+	}
+	var apiErr *checkly.APIError
+	if !errors.As(pager.Err(), &apiErr) || apiErr.StatusCode != http.StatusInternalServerError {
+		t.Errorf("want a 500 APIError, got %v", pager.Err())
+	}
+}
+
+func TestListGroups(t *testing.T) {
+	t.Parallel()
+	ts := routedResponseServer(t, map[string]string{
+		"/v1/check-groups?limit=2&page=1": "ListGroupsPage1.json",
+		"/v1/check-groups?limit=2&page=2": "ListGroupsPage2.json",
+	})
+	defer ts.Close()
+	client := checkly.NewClient(ts.URL, "dummy-key", ts.Client(), nil)
+	ctx := context.Background()
+	// MaxItems stops the iteration halfway through the second page, and no
+	// third page is requested.
+	pager := client.ListGroups(ctx, checkly.ListOptions{PageSize: 2, MaxItems: 3})
+	var gotNames []string
+	for pager.Next(ctx) {
+		gotNames = append(gotNames, pager.Value().Name)
+	}
+	if err := pager.Err(); err != nil {
+		t.Fatal(err)
+	}
+	wantNames := []string{"Production", "Staging", "Development"}
+	if !cmp.Equal(wantNames, gotNames) {
+		t.Error(cmp.Diff(wantNames, gotNames))
+	}
+}
+
+func TestListAlertChannels(t *testing.T) {
+	t.Parallel()
+	ts := routedResponseServer(t, map[string]string{
+		"/v1/alert-channels?limit=100&page=1": "ListAlertChannels.json",
+	})
+	defer ts.Close()
+	client := checkly.NewClient(ts.URL, "dummy-key", ts.Client(), nil)
+	ctx := context.Background()
+	pager := client.ListAlertChannels(ctx, checkly.ListOptions{})
+	var got []checkly.AlertChannel
+	for pager.Next(ctx) {
+		got = append(got, pager.Value())
+	}
+	if err := pager.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("want 2 alert channels, got %d", len(got))
+	}
+	if got[0].Email == nil || got[0].Email.Address != "test@example.com" {
+		t.Errorf("want email config to be decoded, got %#v", got[0].Email)
+	}
+	if got[1].Slack == nil || got[1].Slack.Channel != "#alerts" {
+		t.Errorf("want Slack config to be decoded, got %#v", got[1].Slack)
+	}
+}
+
+func TestListStatusPages(t *testing.T) {
+	t.Parallel()
+	ts := routedResponseServer(t, map[string]string{
+		"/v1/status-pages?limit=1": "ListStatusPages.json",
+		"/v1/status-pages?limit=1&nextId=e1f5a3c2-4b8d-4f6a-9c7e-2d3b4a5c6d7e": "ListStatusPagesPage2.json",
+	})
+	defer ts.Close()
+	client := checkly.NewClient(ts.URL, "dummy-key", ts.Client(), nil)
+	ctx := context.Background()
+	got, err := client.ListStatusPages(ctx, checkly.ListOptions{PageSize: 1}).Collect(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var gotNames []string
+	for _, page := range got {
+		gotNames = append(gotNames, page.Name)
+	}
+	wantNames := []string{"Foo status page", "Bar status page"}
+	if !cmp.Equal(wantNames, gotNames) {
+		t.Error(cmp.Diff(wantNames, gotNames))
+	}
+}
+
+func TestListPrivateLocations(t *testing.T) {
+	t.Parallel()
+	ts := routedResponseServer(t, map[string]string{
+		"/v1/private-locations": "ListPrivateLocations.json",
+	})
+	defer ts.Close()
+	client := checkly.NewClient(ts.URL, "dummy-key", ts.Client(), nil)
+	ctx := context.Background()
+	// The endpoint is not paginated, so a page size matching the number of
+	// private locations must not cause another request.
+	pager := client.ListPrivateLocations(ctx, checkly.ListOptions{PageSize: 2})
+	var gotSlugs []string
+	for pager.Next(ctx) {
+		gotSlugs = append(gotSlugs, pager.Value().SlugName)
+	}
+	if err := pager.Err(); err != nil {
+		t.Fatal(err)
+	}
+	wantSlugs := []string{"office", "data-center"}
+	if !cmp.Equal(wantSlugs, gotSlugs) {
+		t.Error(cmp.Diff(wantSlugs, gotSlugs))
+	}
+}
+
+func TestListCheckResults(t *testing.T) {
+	t.Parallel()
+	ts := routedResponseServer(t, map[string]string{
+		"/v1/check-results/73d29e72-6540-4bb5-967e-e07fa2c9465e?hasFailures=1&limit=10&location=eu-west-1&page=1": "GetCheckResults.json",
+		"/v1/check-results/73d29e72-6540-4bb5-967e-e07fa2c9465e?hasFailures=1&limit=10&location=eu-west-1&page=2": "EmptyList.json",
+	})
+	defer ts.Close()
+	client := checkly.NewClient(ts.URL, "dummy-key", ts.Client(), nil)
+	ctx := context.Background()
+	pager := client.ListCheckResults(ctx, wantCheckID, checkly.CheckResultsFilter{
+		Location:    "eu-west-1",
+		HasFailures: true,
+	}, checkly.ListOptions{PageSize: 10})
+	results, err := pager.Collect(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 10 {
+		t.Errorf("want 10 results, got %d", len(results))
+	}
+}
+
+func TestPagerPrefetchesNextPage(t *testing.T) {
+	t.Parallel()
+	var requests int32
+	ts := routedResponseServer(t, map[string]string{
+		"/v1/check-groups?limit=2&page=1": "ListGroupsPage1.json",
+		"/v1/check-groups?limit=2&page=2": "ListGroupsPage2.json",
+		"/v1/check-groups?limit=2&page=3": "EmptyList.json",
+	})
+	defer ts.Close()
+	handler := ts.Config.Handler
+	ts.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		handler.ServeHTTP(w, r)
+	})
+	client := checkly.NewClient(ts.URL, "dummy-key", ts.Client(), nil)
+	ctx := context.Background()
+	pager := client.ListGroups(ctx, checkly.ListOptions{PageSize: 2})
+	if !pager.Next(ctx) {
+		t.Fatal(pager.Err())
+	}
+	// The second page is requested while the first one is being consumed.
+	deadline := time.Now().Add(5 * time.Second)
+	for atomic.LoadInt32(&requests) < 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("want the second page to be prefetched")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	rest, err := pager.Collect(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rest) != 3 {
+		t.Errorf("want 3 more groups, got %d", len(rest))
+	}
+}
+
+func TestPagerStopsOnContextCancellation(t *testing.T) {
+	t.Parallel()
+	ts := routedResponseServer(t, map[string]string{
+		"/v1/check-groups?limit=2&page=1": "ListGroupsPage1.json",
+		"/v1/check-groups?limit=2&page=2": "ListGroupsPage2.json",
+	})
+	defer ts.Close()
+	client := checkly.NewClient(ts.URL, "dummy-key", ts.Client(), nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	pager := client.ListGroups(ctx, checkly.ListOptions{PageSize: 2, MaxItems: 4})
+	if !pager.Next(ctx) {
+		t.Fatal(pager.Err())
+	}
+	cancel()
+	if pager.Next(ctx) {
+		t.Error("want Next to return false after the context is cancelled")
+	}
+	if !errors.Is(pager.Err(), context.Canceled) {
+		t.Errorf("want context.Canceled, got %v", pager.Err())
+	}
+}
+
+func TestGetAnyCheck(t *testing.T) {
+	t.Parallel()
+	tcs := map[string]struct {
+		fixture  string
+		wantType checkly.Monitor
+	}{
+		"API":        {"GetCheck.json", &checkly.Check{}},
+		"URL":        {"GetURLMonitor.json", &checkly.URLMonitor{}},
+		"DNS":        {"GetDNSMonitor.json", &checkly.DNSMonitor{}},
+		"SSL":        {"GetSSLMonitor.json", &checkly.SSLMonitor{}},
+		"GRPC":       {"GetGRPCMonitor.json", &checkly.GRPCMonitor{}},
+		"TRACEROUTE": {"GetTracerouteMonitor.json", &checkly.TracerouteMonitor{}},
+		"ICMP":       {"GetICMPMonitor.json", &checkly.ICMPMonitor{}},
+	}
+	for name, tc := range tcs {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ts := cannedResponseServer(t,
+				http.MethodGet,
+				fmt.Sprintf("/v1/checks/%s", wantCheckID),
+				validateEmptyBody,
+				http.StatusOK,
+				tc.fixture,
+			)
+			defer ts.Close()
+			client := checkly.NewClient(ts.URL, "dummy-key", ts.Client(), nil)
+			got, err := client.GetAnyCheck(context.Background(), wantCheckID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if reflect.TypeOf(got) != reflect.TypeOf(tc.wantType) {
+				t.Errorf("want %T, got %T", tc.wantType, got)
+			}
+			if got.CheckType() != name {
+				t.Errorf("want check type %q, got %q", name, got.CheckType())
+			}
+		})
+	}
+}
+
+func TestGetAnyCheckDecodesConcreteFields(t *testing.T) {
+	t.Parallel()
+	ts := cannedResponseServer(t,
+		http.MethodGet,
+		fmt.Sprintf("/v1/checks/%s", wantCheckID),
+		validateEmptyBody,
+		http.StatusOK,
+		"GetURLMonitor.json",
+	)
+	defer ts.Close()
+	client := checkly.NewClient(ts.URL, "dummy-key", ts.Client(), nil)
+	got, err := client.GetAnyCheck(context.Background(), wantCheckID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := client.GetURLMonitor(context.Background(), wantCheckID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestCreateCheckRejectsOtherTypes(t *testing.T) {
+	t.Parallel()
+	client := checkly.NewClient("http://localhost", "dummy-key", nil, nil)
+	_, err := client.CreateCheck(context.Background(), checkly.Check{Type: checkly.TypePlaywright})
+	if err == nil || !strings.Contains(err.Error(), "CreatePlaywrightCheck") {
+		t.Errorf("want error pointing to CreatePlaywrightCheck, got %v", err)
+	}
+}
+
+var testMultiStepCheck = checkly.MultiStepCheck{
+	ID:                   "2a8d3c4e-6f10-4b2a-9d5e-7c8b9a0f1e2d",
+	Name:                 "Multistep Check #1",
+	Type:                 checkly.TypeMultiStep,
+	Frequency:            10,
+	FrequencyOffset:      3,
+	Activated:            true,
+	Locations:            []string{"eu-central-1", "us-east-1"},
+	PrivateLocations:     &[]string{},
+	DegradedResponseTime: 10000,
+	MaxResponseTime:      20000,
+	Script: "import { test } from '@playwright/test'\n" +
+		"test('api', async ({ request }) => {\n" +
+		"  await request.get('https://api.checklyhq.com/v1')\n" +
+		"})\n",
+	EnvironmentVariables: []checkly.EnvironmentVariable{},
+	Tags:                 []string{"multistep"},
+	AlertSettings: checkly.AlertSettings{
+		EscalationType: checkly.RunBased,
+		RunBasedEscalation: checkly.RunBasedEscalation{
+			FailedRunThreshold: 1,
+		},
+		TimeBasedEscalation: checkly.TimeBasedEscalation{
+			MinutesFailingThreshold: 5,
+		},
+		Reminders: checkly.Reminders{
+			Interval: 5,
+		},
+		ParallelRunFailureThreshold: checkly.ParallelRunFailureThreshold{
+			Percentage: 10,
+		},
+	},
+	UseGlobalAlertSettings: true,
+}
+
+var ignoreMultiStepCheckFields = cmpopts.IgnoreFields(
+	checkly.MultiStepCheck{},
+	"ID",
+	"AlertChannelSubscriptions",
+	"CreatedAt",
+	"UpdatedAt",
+)
+
+func validateMultiStepCheck(t *testing.T, body []byte) {
+	var payload map[string]interface{}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("decoding error for data %q: %v", body, err)
+	}
+	if payload["checkType"] != checkly.TypeMultiStep {
+		t.Errorf("want checkType %q, got %v", checkly.TypeMultiStep, payload["checkType"])
+	}
+	if groupID, ok := payload["groupId"]; !ok || groupID != nil {
+		t.Errorf("want groupId to be null, got %v", groupID)
+	}
+	var check checkly.MultiStepCheck
+	if err := json.Unmarshal(body, &check); err != nil {
+		t.Fatalf("decoding error for data %q: %v", body, err)
+	}
+	if !cmp.Equal(testMultiStepCheck, check, ignoreMultiStepCheckFields) {
+		t.Error(cmp.Diff(testMultiStepCheck, check, ignoreMultiStepCheckFields))
+	}
+}
+
+func TestCreateMultiStepCheck(t *testing.T) {
+	t.Parallel()
+	ts := cannedResponseServer(t,
+		http.MethodPost,
+		"/v1/checks/multistep?autoAssignAlerts=false",
+		validateMultiStepCheck,
+		http.StatusCreated,
+		"CreateMultiStepCheck.json",
+	)
+	defer ts.Close()
+	client := checkly.NewClient(ts.URL, "dummy-key", ts.Client(), nil)
+	check := testMultiStepCheck
+	check.Type = ""
+	response, err := client.CreateMultiStepCheck(context.Background(), check)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(testMultiStepCheck, *response, ignoreMultiStepCheckFields) {
+		t.Error(cmp.Diff(testMultiStepCheck, *response, ignoreMultiStepCheckFields))
+	}
+}
+
+func TestGetMultiStepCheck(t *testing.T) {
+	t.Parallel()
+	ts := cannedResponseServer(t,
+		http.MethodGet,
+		fmt.Sprintf("/v1/checks/%s", testMultiStepCheck.ID),
+		validateEmptyBody,
+		http.StatusOK,
+		"GetMultiStepCheck.json",
+	)
+	defer ts.Close()
+	client := checkly.NewClient(ts.URL, "dummy-key", ts.Client(), nil)
+	response, err := client.GetMultiStepCheck(context.Background(), testMultiStepCheck.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(testMultiStepCheck, *response, ignoreMultiStepCheckFields) {
+		t.Error(cmp.Diff(testMultiStepCheck, *response, ignoreMultiStepCheckFields))
+	}
+}
+
+func TestUpdateMultiStepCheck(t *testing.T) {
+	t.Parallel()
+	ts := cannedResponseServer(t,
+		http.MethodPut,
+		fmt.Sprintf("/v1/checks/multistep/%s?autoAssignAlerts=false", testMultiStepCheck.ID),
+		validateMultiStepCheck,
+		http.StatusOK,
+		"UpdateMultiStepCheck.json",
+	)
+	defer ts.Close()
+	client := checkly.NewClient(ts.URL, "dummy-key", ts.Client(), nil)
+	_, err := client.UpdateMultiStepCheck(context.Background(), testMultiStepCheck.ID, testMultiStepCheck)
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDeleteMultiStepCheck(t *testing.T) {
+	t.Parallel()
+	ts := cannedResponseServer(t,
+		http.MethodDelete,
+		fmt.Sprintf("/v1/checks/%s", testMultiStepCheck.ID),
+		validateEmptyBody,
+		http.StatusNoContent,
+		"Empty.json",
+	)
+	defer ts.Close()
+	client := checkly.NewClient(ts.URL, "dummy-key", ts.Client(), nil)
+	err := client.DeleteMultiStepCheck(context.Background(), testMultiStepCheck.ID)
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCreateMultiStepCheckValidatesRuntime(t *testing.T) {
+	t.Parallel()
+	ts, calls := scriptedResponseServer(t, validateAnything,
+		scriptedResponse{status: http.StatusOK, filename: "GetRuntime.json"},
+		scriptedResponse{status: http.StatusCreated, filename: "CreateMultiStepCheck.json"},
+	)
+	defer ts.Close()
+	client := checkly.New(
+		checkly.WithBaseURL(ts.URL),
+		checkly.WithHTTPClient(ts.Client()),
+		checkly.WithValidation(),
+	)
+	check := testMultiStepCheck
+	runtimeID := "2024.02"
+	check.RuntimeID = &runtimeID
+	if _, err := client.CreateMultiStepCheck(context.Background(), check); err != nil {
+		t.Fatal(err)
+	}
+	if got := atomic.LoadInt32(calls); got != 2 {
+		t.Errorf("want runtime lookup and create requests, got %d requests", got)
+	}
+}
+
+func TestCreateMultiStepCheckSkipsRuntimeLookup(t *testing.T) {
+	t.Parallel()
+	ts, calls := scriptedResponseServer(t, validateAnything,
+		scriptedResponse{status: http.StatusCreated, filename: "CreateMultiStepCheck.json"},
+	)
+	defer ts.Close()
+	client := checkly.NewClient(ts.URL, "dummy-key", ts.Client(), nil)
+	check := testMultiStepCheck
+	runtimeID := "2024.02"
+	check.RuntimeID = &runtimeID
+	if _, err := client.CreateMultiStepCheck(context.Background(), check); err != nil {
+		t.Fatal(err)
+	}
+	if got := atomic.LoadInt32(calls); got != 1 {
+		t.Errorf("want only the create request without validation, got %d requests", got)
+	}
+}
+
+func TestCreateMultiStepCheckUnsupportedRuntime(t *testing.T) {
+	t.Parallel()
+	ts := cannedResponseServer(t,
+		http.MethodGet,
+		"/v1/runtimes/2022.10",
+		validateEmptyBody,
+		http.StatusOK,
+		"GetRuntimeNoMultiStep.json",
+	)
+	defer ts.Close()
+	client := checkly.New(
+		checkly.WithBaseURL(ts.URL),
+		checkly.WithHTTPClient(ts.Client()),
+		checkly.WithValidation(),
+	)
+	check := testMultiStepCheck
+	runtimeID := "2022.10"
+	check.RuntimeID = &runtimeID
+	_, err := client.CreateMultiStepCheck(context.Background(), check)
+	if err == nil || !strings.Contains(err.Error(), "does not support multistep checks") {
+		t.Errorf("want unsupported runtime error, got %v", err)
+	}
+}
@@ -0,0 +1,487 @@
+package checklytest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	checkly "github.com/checkly/checkly-go-sdk"
+)
+
+// monitorPointer is satisfied by *T when it implements checkly.Monitor.
+type monitorPointer[T any] interface {
+	*T
+	checkly.Monitor
+}
+
+// createMonitor stores a copy of monitor under a new ID, after checking that
+// the resources it references exist, and returns another copy of it.
+func createMonitor[T any, PT monitorPointer[T]](
+	ctx context.Context,
+	c *Client,
+	endpoint string,
+	monitor T,
+) (*T, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stored := PT(clonePointer(&monitor))
+	if err := c.checkMonitorReferences(http.MethodPost, endpoint, stored); err != nil {
+		return nil, err
+	}
+	setMonitorID(stored, c.newUUID())
+	c.checks.put(stored.GetID(), stored)
+	return clonePointer((*T)(stored)), nil
+}
+
+// updateMonitor replaces the check with the given ID by a copy of monitor.
+// Like the API, it fails with a validation error if monitor has another check
+// type than the check.
+func updateMonitor[T any, PT monitorPointer[T]](
+	ctx context.Context,
+	c *Client,
+	endpoint string,
+	ID string,
+	monitor T,
+) (*T, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	path := fmt.Sprintf("%s/%s", endpoint, ID)
+	current, ok := c.checks.get(ID)
+	if !ok {
+		return nil, notFound(http.MethodPut, path)
+	}
+	stored := PT(clonePointer(&monitor))
+	if check, ok := any(stored).(*checkly.Check); ok && check.Type == "" {
+		// The API keeps the type of checks updated without one.
+		check.Type = current.CheckType()
+	}
+	if checkType := stored.CheckType(); checkType != current.CheckType() {
+		return nil, invalid(http.MethodPut, path, "checkType",
+			fmt.Sprintf("cannot change the type of check %s from %s to %s", ID, current.CheckType(), checkType))
+	}
+	if err := c.checkMonitorReferences(http.MethodPut, path, stored); err != nil {
+		return nil, err
+	}
+	setMonitorID(stored, ID)
+	c.checks.put(ID, stored)
+	return clonePointer((*T)(stored)), nil
+}
+
+// getMonitor returns the check with the given ID decoded into a T, the way
+// the real client decodes whatever the API returns for the ID.
+func getMonitor[T any](ctx context.Context, c *Client, ID string) (*T, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	monitor, ok := c.checks.get(ID)
+	if !ok {
+		return nil, notFound(http.MethodGet, "checks/"+ID)
+	}
+	var result T
+	if err := json.Unmarshal(monitorJSON(monitor), &result); err != nil {
+		return nil, fmt.Errorf("decoding error for data %s: %v", monitorJSON(monitor), err)
+	}
+	return &result, nil
+}
+
+func clonePointer[T any](v *T) *T {
+	result := clone(*v)
+	return &result
+}
+
+// monitorJSON returns the JSON encoding of monitor.
+func monitorJSON(monitor checkly.Monitor) []byte {
+	if m, ok := monitor.(*checkly.UnknownMonitor); ok {
+		return m.Raw
+	}
+	data, err := json.Marshal(monitor)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
+// checkMonitorReferences checks that the group and alert channels monitor
+// references exist. c.mu must be held.
+func (c *Client) checkMonitorReferences(method, path string, monitor checkly.Monitor) error {
+	if groupID := monitor.GetGroupID(); groupID != 0 {
+		if _, ok := c.groups.get(groupID); !ok {
+			return invalid(method, path, "groupId", fmt.Sprintf("check group %d does not exist", groupID))
+		}
+	}
+	return c.checkSubscriptions(method, path, monitor)
+}
+
+// checkSubscriptions checks that the alert channels v subscribes to exist.
+// c.mu must be held.
+func (c *Client) checkSubscriptions(method, path string, v any) error {
+	var subscriptions struct {
+		AlertChannelSubscriptions []checkly.AlertChannelSubscription `json:"alertChannelSubscriptions"`
+	}
+	convert(v, &subscriptions)
+	for _, s := range subscriptions.AlertChannelSubscriptions {
+		if _, ok := c.alertChannels.get(s.ChannelID); !ok {
+			return invalid(method, path, "alertChannelSubscriptions",
+				fmt.Sprintf("alert channel %d does not exist", s.ChannelID))
+		}
+	}
+	return nil
+}
+
+// Create creates a check like CreateCheck, without restricting its type.
+//
+// Deprecated: use CreateCheck instead.
+func (c *Client) Create(ctx context.Context, check checkly.Check) (*checkly.Check, error) {
+	return createMonitor(ctx, c, "checks", check)
+}
+
+// Update updates a check like UpdateCheck.
+//
+// Deprecated: use UpdateCheck instead.
+func (c *Client) Update(ctx context.Context, ID string, check checkly.Check) (*checkly.Check, error) {
+	return c.UpdateCheck(ctx, ID, check)
+}
+
+// Delete deletes a check like DeleteCheck.
+//
+// Deprecated: use DeleteCheck instead.
+func (c *Client) Delete(ctx context.Context, ID string) error {
+	return c.DeleteCheck(ctx, ID)
+}
+
+// Get returns a check like GetCheck.
+//
+// Deprecated: use GetCheck instead.
+func (c *Client) Get(ctx context.Context, ID string) (*checkly.Check, error) {
+	return c.GetCheck(ctx, ID)
+}
+
+// CreateCheck creates an API or browser check. Like the real client, it
+// refuses the check types that have their own creation method.
+func (c *Client) CreateCheck(ctx context.Context, check checkly.Check) (*checkly.Check, error) {
+	if err := checkly.CreateCheckError(check.Type); err != nil {
+		return nil, err
+	}
+	return createMonitor(ctx, c, checkEndpoint(check.Type), check)
+}
+
+// checkEndpoint returns the type specific endpoint of checks of checkType.
+func checkEndpoint(checkType string) string {
+	if checkType == checkly.TypeMultiStep {
+		return "checks/multistep"
+	}
+	return "checks/" + strings.ToLower(checkType)
+}
+
+func (c *Client) UpdateCheck(ctx context.Context, ID string, check checkly.Check) (*checkly.Check, error) {
+	return updateMonitor(ctx, c, checkEndpoint(check.Type), ID, check)
+}
+
+func (c *Client) GetCheck(ctx context.Context, ID string) (*checkly.Check, error) {
+	return getMonitor[checkly.Check](ctx, c, ID)
+}
+
+// DeleteCheck deletes the check with the given ID along with its trigger and
+// results.
+func (c *Client) DeleteCheck(ctx context.Context, ID string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.checks.delete(ID) {
+		return notFound(http.MethodDelete, "checks/"+ID)
+	}
+	c.checkTriggers.delete(ID)
+	for _, result := range c.checkResults.values() {
+		if result.CheckID == ID {
+			c.checkResults.delete(result.ID)
+		}
+	}
+	return nil
+}
+
+// Deprecated: use CreateHeartbeatMonitor instead.
+func (c *Client) CreateHeartbeat(ctx context.Context, check checkly.HeartbeatCheck) (*checkly.HeartbeatCheck, error) {
+	return c.CreateHeartbeatMonitor(ctx, check)
+}
+
+// CreateHeartbeatMonitor creates a heartbeat monitor, assigning it a ping
+// token unless it has one.
+func (c *Client) CreateHeartbeatMonitor(ctx context.Context, monitor checkly.HeartbeatMonitor) (*checkly.HeartbeatMonitor, error) {
+	if monitor.Heartbeat.PingToken == "" {
+		c.mu.Lock()
+		monitor.Heartbeat.PingToken = c.newUUID()
+		c.mu.Unlock()
+	}
+	return createMonitor(ctx, c, "checks/heartbeat", monitor)
+}
+
+// Deprecated: use UpdateHeartbeatMonitor instead.
+func (c *Client) UpdateHeartbeat(ctx context.Context, ID string, check checkly.HeartbeatCheck) (*checkly.HeartbeatCheck, error) {
+	return c.UpdateHeartbeatMonitor(ctx, ID, check)
+}
+
+func (c *Client) UpdateHeartbeatMonitor(ctx context.Context, ID string, monitor checkly.HeartbeatMonitor) (*checkly.HeartbeatMonitor, error) {
+	return updateMonitor(ctx, c, "checks/heartbeat", ID, monitor)
+}
+
+// Deprecated: use GetHeartbeatMonitor instead.
+func (c *Client) GetHeartbeatCheck(ctx context.Context, ID string) (*checkly.HeartbeatCheck, error) {
+	return c.GetHeartbeatMonitor(ctx, ID)
+}
+
+func (c *Client) GetHeartbeatMonitor(ctx context.Context, ID string) (*checkly.HeartbeatMonitor, error) {
+	return getMonitor[checkly.HeartbeatMonitor](ctx, c, ID)
+}
+
+func (c *Client) DeleteHeartbeatMonitor(ctx context.Context, ID string) error {
+	return c.DeleteCheck(ctx, ID)
+}
+
+// Deprecated: use CreateTCPMonitor instead.
+func (c *Client) CreateTCPCheck(ctx context.Context, check checkly.TCPCheck) (*checkly.TCPCheck, error) {
+	return c.CreateTCPMonitor(ctx, check)
+}
+
+func (c *Client) CreateTCPMonitor(ctx context.Context, monitor checkly.TCPMonitor) (*checkly.TCPMonitor, error) {
+	return createMonitor(ctx, c, "checks/tcp", monitor)
+}
+
+// Deprecated: use UpdateTCPMonitor instead.
+func (c *Client) UpdateTCPCheck(ctx context.Context, ID string, check checkly.TCPCheck) (*checkly.TCPCheck, error) {
+	return c.UpdateTCPMonitor(ctx, ID, check)
+}
+
+func (c *Client) UpdateTCPMonitor(ctx context.Context, ID string, monitor checkly.TCPMonitor) (*checkly.TCPMonitor, error) {
+	return updateMonitor(ctx, c, "checks/tcp", ID, monitor)
+}
+
+// Deprecated: use GetTCPMonitor instead.
+func (c *Client) GetTCPCheck(ctx context.Context, ID string) (*checkly.TCPCheck, error) {
+	return c.GetTCPMonitor(ctx, ID)
+}
+
+func (c *Client) GetTCPMonitor(ctx context.Context, ID string) (*checkly.TCPMonitor, error) {
+	return getMonitor[checkly.TCPMonitor](ctx, c, ID)
+}
+
+func (c *Client) DeleteTCPMonitor(ctx context.Context, ID string) error {
+	return c.DeleteCheck(ctx, ID)
+}
+
+func (c *Client) CreateGRPCMonitor(ctx context.Context, monitor checkly.GRPCMonitor) (*checkly.GRPCMonitor, error) {
+	return createMonitor(ctx, c, "checks/grpc", monitor)
+}
+
+func (c *Client) UpdateGRPCMonitor(ctx context.Context, ID string, monitor checkly.GRPCMonitor) (*checkly.GRPCMonitor, error) {
+	return updateMonitor(ctx, c, "checks/grpc", ID, monitor)
+}
+
+func (c *Client) GetGRPCMonitor(ctx context.Context, ID string) (*checkly.GRPCMonitor, error) {
+	return getMonitor[checkly.GRPCMonitor](ctx, c, ID)
+}
+
+func (c *Client) DeleteGRPCMonitor(ctx context.Context, ID string) error {
+	return c.DeleteCheck(ctx, ID)
+}
+
+func (c *Client) CreateTracerouteMonitor(ctx context.Context, monitor checkly.TracerouteMonitor) (*checkly.TracerouteMonitor, error) {
+	return createMonitor(ctx, c, "checks/traceroute", monitor)
+}
+
+func (c *Client) UpdateTracerouteMonitor(ctx context.Context, ID string, monitor checkly.TracerouteMonitor) (*checkly.TracerouteMonitor, error) {
+	return updateMonitor(ctx, c, "checks/traceroute", ID, monitor)
+}
+
+func (c *Client) GetTracerouteMonitor(ctx context.Context, ID string) (*checkly.TracerouteMonitor, error) {
+	return getMonitor[checkly.TracerouteMonitor](ctx, c, ID)
+}
+
+func (c *Client) DeleteTracerouteMonitor(ctx context.Context, ID string) error {
+	return c.DeleteCheck(ctx, ID)
+}
+
+func (c *Client) CreateSSLMonitor(ctx context.Context, monitor checkly.SSLMonitor) (*checkly.SSLMonitor, error) {
+	return createMonitor(ctx, c, "checks/ssl", monitor)
+}
+
+func (c *Client) UpdateSSLMonitor(ctx context.Context, ID string, monitor checkly.SSLMonitor) (*checkly.SSLMonitor, error) {
+	return updateMonitor(ctx, c, "checks/ssl", ID, monitor)
+}
+
+func (c *Client) GetSSLMonitor(ctx context.Context, ID string) (*checkly.SSLMonitor, error) {
+	return getMonitor[checkly.SSLMonitor](ctx, c, ID)
+}
+
+func (c *Client) DeleteSSLMonitor(ctx context.Context, ID string) error {
+	return c.DeleteCheck(ctx, ID)
+}
+
+func (c *Client) CreateURLMonitor(ctx context.Context, monitor checkly.URLMonitor) (*checkly.URLMonitor, error) {
+	return createMonitor(ctx, c, "checks/url", monitor)
+}
+
+func (c *Client) UpdateURLMonitor(ctx context.Context, ID string, monitor checkly.URLMonitor) (*checkly.URLMonitor, error) {
+	return updateMonitor(ctx, c, "checks/url", ID, monitor)
+}
+
+func (c *Client) GetURLMonitor(ctx context.Context, ID string) (*checkly.URLMonitor, error) {
+	return getMonitor[checkly.URLMonitor](ctx, c, ID)
+}
+
+func (c *Client) DeleteURLMonitor(ctx context.Context, ID string) error {
+	return c.DeleteCheck(ctx, ID)
+}
+
+func (c *Client) CreateDNSMonitor(ctx context.Context, monitor checkly.DNSMonitor) (*checkly.DNSMonitor, error) {
+	return createMonitor(ctx, c, "checks/dns", monitor)
+}
+
+func (c *Client) UpdateDNSMonitor(ctx context.Context, ID string, monitor checkly.DNSMonitor) (*checkly.DNSMonitor, error) {
+	return updateMonitor(ctx, c, "checks/dns", ID, monitor)
+}
+
+func (c *Client) GetDNSMonitor(ctx context.Context, ID string) (*checkly.DNSMonitor, error) {
+	return getMonitor[checkly.DNSMonitor](ctx, c, ID)
+}
+
+func (c *Client) DeleteDNSMonitor(ctx context.Context, ID string) error {
+	return c.DeleteCheck(ctx, ID)
+}
+
+func (c *Client) CreateICMPMonitor(ctx context.Context, monitor checkly.ICMPMonitor) (*checkly.ICMPMonitor, error) {
+	return createMonitor(ctx, c, "checks/icmp", monitor)
+}
+
+func (c *Client) UpdateICMPMonitor(ctx context.Context, ID string, monitor checkly.ICMPMonitor) (*checkly.ICMPMonitor, error) {
+	return updateMonitor(ctx, c, "checks/icmp", ID, monitor)
+}
+
+func (c *Client) GetICMPMonitor(ctx context.Context, ID string) (*checkly.ICMPMonitor, error) {
+	return getMonitor[checkly.ICMPMonitor](ctx, c, ID)
+}
+
+func (c *Client) DeleteICMPMonitor(ctx context.Context, ID string) error {
+	return c.DeleteCheck(ctx, ID)
+}
+
+func (c *Client) CreatePlaywrightCheck(ctx context.Context, check checkly.PlaywrightCheck) (*checkly.PlaywrightCheck, error) {
+	return createMonitor(ctx, c, "checks/playwright", check)
+}
+
+func (c *Client) UpdatePlaywrightCheck(ctx context.Context, ID string, check checkly.PlaywrightCheck) (*checkly.PlaywrightCheck, error) {
+	return updateMonitor(ctx, c, "checks/playwright", ID, check)
+}
+
+func (c *Client) GetPlaywrightCheck(ctx context.Context, ID string) (*checkly.PlaywrightCheck, error) {
+	return getMonitor[checkly.PlaywrightCheck](ctx, c, ID)
+}
+
+func (c *Client) DeletePlaywrightCheck(ctx context.Context, ID string) error {
+	return c.DeleteCheck(ctx, ID)
+}
+
+// CreateMultiStepCheck creates a multistep check. Like the real client, it
+// first checks that the runtime of the check, if set, supports multistep
+// checks, so the runtime must have been seeded.
+func (c *Client) CreateMultiStepCheck(ctx context.Context, check checkly.MultiStepCheck) (*checkly.MultiStepCheck, error) {
+	if err := c.validateMultiStepRuntime(ctx, check); err != nil {
+		return nil, err
+	}
+	check.Type = checkly.TypeMultiStep
+	return createMonitor(ctx, c, "checks/multistep", check)
+}
+
+// UpdateMultiStepCheck updates a multistep check, validating its runtime
+// like CreateMultiStepCheck.
+func (c *Client) UpdateMultiStepCheck(ctx context.Context, ID string, check checkly.MultiStepCheck) (*checkly.MultiStepCheck, error) {
+	if err := c.validateMultiStepRuntime(ctx, check); err != nil {
+		return nil, err
+	}
+	check.Type = checkly.TypeMultiStep
+	return updateMonitor(ctx, c, "checks/multistep", ID, check)
+}
+
+func (c *Client) validateMultiStepRuntime(ctx context.Context, check checkly.MultiStepCheck) error {
+	if check.RuntimeID == nil {
+		return nil
+	}
+	runtime, err := c.GetRuntime(ctx, *check.RuntimeID)
+	if err != nil {
+		return err
+	}
+	if !runtime.MultiStepSupport {
+		return fmt.Errorf("user error: runtime %s does not support multistep checks", *check.RuntimeID)
+	}
+	return nil
+}
+
+func (c *Client) GetMultiStepCheck(ctx context.Context, ID string) (*checkly.MultiStepCheck, error) {
+	return getMonitor[checkly.MultiStepCheck](ctx, c, ID)
+}
+
+func (c *Client) DeleteMultiStepCheck(ctx context.Context, ID string) error {
+	return c.DeleteCheck(ctx, ID)
+}
+
+// GetAnyCheck returns a copy of the check with the given ID, with the
+// concrete type it was last created, updated or seeded with.
+func (c *Client) GetAnyCheck(ctx context.Context, ID string) (checkly.Monitor, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	monitor, ok := c.checks.get(ID)
+	if !ok {
+		return nil, notFound(http.MethodGet, "checks/"+ID)
+	}
+	return cloneMonitor(monitor), nil
+}
+
+// ListChecks returns a Pager over copies of the checks matching opts, in
+// creation order.
+func (c *Client) ListChecks(ctx context.Context, opts checkly.ListChecksOptions) *checkly.Pager[checkly.Monitor] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var result []checkly.Monitor
+	for _, monitor := range c.checks.values() {
+		if matchMonitor(opts, monitor) {
+			result = append(result, cloneMonitor(monitor))
+		}
+	}
+	return newSlicePager(result, opts.ListOptions)
+}
+
+func matchMonitor(opts checkly.ListChecksOptions, monitor checkly.Monitor) bool {
+	if opts.CheckType != "" && monitor.CheckType() != opts.CheckType {
+		return false
+	}
+	if opts.GroupID != 0 && monitor.GetGroupID() != opts.GroupID {
+		return false
+	}
+	if opts.Activated != nil && monitor.IsActivated() != *opts.Activated {
+		return false
+	}
+	if opts.Muted != nil && monitor.IsMuted() != *opts.Muted {
+		return false
+	}
+	for _, tag := range opts.Tags {
+		if !slices.Contains(monitor.GetTags(), tag) {
+			return false
+		}
+	}
+	return true
+}
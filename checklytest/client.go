@@ -0,0 +1,474 @@
+// Package checklytest provides an in-memory implementation of checkly.Client
+// for unit tests of code built on the SDK.
+//
+// The fake keeps state between calls: it assigns IDs to the resources it
+// creates, returns copies of what was stored, and enforces the referential
+// rules of the API, for example refusing to delete a group that still has
+// checks. Errors are returned as *checkly.APIError values with the status
+// codes the API uses, so checkly.IsNotFound, checkly.IsConflict and
+// checkly.IsValidationError work as they do with the real client:
+//
+//	client := checklytest.NewClient()
+//	if err := client.SeedFile(checklytest.Checks, "fixtures/GetCheck.json"); err != nil {
+//		t.Fatal(err)
+//	}
+//	runCodeUnderTest(client)
+package checklytest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"strconv"
+	"sync"
+
+	checkly "github.com/checkly/checkly-go-sdk"
+)
+
+// Resource identifies a collection of resources held by a Client, see
+// Client.Seed.
+type Resource string
+
+const (
+	Checks               Resource = "checks"
+	CheckResults         Resource = "check-results"
+	Groups               Resource = "check-groups"
+	AlertChannels        Resource = "alert-channels"
+	Snippets             Resource = "snippets"
+	EnvironmentVariables Resource = "variables"
+	Dashboards           Resource = "dashboards"
+	MaintenanceWindows   Resource = "maintenance-windows"
+	PrivateLocations     Resource = "private-locations"
+	ClientCertificates   Resource = "client-certificates"
+	StatusPages          Resource = "status-pages"
+	StatusPageServices   Resource = "status-pages/services"
+	Runtimes             Resource = "runtimes"
+)
+
+// Client is an in-memory implementation of checkly.Client. The zero value is
+// not usable, create instances with NewClient. A Client is safe for
+// concurrent use.
+type Client struct {
+	mu     sync.Mutex
+	lastID int64
+
+	accountID   string
+	source      string
+	retryPolicy checkly.RetryPolicy
+	rateLimiter *checkly.RateLimiter
+
+	checks             *store[string, checkly.Monitor]
+	checkResults       *store[string, checkly.CheckResult]
+	groups             *store[int64, checkly.GroupV2]
+	alertChannels      *store[int64, checkly.AlertChannel]
+	snippets           *store[int64, checkly.Snippet]
+	variables          *store[string, checkly.EnvironmentVariable]
+	dashboards         *store[string, checkly.Dashboard]
+	maintenanceWindows *store[int64, checkly.MaintenanceWindow]
+	privateLocations   *store[string, checkly.PrivateLocation]
+	clientCertificates *store[string, checkly.ClientCertificate]
+	statusPages        *store[string, checkly.StatusPage]
+	statusPageServices *store[string, checkly.StatusPageService]
+	runtimes           *store[string, checkly.Runtime]
+	checkTriggers      *store[string, checkly.TriggerCheck]
+	groupTriggers      *store[int64, checkly.TriggerGroup]
+	codeBundles        *store[string, checkly.CodeBundle]
+	staticIPs          []checkly.StaticIP
+}
+
+var _ checkly.Client = (*Client)(nil)
+
+// NewClient returns an empty Client.
+func NewClient() *Client {
+	return &Client{
+		checks:             newStore[string, checkly.Monitor](),
+		checkResults:       newStore[string, checkly.CheckResult](),
+		groups:             newStore[int64, checkly.GroupV2](),
+		alertChannels:      newStore[int64, checkly.AlertChannel](),
+		snippets:           newStore[int64, checkly.Snippet](),
+		variables:          newStore[string, checkly.EnvironmentVariable](),
+		dashboards:         newStore[string, checkly.Dashboard](),
+		maintenanceWindows: newStore[int64, checkly.MaintenanceWindow](),
+		privateLocations:   newStore[string, checkly.PrivateLocation](),
+		clientCertificates: newStore[string, checkly.ClientCertificate](),
+		statusPages:        newStore[string, checkly.StatusPage](),
+		statusPageServices: newStore[string, checkly.StatusPageService](),
+		runtimes:           newStore[string, checkly.Runtime](),
+		checkTriggers:      newStore[string, checkly.TriggerCheck](),
+		groupTriggers:      newStore[int64, checkly.TriggerGroup](),
+		codeBundles:        newStore[string, checkly.CodeBundle](),
+	}
+}
+
+// SeedFile adds the resources held in the JSON file at path, for example one
+// of the SDK's fixtures, to the collection identified by resource. See Seed
+// for the accepted formats.
+func (c *Client) SeedFile(resource Resource, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return c.Seed(resource, data)
+}
+
+// Seed adds the resources encoded in data, as returned by the API, to the
+// collection identified by resource. data holds either a single resource, an
+// array of resources or a list page with the resources in an "entries"
+// field. Resources without an ID are assigned one, and resources with the ID
+// of a stored one replace it.
+//
+// Seeding bypasses the referential rules enforced by the other methods, so
+// that fixtures can be loaded in any order.
+func (c *Client) Seed(resource Resource, data []byte) error {
+	items, err := splitSeedData(data)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, item := range items {
+		if err := c.seed(resource, item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Client) seed(resource Resource, data []byte) error {
+	switch resource {
+	case Checks:
+		monitor, err := checkly.DecodeMonitor(data)
+		if err != nil {
+			return err
+		}
+		if monitor.GetID() == "" {
+			setMonitorID(monitor, c.newUUID())
+		}
+		c.checks.put(monitor.GetID(), monitor)
+		return nil
+	case CheckResults:
+		return seedItem(c, data, c.checkResults, func(r *checkly.CheckResult) *string { return &r.ID }, c.newUUID)
+	case Groups:
+		return seedItem(c, data, c.groups, func(g *checkly.GroupV2) *int64 { return &g.ID }, c.newID)
+	case AlertChannels:
+		ac, err := decodeAlertChannel(data)
+		if err != nil {
+			return err
+		}
+		c.seenID(ac.ID)
+		if ac.ID == 0 {
+			ac.ID = c.newID()
+		}
+		c.alertChannels.put(ac.ID, *ac)
+		return nil
+	case Snippets:
+		return seedItem(c, data, c.snippets, func(s *checkly.Snippet) *int64 { return &s.ID }, c.newID)
+	case EnvironmentVariables:
+		return seedItem(c, data, c.variables, func(v *checkly.EnvironmentVariable) *string { return &v.Key }, c.newUUID)
+	case Dashboards:
+		return seedItem(c, data, c.dashboards, func(d *checkly.Dashboard) *string { return &d.DashboardID }, c.newUUID)
+	case MaintenanceWindows:
+		return seedItem(c, data, c.maintenanceWindows, func(mw *checkly.MaintenanceWindow) *int64 { return &mw.ID }, c.newID)
+	case PrivateLocations:
+		return seedItem(c, data, c.privateLocations, func(pl *checkly.PrivateLocation) *string { return &pl.ID }, c.newUUID)
+	case ClientCertificates:
+		return seedItem(c, data, c.clientCertificates, func(cc *checkly.ClientCertificate) *string { return &cc.ID }, c.newUUID)
+	case StatusPages:
+		return seedItem(c, data, c.statusPages, func(p *checkly.StatusPage) *string { return &p.ID }, c.newUUID)
+	case StatusPageServices:
+		return seedItem(c, data, c.statusPageServices, func(s *checkly.StatusPageService) *string { return &s.ID }, c.newUUID)
+	case Runtimes:
+		return seedItem(c, data, c.runtimes, func(r *checkly.Runtime) *string { return &r.Name }, c.newUUID)
+	default:
+		return fmt.Errorf("unknown resource: %s", resource)
+	}
+}
+
+// seedItem decodes data into a V and stores it under the ID returned by id,
+// assigning a new one if it is empty.
+func seedItem[K comparable, V any](
+	c *Client,
+	data []byte,
+	s *store[K, V],
+	id func(*V) *K,
+	newID func() K,
+) error {
+	var item V
+	if err := json.Unmarshal(data, &item); err != nil {
+		return fmt.Errorf("decoding error for data %s: %v", data, err)
+	}
+	key := id(&item)
+	var zero K
+	if *key == zero {
+		*key = newID()
+	} else if n, ok := any(*key).(int64); ok {
+		c.seenID(n)
+	}
+	s.put(*key, item)
+	return nil
+}
+
+func splitSeedData(data []byte) ([]json.RawMessage, error) {
+	data = bytes.TrimSpace(data)
+	if bytes.HasPrefix(data, []byte("[")) {
+		var items []json.RawMessage
+		if err := json.Unmarshal(data, &items); err != nil {
+			return nil, fmt.Errorf("decoding error for data %s: %v", data, err)
+		}
+		return items, nil
+	}
+	var envelope struct {
+		Entries []json.RawMessage `json:"entries"`
+	}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("decoding error for data %s: %v", data, err)
+	}
+	if envelope.Entries != nil {
+		return envelope.Entries, nil
+	}
+	return []json.RawMessage{data}, nil
+}
+
+// newID returns a new numeric ID. c.mu must be held.
+func (c *Client) newID() int64 {
+	c.lastID++
+	return c.lastID
+}
+
+// seenID records that ID is in use, so that newID doesn't return it. c.mu
+// must be held.
+func (c *Client) seenID(ID int64) {
+	if ID > c.lastID {
+		c.lastID = ID
+	}
+}
+
+// newUUID returns a new ID in the format of the API's UUIDs. c.mu must be
+// held.
+func (c *Client) newUUID() string {
+	return fmt.Sprintf("00000000-0000-4000-8000-%012d", c.newID())
+}
+
+// SetAccountId records the account ID, which the fake otherwise ignores.
+func (c *Client) SetAccountId(ID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.accountID = ID
+}
+
+// SetChecklySource records the source, which the fake otherwise ignores.
+func (c *Client) SetChecklySource(source string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.source = source
+}
+
+// SetRetryPolicy records the policy. The fake never fails transiently, so it
+// never retries.
+func (c *Client) SetRetryPolicy(policy checkly.RetryPolicy) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.retryPolicy = policy
+}
+
+// SetRateLimiter records the limiter, which the fake doesn't wait on.
+func (c *Client) SetRateLimiter(limiter *checkly.RateLimiter) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rateLimiter = limiter
+}
+
+// apiErrorBody is the error payload returned by the Checkly API.
+type apiErrorBody struct {
+	StatusCode int                         `json:"statusCode"`
+	Error      string                      `json:"error"`
+	Message    string                      `json:"message"`
+	Validation *checkly.APIErrorValidation `json:"validation,omitempty"`
+}
+
+// apiError returns the error the client returns when the API replies to a
+// request to path with status. keys name the payload fields which failed
+// validation, if any.
+func apiError(method, path string, status int, message string, keys ...string) error {
+	body := apiErrorBody{
+		StatusCode: status,
+		Error:      http.StatusText(status),
+		Message:    message,
+	}
+	if len(keys) > 0 {
+		body.Validation = &checkly.APIErrorValidation{
+			Source: "payload",
+			Keys:   keys,
+		}
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		panic(err)
+	}
+	return &checkly.APIError{
+		StatusCode: status,
+		Method:     method,
+		Path:       "/v1/" + path,
+		Reason:     body.Error,
+		Message:    message,
+		Validation: body.Validation,
+		Body:       string(data),
+		Header:     http.Header{},
+	}
+}
+
+func notFound(method, path string) error {
+	return apiError(method, path, http.StatusNotFound, "Not Found")
+}
+
+func conflict(method, path, message string) error {
+	return apiError(method, path, http.StatusConflict, message)
+}
+
+func invalid(method, path, key, message string) error {
+	return apiError(method, path, http.StatusBadRequest, message, key)
+}
+
+// store holds the resources of a collection in creation order.
+type store[K comparable, V any] struct {
+	items map[K]V
+	keys  []K
+}
+
+func newStore[K comparable, V any]() *store[K, V] {
+	return &store[K, V]{items: map[K]V{}}
+}
+
+func (s *store[K, V]) get(key K) (V, bool) {
+	value, ok := s.items[key]
+	return value, ok
+}
+
+func (s *store[K, V]) put(key K, value V) {
+	if _, ok := s.items[key]; !ok {
+		s.keys = append(s.keys, key)
+	}
+	s.items[key] = value
+}
+
+func (s *store[K, V]) delete(key K) bool {
+	if _, ok := s.items[key]; !ok {
+		return false
+	}
+	delete(s.items, key)
+	for i, k := range s.keys {
+		if k == key {
+			s.keys = append(s.keys[:i:i], s.keys[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+// values returns the stored values in creation order.
+func (s *store[K, V]) values() []V {
+	result := make([]V, 0, len(s.keys))
+	for _, key := range s.keys {
+		result = append(result, s.items[key])
+	}
+	return result
+}
+
+// clone returns a deep copy of v, made by encoding it to JSON and back so that
+// what the caller gets matches what the real client would decode.
+func clone[T any](v T) T {
+	var result T
+	convert(v, &result)
+	return result
+}
+
+// convert copies from into to through their JSON encoding, e.g. to convert a
+// Group to a GroupV2.
+func convert(from, to any) {
+	data, err := json.Marshal(from)
+	if err != nil {
+		panic(err)
+	}
+	if err := json.Unmarshal(data, to); err != nil {
+		panic(err)
+	}
+}
+
+// cloneMonitor returns a deep copy of monitor with the same concrete type.
+func cloneMonitor(monitor checkly.Monitor) checkly.Monitor {
+	if m, ok := monitor.(*checkly.UnknownMonitor); ok {
+		result := *m
+		result.Raw = append(json.RawMessage(nil), m.Raw...)
+		return &result
+	}
+	result := reflect.New(reflect.TypeOf(monitor).Elem()).Interface().(checkly.Monitor)
+	convert(monitor, result)
+	return result
+}
+
+// setMonitorID sets the ID of monitor, which the Monitor interface doesn't
+// allow.
+func setMonitorID(monitor checkly.Monitor, ID string) {
+	reflect.ValueOf(monitor).Elem().FieldByName("ID").SetString(ID)
+}
+
+// cloneAlertChannel returns a deep copy of ac. The type specific config of
+// alert channels isn't part of their JSON encoding, so it is copied through
+// GetConfig and SetConfig.
+func cloneAlertChannel(ac checkly.AlertChannel) checkly.AlertChannel {
+	result := clone(ac)
+	if cfg := ac.GetConfig(); cfg != nil {
+		data, err := json.Marshal(cfg)
+		if err != nil {
+			panic(err)
+		}
+		if cfg, err := checkly.AlertChannelConfigFromJSON(ac.Type, data); err == nil {
+			result.SetConfig(cfg)
+		}
+	}
+	return result
+}
+
+// decodeAlertChannel decodes an alert channel as returned by the API.
+func decodeAlertChannel(data []byte) (*checkly.AlertChannel, error) {
+	var ac struct {
+		checkly.AlertChannel
+		Config json.RawMessage `json:"config"`
+	}
+	if err := json.Unmarshal(data, &ac); err != nil {
+		return nil, fmt.Errorf("decoding error for data %s: %v", data, err)
+	}
+	result := ac.AlertChannel
+	if len(ac.Config) > 0 {
+		cfg, err := checkly.AlertChannelConfigFromJSON(result.Type, ac.Config)
+		if err != nil {
+			return nil, err
+		}
+		result.SetConfig(cfg)
+	}
+	return &result, nil
+}
+
+// newSlicePager returns a Pager over items, split in pages of the size set
+// in opts.
+func newSlicePager[T any](items []T, opts checkly.ListOptions) *checkly.Pager[T] {
+	pageSize := opts.PageSize
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 100
+	}
+	return checkly.NewPager(opts, func(ctx context.Context, token string) ([]T, string, error) {
+		if err := ctx.Err(); err != nil {
+			return nil, "", err
+		}
+		start, _ := strconv.Atoi(token)
+		end := start + pageSize
+		if end >= len(items) {
+			return items[start:], "", nil
+		}
+		return items[start:end], strconv.Itoa(end), nil
+	})
+}
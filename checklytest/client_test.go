@@ -0,0 +1,286 @@
+package checklytest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	checkly "github.com/checkly/checkly-go-sdk"
+	"github.com/checkly/checkly-go-sdk/checklytest"
+)
+
+func TestMonitorLifecycle(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	client := checklytest.NewClient()
+	monitor := checkly.URLMonitor{
+		Name:      "URL Monitor",
+		Frequency: 10,
+		Activated: true,
+		Locations: []string{"eu-central-1"},
+		Tags:      []string{"url"},
+		Request: checkly.URLRequest{
+			URL: "https://example.com",
+		},
+	}
+	created, err := client.CreateURLMonitor(ctx, monitor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.ID == "" {
+		t.Fatal("want an ID to be assigned")
+	}
+	// The returned value must not alias the stored one.
+	created.Tags[0] = "changed"
+	got, err := client.GetURLMonitor(ctx, created.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	monitor.ID = created.ID
+	if !cmp.Equal(monitor, *got) {
+		t.Error(cmp.Diff(monitor, *got))
+	}
+	monitor.Name = "Renamed"
+	if _, err := client.UpdateURLMonitor(ctx, created.ID, monitor); err != nil {
+		t.Fatal(err)
+	}
+	anyCheck, err := client.GetAnyCheck(ctx, created.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m, ok := anyCheck.(*checkly.URLMonitor); !ok || m.Name != "Renamed" {
+		t.Errorf("want the renamed *checkly.URLMonitor, got %#v", anyCheck)
+	}
+	if err := client.DeleteURLMonitor(ctx, created.ID); err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.GetURLMonitor(ctx, created.ID)
+	if !checkly.IsNotFound(err) {
+		t.Errorf("want a not found error after deletion, got %v", err)
+	}
+}
+
+func TestUpdateMonitorOfAnotherType(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	client := checklytest.NewClient()
+	created, err := client.CreateTCPMonitor(ctx, checkly.TCPMonitor{Name: "TCP Monitor"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.UpdateURLMonitor(ctx, created.ID, checkly.URLMonitor{Name: "URL Monitor"})
+	if !checkly.IsValidationError(err) {
+		t.Errorf("want a validation error, got %v", err)
+	}
+	if _, err := client.UpdateURLMonitor(ctx, "unknown", checkly.URLMonitor{Name: "URL Monitor"}); !checkly.IsNotFound(err) {
+		t.Errorf("want a not found error for an unknown ID, got %v", err)
+	}
+
+	// Checks of the same type are updated whatever their Go type.
+	check, err := client.CreateCheck(ctx, checkly.Check{Name: "Multistep", Type: checkly.TypeMultiStep})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.UpdateMultiStepCheck(ctx, check.ID, checkly.MultiStepCheck{Name: "Renamed"}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := client.GetCheck(ctx, check.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Name != "Renamed" || got.Type != checkly.TypeMultiStep {
+		t.Errorf("want the renamed multistep check, got %q of type %s", got.Name, got.Type)
+	}
+}
+
+func TestCreateCheckOfMonitorType(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	client := checklytest.NewClient()
+	for _, checkType := range []string{checkly.TypeTCP, "UNKNOWN"} {
+		_, want := checkly.New().CreateCheck(ctx, checkly.Check{Type: checkType})
+		_, err := client.CreateCheck(ctx, checkly.Check{Type: checkType})
+		if err == nil || want == nil || err.Error() != want.Error() {
+			t.Errorf("want the error of the client %v for %s checks, got %v", want, checkType, err)
+		}
+	}
+}
+
+func TestGroupReferences(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	client := checklytest.NewClient()
+	_, err := client.CreateTCPMonitor(ctx, checkly.TCPMonitor{Name: "TCP Monitor", GroupID: 42})
+	if !checkly.IsValidationError(err) {
+		t.Errorf("want a validation error for an unknown group, got %v", err)
+	}
+	group, err := client.CreateGroupV2(ctx, checkly.GroupV2{Name: "Group"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	monitor, err := client.CreateTCPMonitor(ctx, checkly.TCPMonitor{Name: "TCP Monitor", GroupID: group.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = client.DeleteGroupV2(ctx, group.ID)
+	if !checkly.IsConflict(err) {
+		t.Errorf("want a conflict when deleting a group with checks, got %v", err)
+	}
+	if err := client.DeleteTCPMonitor(ctx, monitor.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.DeleteGroupV2(ctx, group.ID); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestAlertChannelSubscriptions(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	client := checklytest.NewClient()
+	subscribe := func(ID int64) []checkly.AlertChannelSubscription {
+		return []checkly.AlertChannelSubscription{{ChannelID: ID, Activated: true}}
+	}
+	_, err := client.CreateGroup(ctx, checkly.Group{Name: "Group", AlertChannelSubscriptions: subscribe(7)})
+	if !checkly.IsValidationError(err) {
+		t.Errorf("want a validation error for an unknown alert channel, got %v", err)
+	}
+	ac := checkly.AlertChannel{Type: checkly.AlertTypeEmail}
+	ac.SetConfig(&checkly.AlertChannelEmail{Address: "alerts@example.com"})
+	channel, err := client.CreateAlertChannel(ctx, ac)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if channel.Email == nil || channel.Email.Address != "alerts@example.com" {
+		t.Errorf("want the email config to be kept, got %#v", channel.Email)
+	}
+	if _, err := client.CreateGroup(ctx, checkly.Group{Name: "Group", AlertChannelSubscriptions: subscribe(channel.ID)}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCreateEnvironmentVariableConflict(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	client := checklytest.NewClient()
+	envVar := checkly.EnvironmentVariable{Key: "API_KEY", Value: "secret"}
+	if _, err := client.CreateEnvironmentVariable(ctx, envVar); err != nil {
+		t.Fatal(err)
+	}
+	_, err := client.CreateEnvironmentVariable(ctx, envVar)
+	if !checkly.IsConflict(err) {
+		t.Errorf("want a conflict for a duplicate key, got %v", err)
+	}
+}
+
+func TestErrorsMatchRealClient(t *testing.T) {
+	t.Parallel()
+	client := checklytest.NewClient()
+	_, err := client.GetCheck(context.Background(), "missing")
+	var apiErr *checkly.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("want a *checkly.APIError, got %T", err)
+	}
+	if apiErr.Path != "/v1/checks/missing" || apiErr.Reason != "Not Found" {
+		t.Errorf("want the path and reason of the API, got %q and %q", apiErr.Path, apiErr.Reason)
+	}
+	want := `unexpected response status 404: "{\"statusCode\":404,\"error\":\"Not Found\",\"message\":\"Not Found\"}"`
+	if err.Error() != want {
+		t.Errorf("want error %s, got %s", want, err)
+	}
+}
+
+func TestSeedFromFixtures(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	client := checklytest.NewClient()
+	for _, seed := range []struct {
+		resource checklytest.Resource
+		path     string
+	}{
+		{checklytest.Checks, "../fixtures/ListChecksPage1.json"},
+		{checklytest.Checks, "../fixtures/ListChecksPage2.json"},
+		{checklytest.Groups, "../fixtures/ListGroupsPage1.json"},
+		{checklytest.AlertChannels, "../fixtures/CreateAlertChannelEmail.json"},
+		{checklytest.StatusPages, "../fixtures/ListStatusPages.json"},
+	} {
+		if err := client.SeedFile(seed.resource, seed.path); err != nil {
+			t.Fatalf("seeding %s: %v", seed.path, err)
+		}
+	}
+	checks, err := client.ListChecks(ctx, checkly.ListChecksOptions{
+		ListOptions: checkly.ListOptions{PageSize: 2},
+	}).Collect(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var types []string
+	for _, check := range checks {
+		types = append(types, check.CheckType())
+	}
+	wantTypes := []string{"API", "TCP", "HEARTBEAT", "URL", "QUANTUM"}
+	if !cmp.Equal(wantTypes, types) {
+		t.Error(cmp.Diff(wantTypes, types))
+	}
+	inGroup, err := client.ListChecks(ctx, checkly.ListChecksOptions{GroupID: 15}).Collect(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(inGroup) != 2 {
+		t.Errorf("want 2 checks in group 15, got %d", len(inGroup))
+	}
+	channel, err := client.GetAlertChannel(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if channel.Email == nil || channel.Email.Address != "test@example.com" {
+		t.Errorf("want the seeded email config, got %#v", channel.Email)
+	}
+	// IDs assigned after seeding must not clash with the seeded ones.
+	group, err := client.CreateGroup(ctx, checkly.Group{Name: "New group"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if group.ID <= 2 {
+		t.Errorf("want a new group ID, got %d", group.ID)
+	}
+	if _, err := client.GetStatusPage(ctx, "cd8d05a4-c292-4dc4-a78f-1dea65e5457e"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCreateMultiStepCheckRuntime(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	client := checklytest.NewClient()
+	if err := client.SeedFile(checklytest.Runtimes, "../fixtures/GetRuntimeNoMultiStep.json"); err != nil {
+		t.Fatal(err)
+	}
+	runtimeID := "2022.10"
+	_, err := client.CreateMultiStepCheck(ctx, checkly.MultiStepCheck{Name: "Multistep", RuntimeID: &runtimeID})
+	if err == nil {
+		t.Error("want an error for a runtime without multistep support, got nil")
+	}
+	check, err := client.CreateMultiStepCheck(ctx, checkly.MultiStepCheck{Name: "Multistep"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if check.Type != checkly.TypeMultiStep {
+		t.Errorf("want check type %q, got %q", checkly.TypeMultiStep, check.Type)
+	}
+}
+
+func TestGenericMonitorFunctions(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	client := checklytest.NewClient()
+	monitor, err := checkly.CreateMonitor(ctx, client, &checkly.DNSMonitor{Name: "DNS Monitor"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := checkly.DeleteMonitor(ctx, client, monitor); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,125 @@
+package checklytest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	checkly "github.com/checkly/checkly-go-sdk"
+)
+
+// collection implements the CRUD methods of the resources stored in items,
+// which are identified by the field returned by id.
+type collection[K comparable, V any] struct {
+	c        *Client
+	resource Resource
+	items    *store[K, V]
+	id       func(item *V) *K
+	// newID returns the ID of a new item. It is nil for resources whose ID
+	// is chosen by the caller, such as environment variables.
+	newID func() K
+	// clone returns a deep copy of an item. It defaults to clone.
+	clone func(item V) V
+	// validate, if set, checks an item about to be created (POST) or updated
+	// (PUT).
+	validate func(method, path string, item *V) error
+	// beforeDelete, if set, is called with the ID of an item about to be
+	// deleted. It can refuse the deletion, or delete dependent resources.
+	beforeDelete func(key K) error
+}
+
+func (col collection[K, V]) path(key K) string {
+	return fmt.Sprintf("%s/%v", col.resource, key)
+}
+
+func (col collection[K, V]) copy(item V) V {
+	if col.clone != nil {
+		return col.clone(item)
+	}
+	return clone(item)
+}
+
+func (col collection[K, V]) create(ctx context.Context, item V) (*V, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	col.c.mu.Lock()
+	defer col.c.mu.Unlock()
+	stored := col.copy(item)
+	if col.validate != nil {
+		if err := col.validate(http.MethodPost, string(col.resource), &stored); err != nil {
+			return nil, err
+		}
+	}
+	if col.newID != nil {
+		*col.id(&stored) = col.newID()
+	}
+	col.items.put(*col.id(&stored), stored)
+	result := col.copy(stored)
+	return &result, nil
+}
+
+func (col collection[K, V]) get(ctx context.Context, key K) (*V, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	col.c.mu.Lock()
+	defer col.c.mu.Unlock()
+	item, ok := col.items.get(key)
+	if !ok {
+		return nil, notFound(http.MethodGet, col.path(key))
+	}
+	result := col.copy(item)
+	return &result, nil
+}
+
+func (col collection[K, V]) update(ctx context.Context, key K, item V) (*V, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	col.c.mu.Lock()
+	defer col.c.mu.Unlock()
+	path := col.path(key)
+	if _, ok := col.items.get(key); !ok {
+		return nil, notFound(http.MethodPut, path)
+	}
+	stored := col.copy(item)
+	*col.id(&stored) = key
+	if col.validate != nil {
+		if err := col.validate(http.MethodPut, path, &stored); err != nil {
+			return nil, err
+		}
+	}
+	col.items.put(key, stored)
+	result := col.copy(stored)
+	return &result, nil
+}
+
+func (col collection[K, V]) delete(ctx context.Context, key K) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	col.c.mu.Lock()
+	defer col.c.mu.Unlock()
+	if _, ok := col.items.get(key); !ok {
+		return notFound(http.MethodDelete, col.path(key))
+	}
+	if col.beforeDelete != nil {
+		if err := col.beforeDelete(key); err != nil {
+			return err
+		}
+	}
+	col.items.delete(key)
+	return nil
+}
+
+// list returns a Pager over copies of the items, in creation order.
+func (col collection[K, V]) list(opts checkly.ListOptions) *checkly.Pager[V] {
+	col.c.mu.Lock()
+	defer col.c.mu.Unlock()
+	items := col.items.values()
+	for i, item := range items {
+		items[i] = col.copy(item)
+	}
+	return newSlicePager(items, opts)
+}
@@ -0,0 +1,429 @@
+package checklytest
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// ErrNoInteraction is returned by a replaying Recorder for a request that
+// doesn't match any recorded interaction.
+var ErrNoInteraction = errors.New("checklytest: no recorded interaction matches the request")
+
+// Redacted replaces secrets in the cassettes written by a Recorder.
+const Redacted = "REDACTED"
+
+// RecorderMode selects whether a Recorder captures or replays traffic.
+type RecorderMode int
+
+const (
+	// Replay answers requests from a cassette without using the network.
+	Replay RecorderMode = iota
+	// Record sends requests to the underlying transport and writes the
+	// interactions to a cassette when the Recorder is stopped.
+	Record
+)
+
+// Matching selects how a replaying Recorder pairs requests with the recorded
+// interactions.
+type Matching int
+
+const (
+	// MatchStrict replays interactions in the order they were recorded. Each
+	// request must have the method, path, query and body of the next one.
+	MatchStrict Matching = iota
+	// MatchLoose replays the first unused interaction with the method and
+	// path of the request, regardless of order, query or body.
+	MatchLoose
+)
+
+// defaultRedactedFields are the JSON fields holding secrets in the payloads of
+// the API. Some fields are only secret in context and are handled by
+// redactValue: the "key" of a client certificate, which names environment
+// variables elsewhere, and the "value" of secret environment variables.
+var defaultRedactedFields = []string{"webhookSecret", "passphrase", "rawKey"}
+
+var redactedHeaders = []string{"Authorization", "Cookie", "Set-Cookie"}
+
+// Recorder is an http.RoundTripper which records the traffic of the SDK to a
+// cassette file and replays it, so that tests can run without network access:
+//
+//	rec, err := checklytest.NewRecorder("testdata/checks.json", checklytest.Replay)
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	defer rec.Stop()
+//	client := checkly.NewClient(baseURL, apiKey, rec.HTTPClient(), nil)
+//
+// The Authorization header and secrets in request and response bodies, such as
+// webhook secrets, client certificate keys and the values of secret
+// environment variables, are replaced with Redacted before they are written.
+//
+// A Recorder is safe for concurrent use.
+type Recorder struct {
+	path      string
+	mode      RecorderMode
+	matching  Matching
+	transport http.RoundTripper
+	redact    map[string]bool
+
+	mu           sync.Mutex
+	interactions []interaction
+	used         []bool
+}
+
+// RecorderOption configures a Recorder constructed with NewRecorder.
+type RecorderOption func(*Recorder)
+
+// WithTransport sets the transport a recording Recorder sends requests to. It
+// defaults to http.DefaultTransport.
+func WithTransport(transport http.RoundTripper) RecorderOption {
+	return func(r *Recorder) {
+		r.transport = transport
+	}
+}
+
+// WithMatching sets how a replaying Recorder matches requests, MatchStrict
+// by default.
+func WithMatching(matching Matching) RecorderOption {
+	return func(r *Recorder) {
+		r.matching = matching
+	}
+}
+
+// WithRedactedFields adds JSON fields to redact from the recorded bodies.
+func WithRedactedFields(fields ...string) RecorderOption {
+	return func(r *Recorder) {
+		for _, f := range fields {
+			r.redact[f] = true
+		}
+	}
+}
+
+// NewRecorder returns a Recorder for the cassette at path. In Replay mode the
+// cassette is read immediately and must exist.
+func NewRecorder(path string, mode RecorderMode, opts ...RecorderOption) (*Recorder, error) {
+	r := &Recorder{
+		path:      path,
+		mode:      mode,
+		transport: http.DefaultTransport,
+		redact:    map[string]bool{},
+	}
+	for _, f := range defaultRedactedFields {
+		r.redact[f] = true
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	if mode == Replay {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var c cassette
+		if err := json.Unmarshal(data, &c); err != nil {
+			return nil, fmt.Errorf("decoding cassette %s: %w", path, err)
+		}
+		r.interactions = c.Interactions
+		r.used = make([]bool, len(c.Interactions))
+	}
+	return r, nil
+}
+
+// HTTPClient returns an HTTP client which sends its requests through r.
+func (r *Recorder) HTTPClient() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	recorded := recordedRequest{
+		Method: req.Method,
+		URL:    req.URL.String(),
+		Header: r.redactHeader(req.Header),
+	}
+	recorded.setBody(r.redactBody(body))
+
+	if r.mode == Replay {
+		return r.replay(req, recorded)
+	}
+	return r.record(req, recorded, body)
+}
+
+// Stop finishes the session. A recording Recorder writes its cassette, a
+// strictly matching replaying one reports the interactions which weren't
+// replayed.
+func (r *Recorder) Stop() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.mode == Replay {
+		if r.matching != MatchStrict {
+			return nil
+		}
+		first, unused := -1, 0
+		for i, used := range r.used {
+			if !used {
+				if first < 0 {
+					first = i
+				}
+				unused++
+			}
+		}
+		if unused > 0 {
+			in := r.interactions[first].Request
+			return fmt.Errorf("checklytest: %d interactions not replayed, the first is %s %s", unused, in.Method, in.URL)
+		}
+		return nil
+	}
+	data, err := json.MarshalIndent(cassette{Interactions: r.interactions}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, append(data, '\n'), 0o644)
+}
+
+func (r *Recorder) record(req *http.Request, recorded recordedRequest, body []byte) (*http.Response, error) {
+	out := req.Clone(req.Context())
+	out.Body = io.NopCloser(bytes.NewReader(body))
+	out.ContentLength = int64(len(body))
+	resp, err := r.transport.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	response := recordedResponse{
+		Status: resp.StatusCode,
+		Header: r.redactHeader(resp.Header),
+	}
+	response.setBody(r.redactBody(respBody))
+
+	r.mu.Lock()
+	r.interactions = append(r.interactions, interaction{Request: recorded, Response: response})
+	r.mu.Unlock()
+
+	// The caller gets the unredacted body, as it would without a Recorder.
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	resp.ContentLength = int64(len(respBody))
+	return resp, nil
+}
+
+func (r *Recorder) replay(req *http.Request, recorded recordedRequest) (*http.Response, error) {
+	if err := req.Context().Err(); err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := r.match(recorded)
+	if i < 0 {
+		return nil, fmt.Errorf("%w: %s %s", ErrNoInteraction, req.Method, req.URL)
+	}
+	r.used[i] = true
+	body, err := r.interactions[i].Response.body()
+	if err != nil {
+		return nil, err
+	}
+	rec := r.interactions[i].Response
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", rec.Status, http.StatusText(rec.Status)),
+		StatusCode:    rec.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        rec.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// match returns the index of the interaction to replay for req, or -1. It must
+// be called with r.mu held.
+func (r *Recorder) match(req recordedRequest) int {
+	if r.matching == MatchStrict {
+		for i, used := range r.used {
+			if used {
+				continue
+			}
+			in := r.interactions[i].Request
+			if in.Method == req.Method && requestTarget(in.URL, true) == requestTarget(req.URL, true) &&
+				sameBody(in, req) {
+				return i
+			}
+			return -1
+		}
+		return -1
+	}
+	for i, used := range r.used {
+		in := r.interactions[i].Request
+		if !used && in.Method == req.Method && requestTarget(in.URL, false) == requestTarget(req.URL, false) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (r *Recorder) redactHeader(header http.Header) http.Header {
+	header = header.Clone()
+	for _, name := range redactedHeaders {
+		if header.Get(name) != "" {
+			header.Set(name, Redacted)
+		}
+	}
+	return header
+}
+
+// redactBody replaces the secrets in a JSON body. Other bodies are returned
+// unchanged.
+func (r *Recorder) redactBody(body []byte) []byte {
+	var v any
+	if len(body) == 0 || json.Unmarshal(body, &v) != nil {
+		return body
+	}
+	if !r.redactValue(v) {
+		return body
+	}
+	redacted, err := json.Marshal(v)
+	if err != nil {
+		return body
+	}
+	return redacted
+}
+
+// redactValue redacts v in place and reports whether anything changed.
+func (r *Recorder) redactValue(v any) bool {
+	changed := false
+	switch v := v.(type) {
+	case map[string]any:
+		_, isCertificate := v["cert"]
+		isSecret := v["secret"] == true
+		for k, field := range v {
+			secret := r.redact[k] || k == "key" && isCertificate || k == "value" && isSecret
+			if s, ok := field.(string); ok && s != "" && secret {
+				v[k] = Redacted
+				changed = true
+				continue
+			}
+			changed = r.redactValue(field) || changed
+		}
+	case []any:
+		for _, item := range v {
+			changed = r.redactValue(item) || changed
+		}
+	}
+	return changed
+}
+
+// requestTarget returns the path of a recorded URL, with the query if
+// withQuery is set. The host is ignored so that cassettes can be replayed
+// against another base URL.
+func requestTarget(rawURL string, withQuery bool) string {
+	target := rawURL
+	if i := strings.Index(target, "://"); i >= 0 {
+		target = target[i+3:]
+		if j := strings.IndexByte(target, '/'); j >= 0 {
+			target = target[j:]
+		} else {
+			target = "/"
+		}
+	}
+	path, query, _ := strings.Cut(target, "?")
+	if !withQuery || query == "" {
+		return path
+	}
+	// Compare the query independently of the order of its parameters.
+	params := strings.Split(query, "&")
+	sort.Strings(params)
+	return path + "?" + strings.Join(params, "&")
+}
+
+// sameBody compares two request bodies, semantically if both are JSON.
+func sameBody(a, b recordedRequest) bool {
+	if bytes.Equal(a.Body, b.Body) && a.BodyText == b.BodyText && a.BodyBase64 == b.BodyBase64 {
+		return true
+	}
+	var av, bv any
+	if json.Unmarshal(a.Body, &av) != nil || json.Unmarshal(b.Body, &bv) != nil {
+		return false
+	}
+	aj, _ := json.Marshal(av)
+	bj, _ := json.Marshal(bv)
+	return bytes.Equal(aj, bj)
+}
+
+type cassette struct {
+	Interactions []interaction `json:"interactions"`
+}
+
+type interaction struct {
+	Request  recordedRequest  `json:"request"`
+	Response recordedResponse `json:"response"`
+}
+
+// recordedBody holds a JSON body as is, other UTF-8 text as a string and
+// anything else base64 encoded.
+type recordedBody struct {
+	Body       json.RawMessage `json:"body,omitempty"`
+	BodyText   string          `json:"bodyText,omitempty"`
+	BodyBase64 string          `json:"bodyBase64,omitempty"`
+}
+
+func (b *recordedBody) setBody(data []byte) {
+	switch {
+	case len(data) == 0:
+	case json.Valid(data):
+		b.Body = data
+	case utf8.Valid(data):
+		b.BodyText = string(data)
+	default:
+		b.BodyBase64 = base64.StdEncoding.EncodeToString(data)
+	}
+}
+
+func (b *recordedBody) body() ([]byte, error) {
+	if b.BodyBase64 != "" {
+		return base64.StdEncoding.DecodeString(b.BodyBase64)
+	}
+	if b.BodyText != "" {
+		return []byte(b.BodyText), nil
+	}
+	return b.Body, nil
+}
+
+type recordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	recordedBody
+}
+
+type recordedResponse struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+	recordedBody
+}
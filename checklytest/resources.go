@@ -0,0 +1,662 @@
+package checklytest
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	checkly "github.com/checkly/checkly-go-sdk"
+)
+
+func (c *Client) groupCollection() collection[int64, checkly.GroupV2] {
+	return collection[int64, checkly.GroupV2]{
+		c:        c,
+		resource: Groups,
+		items:    c.groups,
+		id:       func(g *checkly.GroupV2) *int64 { return &g.ID },
+		newID:    c.newID,
+		validate: func(method, path string, g *checkly.GroupV2) error {
+			return c.checkSubscriptions(method, path, g)
+		},
+		beforeDelete: func(ID int64) error {
+			for _, monitor := range c.checks.values() {
+				if monitor.GetGroupID() == ID {
+					return conflict(http.MethodDelete, fmt.Sprintf("%s/%d", Groups, ID),
+						fmt.Sprintf("check group %d still has checks", ID))
+				}
+			}
+			c.groupTriggers.delete(ID)
+			return nil
+		},
+	}
+}
+
+// CreateGroup creates a group. Groups are stored once for both Group and
+// GroupV2, converted between the two through their JSON encoding.
+func (c *Client) CreateGroup(ctx context.Context, group checkly.Group) (*checkly.Group, error) {
+	var g checkly.GroupV2
+	convert(group, &g)
+	result, err := c.groupCollection().create(ctx, g)
+	return groupV1(result), err
+}
+
+func (c *Client) CreateGroupV2(ctx context.Context, group checkly.GroupV2) (*checkly.GroupV2, error) {
+	return c.groupCollection().create(ctx, group)
+}
+
+func (c *Client) GetGroup(ctx context.Context, ID int64) (*checkly.Group, error) {
+	result, err := c.groupCollection().get(ctx, ID)
+	return groupV1(result), err
+}
+
+func (c *Client) GetGroupV2(ctx context.Context, ID int64) (*checkly.GroupV2, error) {
+	return c.groupCollection().get(ctx, ID)
+}
+
+func (c *Client) ListGroups(ctx context.Context, opts checkly.ListOptions) *checkly.Pager[checkly.Group] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	groups := c.groups.values()
+	result := make([]checkly.Group, 0, len(groups))
+	for i := range groups {
+		result = append(result, *groupV1(&groups[i]))
+	}
+	return newSlicePager(result, opts)
+}
+
+func (c *Client) ListGroupsV2(ctx context.Context, opts checkly.ListOptions) *checkly.Pager[checkly.GroupV2] {
+	return c.groupCollection().list(opts)
+}
+
+func (c *Client) UpdateGroup(ctx context.Context, ID int64, group checkly.Group) (*checkly.Group, error) {
+	var g checkly.GroupV2
+	convert(group, &g)
+	result, err := c.groupCollection().update(ctx, ID, g)
+	return groupV1(result), err
+}
+
+func (c *Client) UpdateGroupV2(ctx context.Context, ID int64, group checkly.GroupV2) (*checkly.GroupV2, error) {
+	return c.groupCollection().update(ctx, ID, group)
+}
+
+// DeleteGroup deletes a group along with its trigger. Like the API, it
+// refuses to delete groups which still have checks.
+func (c *Client) DeleteGroup(ctx context.Context, ID int64) error {
+	return c.groupCollection().delete(ctx, ID)
+}
+
+func (c *Client) DeleteGroupV2(ctx context.Context, ID int64) error {
+	return c.groupCollection().delete(ctx, ID)
+}
+
+func groupV1(group *checkly.GroupV2) *checkly.Group {
+	if group == nil {
+		return nil
+	}
+	var result checkly.Group
+	convert(group, &result)
+	return &result
+}
+
+func (c *Client) GetCheckResult(ctx context.Context, checkID, checkResultID string) (*checkly.CheckResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	result, ok := c.checkResults.get(checkResultID)
+	if !ok || result.CheckID != checkID {
+		return nil, notFound(http.MethodGet, fmt.Sprintf("%s/%s/%s", CheckResults, checkID, checkResultID))
+	}
+	result = clone(result)
+	return &result, nil
+}
+
+// GetCheckResults returns the page of results of the given check selected by
+// filters.
+//
+// Deprecated: use ListCheckResults instead.
+func (c *Client) GetCheckResults(
+	ctx context.Context,
+	checkID string,
+	filters *checkly.CheckResultsFilter,
+) ([]checkly.CheckResult, error) {
+	if filters == nil {
+		filters = &checkly.CheckResultsFilter{}
+	}
+	results, err := c.ListCheckResults(ctx, checkID, *filters, checkly.ListOptions{}).Collect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	limit, page := int(filters.Limit), int(filters.Page)
+	if limit <= 0 {
+		limit = 10
+	}
+	if page <= 0 {
+		page = 1
+	}
+	start := min((page-1)*limit, len(results))
+	return results[start:min(start+limit, len(results))], nil
+}
+
+// ListCheckResults returns a Pager over the results of the given check
+// matching filters, most recent first.
+func (c *Client) ListCheckResults(
+	ctx context.Context,
+	checkID string,
+	filters checkly.CheckResultsFilter,
+	opts checkly.ListOptions,
+) *checkly.Pager[checkly.CheckResult] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	check, hasCheck := c.checks.get(checkID)
+	var result []checkly.CheckResult
+	for _, r := range c.checkResults.values() {
+		switch {
+		case r.CheckID != checkID:
+		case filters.Location != "" && r.RunLocation != filters.Location:
+		case filters.HasFailures && !r.HasFailures:
+		case filters.From > 0 && r.StartedAt.Unix() < filters.From:
+		case filters.To > 0 && r.StartedAt.Unix() > filters.To:
+		case filters.CheckType != "" && hasCheck && check.CheckType() != string(filters.CheckType):
+		default:
+			result = append(result, clone(r))
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].StartedAt.After(result[j].StartedAt)
+	})
+	return newSlicePager(result, opts)
+}
+
+func (c *Client) snippetCollection() collection[int64, checkly.Snippet] {
+	return collection[int64, checkly.Snippet]{
+		c:        c,
+		resource: Snippets,
+		items:    c.snippets,
+		id:       func(s *checkly.Snippet) *int64 { return &s.ID },
+		newID:    c.newID,
+	}
+}
+
+func (c *Client) CreateSnippet(ctx context.Context, snippet checkly.Snippet) (*checkly.Snippet, error) {
+	return c.snippetCollection().create(ctx, snippet)
+}
+
+func (c *Client) GetSnippet(ctx context.Context, ID int64) (*checkly.Snippet, error) {
+	return c.snippetCollection().get(ctx, ID)
+}
+
+func (c *Client) ListSnippets(ctx context.Context, opts checkly.ListOptions) *checkly.Pager[checkly.Snippet] {
+	return c.snippetCollection().list(opts)
+}
+
+func (c *Client) UpdateSnippet(ctx context.Context, ID int64, snippet checkly.Snippet) (*checkly.Snippet, error) {
+	return c.snippetCollection().update(ctx, ID, snippet)
+}
+
+func (c *Client) DeleteSnippet(ctx context.Context, ID int64) error {
+	return c.snippetCollection().delete(ctx, ID)
+}
+
+func (c *Client) variableCollection() collection[string, checkly.EnvironmentVariable] {
+	return collection[string, checkly.EnvironmentVariable]{
+		c:        c,
+		resource: EnvironmentVariables,
+		items:    c.variables,
+		id:       func(v *checkly.EnvironmentVariable) *string { return &v.Key },
+		validate: func(method, path string, v *checkly.EnvironmentVariable) error {
+			if v.Key == "" {
+				return invalid(method, path, "key", `"key" is not allowed to be empty`)
+			}
+			if _, ok := c.variables.get(v.Key); ok && method == http.MethodPost {
+				return conflict(method, path, "A variable with this key already exists")
+			}
+			return nil
+		},
+	}
+}
+
+// CreateEnvironmentVariable creates a variable. Like the API, it refuses to
+// create a variable with the key of an existing one.
+func (c *Client) CreateEnvironmentVariable(ctx context.Context, envVar checkly.EnvironmentVariable) (*checkly.EnvironmentVariable, error) {
+	return c.variableCollection().create(ctx, envVar)
+}
+
+func (c *Client) GetEnvironmentVariable(ctx context.Context, key string) (*checkly.EnvironmentVariable, error) {
+	return c.variableCollection().get(ctx, key)
+}
+
+func (c *Client) ListEnvironmentVariables(ctx context.Context, opts checkly.ListOptions) *checkly.Pager[checkly.EnvironmentVariable] {
+	return c.variableCollection().list(opts)
+}
+
+func (c *Client) UpdateEnvironmentVariable(ctx context.Context, key string, envVar checkly.EnvironmentVariable) (*checkly.EnvironmentVariable, error) {
+	return c.variableCollection().update(ctx, key, envVar)
+}
+
+func (c *Client) DeleteEnvironmentVariable(ctx context.Context, key string) error {
+	return c.variableCollection().delete(ctx, key)
+}
+
+func (c *Client) alertChannelCollection() collection[int64, checkly.AlertChannel] {
+	return collection[int64, checkly.AlertChannel]{
+		c:        c,
+		resource: AlertChannels,
+		items:    c.alertChannels,
+		id:       func(ac *checkly.AlertChannel) *int64 { return &ac.ID },
+		newID:    c.newID,
+		clone:    cloneAlertChannel,
+	}
+}
+
+func (c *Client) CreateAlertChannel(ctx context.Context, ac checkly.AlertChannel) (*checkly.AlertChannel, error) {
+	return c.alertChannelCollection().create(ctx, ac)
+}
+
+func (c *Client) GetAlertChannel(ctx context.Context, ID int64) (*checkly.AlertChannel, error) {
+	return c.alertChannelCollection().get(ctx, ID)
+}
+
+func (c *Client) ListAlertChannels(ctx context.Context, opts checkly.ListOptions) *checkly.Pager[checkly.AlertChannel] {
+	return c.alertChannelCollection().list(opts)
+}
+
+func (c *Client) UpdateAlertChannel(ctx context.Context, ID int64, ac checkly.AlertChannel) (*checkly.AlertChannel, error) {
+	return c.alertChannelCollection().update(ctx, ID, ac)
+}
+
+func (c *Client) DeleteAlertChannel(ctx context.Context, ID int64) error {
+	return c.alertChannelCollection().delete(ctx, ID)
+}
+
+func (c *Client) dashboardCollection() collection[string, checkly.Dashboard] {
+	return collection[string, checkly.Dashboard]{
+		c:        c,
+		resource: Dashboards,
+		items:    c.dashboards,
+		id:       func(d *checkly.Dashboard) *string { return &d.DashboardID },
+		newID:    c.newUUID,
+	}
+}
+
+func (c *Client) CreateDashboard(ctx context.Context, dashboard checkly.Dashboard) (*checkly.Dashboard, error) {
+	return c.dashboardCollection().create(ctx, dashboard)
+}
+
+func (c *Client) GetDashboard(ctx context.Context, ID string) (*checkly.Dashboard, error) {
+	return c.dashboardCollection().get(ctx, ID)
+}
+
+func (c *Client) ListDashboards(ctx context.Context, opts checkly.ListOptions) *checkly.Pager[checkly.Dashboard] {
+	return c.dashboardCollection().list(opts)
+}
+
+func (c *Client) UpdateDashboard(ctx context.Context, ID string, dashboard checkly.Dashboard) (*checkly.Dashboard, error) {
+	return c.dashboardCollection().update(ctx, ID, dashboard)
+}
+
+func (c *Client) DeleteDashboard(ctx context.Context, ID string) error {
+	return c.dashboardCollection().delete(ctx, ID)
+}
+
+func (c *Client) maintenanceWindowCollection() collection[int64, checkly.MaintenanceWindow] {
+	return collection[int64, checkly.MaintenanceWindow]{
+		c:        c,
+		resource: MaintenanceWindows,
+		items:    c.maintenanceWindows,
+		id:       func(mw *checkly.MaintenanceWindow) *int64 { return &mw.ID },
+		newID:    c.newID,
+	}
+}
+
+func (c *Client) CreateMaintenanceWindow(ctx context.Context, mw checkly.MaintenanceWindow) (*checkly.MaintenanceWindow, error) {
+	return c.maintenanceWindowCollection().create(ctx, mw)
+}
+
+func (c *Client) GetMaintenanceWindow(ctx context.Context, ID int64) (*checkly.MaintenanceWindow, error) {
+	return c.maintenanceWindowCollection().get(ctx, ID)
+}
+
+func (c *Client) ListMaintenanceWindows(ctx context.Context, opts checkly.ListOptions) *checkly.Pager[checkly.MaintenanceWindow] {
+	return c.maintenanceWindowCollection().list(opts)
+}
+
+func (c *Client) UpdateMaintenanceWindow(ctx context.Context, ID int64, mw checkly.MaintenanceWindow) (*checkly.MaintenanceWindow, error) {
+	return c.maintenanceWindowCollection().update(ctx, ID, mw)
+}
+
+func (c *Client) DeleteMaintenanceWindow(ctx context.Context, ID int64) error {
+	return c.maintenanceWindowCollection().delete(ctx, ID)
+}
+
+func (c *Client) privateLocationCollection() collection[string, checkly.PrivateLocation] {
+	return collection[string, checkly.PrivateLocation]{
+		c:        c,
+		resource: PrivateLocations,
+		items:    c.privateLocations,
+		id:       func(pl *checkly.PrivateLocation) *string { return &pl.ID },
+		newID:    c.newUUID,
+	}
+}
+
+func (c *Client) CreatePrivateLocation(ctx context.Context, pl checkly.PrivateLocation) (*checkly.PrivateLocation, error) {
+	return c.privateLocationCollection().create(ctx, pl)
+}
+
+func (c *Client) GetPrivateLocation(ctx context.Context, ID string) (*checkly.PrivateLocation, error) {
+	return c.privateLocationCollection().get(ctx, ID)
+}
+
+func (c *Client) ListPrivateLocations(ctx context.Context, opts checkly.ListOptions) *checkly.Pager[checkly.PrivateLocation] {
+	return c.privateLocationCollection().list(opts)
+}
+
+func (c *Client) UpdatePrivateLocation(ctx context.Context, ID string, pl checkly.PrivateLocation) (*checkly.PrivateLocation, error) {
+	return c.privateLocationCollection().update(ctx, ID, pl)
+}
+
+func (c *Client) DeletePrivateLocation(ctx context.Context, ID string) error {
+	return c.privateLocationCollection().delete(ctx, ID)
+}
+
+// newToken returns a new trigger token. c.mu must be held.
+func (c *Client) newToken() string {
+	return strings.ReplaceAll(c.newUUID(), "-", "")
+}
+
+// CreateTriggerCheck creates the trigger of a check, or returns the existing
+// one.
+func (c *Client) CreateTriggerCheck(ctx context.Context, checkID string) (*checkly.TriggerCheck, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.checks.get(checkID); !ok {
+		return nil, notFound(http.MethodPost, "triggers/checks/"+checkID)
+	}
+	trigger, ok := c.checkTriggers.get(checkID)
+	if !ok {
+		token := c.newToken()
+		trigger = checkly.TriggerCheck{
+			ID:      c.newID(),
+			CheckId: checkID,
+			Token:   token,
+			URL:     fmt.Sprintf("%s/checks/%s/trigger/%s", checkly.DefaultBaseURL, checkID, token),
+		}
+		c.checkTriggers.put(checkID, trigger)
+	}
+	return &trigger, nil
+}
+
+func (c *Client) GetTriggerCheck(ctx context.Context, checkID string) (*checkly.TriggerCheck, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	trigger, ok := c.checkTriggers.get(checkID)
+	if !ok {
+		return nil, notFound(http.MethodGet, "triggers/checks/"+checkID)
+	}
+	return &trigger, nil
+}
+
+func (c *Client) DeleteTriggerCheck(ctx context.Context, checkID string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.checkTriggers.delete(checkID) {
+		return notFound(http.MethodDelete, "triggers/checks/"+checkID)
+	}
+	return nil
+}
+
+// CreateTriggerGroup creates the trigger of a group, or returns the existing
+// one.
+func (c *Client) CreateTriggerGroup(ctx context.Context, groupID int64) (*checkly.TriggerGroup, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	path := fmt.Sprintf("triggers/check-groups/%d", groupID)
+	if _, ok := c.groups.get(groupID); !ok {
+		return nil, notFound(http.MethodPost, path)
+	}
+	trigger, ok := c.groupTriggers.get(groupID)
+	if !ok {
+		token := c.newToken()
+		trigger = checkly.TriggerGroup{
+			ID:      c.newID(),
+			GroupId: groupID,
+			Token:   token,
+			URL:     fmt.Sprintf("%s/check-groups/%d/trigger/%s", checkly.DefaultBaseURL, groupID, token),
+		}
+		c.groupTriggers.put(groupID, trigger)
+	}
+	return &trigger, nil
+}
+
+func (c *Client) GetTriggerGroup(ctx context.Context, groupID int64) (*checkly.TriggerGroup, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	trigger, ok := c.groupTriggers.get(groupID)
+	if !ok {
+		return nil, notFound(http.MethodGet, fmt.Sprintf("triggers/check-groups/%d", groupID))
+	}
+	return &trigger, nil
+}
+
+func (c *Client) DeleteTriggerGroup(ctx context.Context, groupID int64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.groupTriggers.delete(groupID) {
+		return notFound(http.MethodDelete, fmt.Sprintf("triggers/check-groups/%d", groupID))
+	}
+	return nil
+}
+
+func (c *Client) clientCertificateCollection() collection[string, checkly.ClientCertificate] {
+	return collection[string, checkly.ClientCertificate]{
+		c:        c,
+		resource: ClientCertificates,
+		items:    c.clientCertificates,
+		id:       func(cc *checkly.ClientCertificate) *string { return &cc.ID },
+		newID:    c.newUUID,
+	}
+}
+
+func (c *Client) CreateClientCertificate(ctx context.Context, cs checkly.ClientCertificate) (*checkly.ClientCertificate, error) {
+	return c.clientCertificateCollection().create(ctx, cs)
+}
+
+func (c *Client) GetClientCertificate(ctx context.Context, ID string) (*checkly.ClientCertificate, error) {
+	return c.clientCertificateCollection().get(ctx, ID)
+}
+
+func (c *Client) ListClientCertificates(ctx context.Context, opts checkly.ListOptions) *checkly.Pager[checkly.ClientCertificate] {
+	return c.clientCertificateCollection().list(opts)
+}
+
+func (c *Client) DeleteClientCertificate(ctx context.Context, ID string) error {
+	return c.clientCertificateCollection().delete(ctx, ID)
+}
+
+func (c *Client) statusPageCollection() collection[string, checkly.StatusPage] {
+	return collection[string, checkly.StatusPage]{
+		c:        c,
+		resource: StatusPages,
+		items:    c.statusPages,
+		id:       func(p *checkly.StatusPage) *string { return &p.ID },
+		newID:    c.newUUID,
+		validate: func(method, path string, p *checkly.StatusPage) error {
+			for _, card := range p.Cards {
+				for i, service := range card.Services {
+					stored, ok := c.statusPageServices.get(service.ID)
+					if !ok {
+						return invalid(method, path, "cards",
+							fmt.Sprintf("status page service %s does not exist", service.ID))
+					}
+					// Like the API, reply the services in full.
+					card.Services[i] = stored
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// CreateStatusPage creates a status page. The services of its cards must
+// exist.
+func (c *Client) CreateStatusPage(ctx context.Context, page checkly.StatusPage) (*checkly.StatusPage, error) {
+	return c.statusPageCollection().create(ctx, page)
+}
+
+func (c *Client) GetStatusPage(ctx context.Context, ID string) (*checkly.StatusPage, error) {
+	return c.statusPageCollection().get(ctx, ID)
+}
+
+func (c *Client) ListStatusPages(ctx context.Context, opts checkly.ListOptions) *checkly.Pager[checkly.StatusPage] {
+	return c.statusPageCollection().list(opts)
+}
+
+func (c *Client) UpdateStatusPage(ctx context.Context, ID string, page checkly.StatusPage) (*checkly.StatusPage, error) {
+	return c.statusPageCollection().update(ctx, ID, page)
+}
+
+func (c *Client) DeleteStatusPage(ctx context.Context, ID string) error {
+	return c.statusPageCollection().delete(ctx, ID)
+}
+
+func (c *Client) statusPageServiceCollection() collection[string, checkly.StatusPageService] {
+	return collection[string, checkly.StatusPageService]{
+		c:        c,
+		resource: StatusPageServices,
+		items:    c.statusPageServices,
+		id:       func(s *checkly.StatusPageService) *string { return &s.ID },
+		newID:    c.newUUID,
+		beforeDelete: func(ID string) error {
+			for _, page := range c.statusPages.values() {
+				for _, card := range page.Cards {
+					for _, service := range card.Services {
+						if service.ID == ID {
+							return conflict(http.MethodDelete, fmt.Sprintf("%s/%s", StatusPageServices, ID),
+								fmt.Sprintf("status page service %s is used by status page %s", ID, page.ID))
+						}
+					}
+				}
+			}
+			return nil
+		},
+	}
+}
+
+func (c *Client) CreateStatusPageService(ctx context.Context, service checkly.StatusPageService) (*checkly.StatusPageService, error) {
+	return c.statusPageServiceCollection().create(ctx, service)
+}
+
+func (c *Client) GetStatusPageService(ctx context.Context, ID string) (*checkly.StatusPageService, error) {
+	return c.statusPageServiceCollection().get(ctx, ID)
+}
+
+func (c *Client) ListStatusPageServices(ctx context.Context, opts checkly.ListOptions) *checkly.Pager[checkly.StatusPageService] {
+	return c.statusPageServiceCollection().list(opts)
+}
+
+func (c *Client) UpdateStatusPageService(ctx context.Context, ID string, service checkly.StatusPageService) (*checkly.StatusPageService, error) {
+	return c.statusPageServiceCollection().update(ctx, ID, service)
+}
+
+// DeleteStatusPageService deletes a service. Like the API, it refuses to
+// delete services still shown on a status page.
+func (c *Client) DeleteStatusPageService(ctx context.Context, ID string) error {
+	return c.statusPageServiceCollection().delete(ctx, ID)
+}
+
+// GetRuntime returns a seeded runtime, see Runtimes.
+func (c *Client) GetRuntime(ctx context.Context, ID string) (*checkly.Runtime, error) {
+	return collection[string, checkly.Runtime]{
+		c:        c,
+		resource: Runtimes,
+		items:    c.runtimes,
+		id:       func(r *checkly.Runtime) *string { return &r.Name },
+	}.get(ctx, ID)
+}
+
+// SetStaticIPs sets the addresses returned by GetStaticIPs.
+func (c *Client) SetStaticIPs(IPs []checkly.StaticIP) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.staticIPs = append([]checkly.StaticIP(nil), IPs...)
+}
+
+// GetStaticIPs returns the addresses set with SetStaticIPs.
+func (c *Client) GetStaticIPs(ctx context.Context) ([]checkly.StaticIP, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]checkly.StaticIP(nil), c.staticIPs...), nil
+}
+
+// UploadCodeBundle stores the size of data and its SHA-256 checksum, under a
+// key derived from the checksum.
+func (c *Client) UploadCodeBundle(
+	ctx context.Context,
+	data io.Reader,
+	size int64,
+	options checkly.UploadCodeBundleOptions,
+) (*checkly.CodeBundle, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	hash := sha256.New()
+	n, err := io.Copy(hash, io.LimitReader(data, size))
+	if err != nil {
+		return nil, err
+	}
+	checksum := hex.EncodeToString(hash.Sum(nil))
+	if options.ChecksumSha256 != "" {
+		checksum = options.ChecksumSha256
+	}
+	bundle := checkly.CodeBundle{
+		Key:            "code-bundles/" + hex.EncodeToString(hash.Sum(nil)),
+		ContentLength:  n,
+		ChecksumSha256: checksum,
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.codeBundles.put(bundle.Key, bundle)
+	return &bundle, nil
+}
+
+// PeekCodeBundle returns an uploaded code bundle, or
+// checkly.ErrCodeBundleNotFound.
+func (c *Client) PeekCodeBundle(ctx context.Context, key string) (*checkly.CodeBundle, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	bundle, ok := c.codeBundles.get(key)
+	if !ok {
+		return nil, checkly.ErrCodeBundleNotFound
+	}
+	return &bundle, nil
+}
@@ -0,0 +1,712 @@
+package checklytest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strconv"
+	"strings"
+
+	checkly "github.com/checkly/checkly-go-sdk"
+)
+
+// Server is a stand-in for the Checkly API, serving the /v1, /v2 and
+// /next/checkly-storage routes used by the SDK from an in-memory Client:
+//
+//	server := checklytest.NewServer()
+//	defer server.Close()
+//	client := checkly.NewClient(server.URL, "dummy-key", nil, nil)
+//
+// Payloads are validated roughly like the public API does, see
+// validatePayload, and errors are replied in the API's format.
+type Server struct {
+	*httptest.Server
+
+	// Fake holds the state of the server. Use it to seed resources, or to
+	// inspect the effect of requests without going through HTTP.
+	Fake *Client
+}
+
+// NewServer starts and returns a Server backed by an empty Client. The
+// caller should call Close when finished, to shut it down.
+func NewServer() *Server {
+	client := NewClient()
+	return &Server{
+		Server: httptest.NewServer(NewHandler(client)),
+		Fake:   client,
+	}
+}
+
+// NewHandler returns an http.Handler serving the routes of the Checkly API
+// used by the SDK from client. Use it to serve the API from a server other
+// than the one started by NewServer, e.g. one using TLS.
+func NewHandler(client *Client) http.Handler {
+	h := &handler{client: client}
+	h.registerChecks()
+	h.registerResources()
+	return h
+}
+
+type handler struct {
+	client *Client
+	routes []route
+}
+
+// route maps the requests for method on paths matching pattern, in which
+// "{}" matches any path segment, to handle. handle receives the segments
+// matched by "{}".
+type route struct {
+	method  string
+	pattern []string
+	handle  func(w http.ResponseWriter, r *http.Request, params []string)
+}
+
+func (h *handler) handle(
+	method string,
+	pattern string,
+	handle func(w http.ResponseWriter, r *http.Request, params []string),
+) {
+	h.routes = append(h.routes, route{
+		method:  method,
+		pattern: strings.Split(strings.Trim(pattern, "/"), "/"),
+		handle:  handle,
+	})
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+		writeError(w, r, apiError(r.Method, resourcePath(r), http.StatusUnauthorized, "Missing API key"))
+		return
+	}
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	methodNotAllowed := false
+	for _, route := range h.routes {
+		params, ok := matchRoute(route.pattern, segments)
+		if !ok {
+			continue
+		}
+		if route.method != r.Method {
+			methodNotAllowed = true
+			continue
+		}
+		route.handle(w, r, params)
+		return
+	}
+	if methodNotAllowed {
+		writeError(w, r, apiError(r.Method, resourcePath(r), http.StatusMethodNotAllowed, "Method Not Allowed"))
+		return
+	}
+	writeError(w, r, notFound(r.Method, resourcePath(r)))
+}
+
+func matchRoute(pattern, segments []string) ([]string, bool) {
+	if len(pattern) != len(segments) {
+		return nil, false
+	}
+	var params []string
+	for i, p := range pattern {
+		switch {
+		case p == "{}":
+			params = append(params, segments[i])
+		case p != segments[i]:
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// writeJSON replies to a request with v encoded in the API's format.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	data, err := json.Marshal(wireValue(v))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	w.Write(data)
+}
+
+// writeError replies to a request with err. Errors other than
+// *checkly.APIError are replied as bad requests.
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	var apiErr *checkly.APIError
+	if !errors.As(err, &apiErr) {
+		errors.As(apiError(r.Method, resourcePath(r), http.StatusBadRequest, err.Error()), &apiErr)
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(apiErr.StatusCode)
+	io.WriteString(w, apiErr.Body)
+}
+
+// wireValue returns what to encode to reply v. The API encodes the check type
+// of every check and the config of alert channels, which the SDK's types
+// don't.
+func wireValue(v any) any {
+	switch v := v.(type) {
+	case checkly.Monitor:
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(monitorJSON(v), &fields); err != nil {
+			panic(err)
+		}
+		fields["checkType"], _ = json.Marshal(v.CheckType())
+		return fields
+	case *checkly.AlertChannel:
+		var fields map[string]any
+		convert(v, &fields)
+		// The SDK decodes alert channels field by field and expects the
+		// optional ones to be either absent or set.
+		for k, value := range fields {
+			if value == nil {
+				delete(fields, k)
+			}
+		}
+		fields["config"] = v.GetConfig()
+		return fields
+	case []any:
+		result := make([]any, len(v))
+		for i, item := range v {
+			result[i] = wireValue(item)
+		}
+		return result
+	default:
+		return v
+	}
+}
+
+// decodeBody decodes the payload of a request into a T.
+func decodeBody[T any](r *http.Request) (T, error) {
+	var result T
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		return result, err
+	}
+	if ac, ok := any(&result).(*checkly.AlertChannel); ok {
+		decoded, err := decodeAlertChannel(data)
+		if err != nil {
+			return result, apiError(r.Method, resourcePath(r), http.StatusBadRequest, err.Error())
+		}
+		*ac = *decoded
+		return result, nil
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return result, apiError(r.Method, resourcePath(r), http.StatusBadRequest, "Invalid request payload JSON format")
+	}
+	return result, nil
+}
+
+// replyValue returns the value to pass to writeJSON to reply v, so that
+// wireValue recognizes checks whether T is a check type or checkly.Monitor.
+func replyValue[T any](v *T) any {
+	if m, ok := any(v).(checkly.Monitor); ok {
+		return m
+	}
+	if m, ok := any(*v).(checkly.Monitor); ok {
+		return m
+	}
+	return v
+}
+
+// resourcePath returns the path of the resource requested by r, relative to
+// the /v1 prefix added by apiError.
+func resourcePath(r *http.Request) string {
+	return strings.TrimPrefix(r.URL.Path, "/v1/")
+}
+
+func parseInt64(s string) (int64, error) {
+	return strconv.ParseInt(s, 10, 64)
+}
+
+func parseString(s string) (string, error) {
+	return s, nil
+}
+
+// createRoute returns a handler decoding the payload into a T and creating it
+// with create.
+func createRoute[T any](
+	create func(context.Context, T) (*T, error),
+) func(http.ResponseWriter, *http.Request, []string) {
+	return func(w http.ResponseWriter, r *http.Request, _ []string) {
+		payload, err := decodeBody[T](r)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		result, err := create(r.Context(), payload)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, replyValue(result))
+	}
+}
+
+// getRoute returns a handler replying the resource whose ID is the last
+// parameter of the route.
+func getRoute[K any, T any](
+	parse func(string) (K, error),
+	get func(context.Context, K) (*T, error),
+) func(http.ResponseWriter, *http.Request, []string) {
+	return func(w http.ResponseWriter, r *http.Request, params []string) {
+		ID, err := parse(params[len(params)-1])
+		if err != nil {
+			writeError(w, r, notFound(r.Method, resourcePath(r)))
+			return
+		}
+		result, err := get(r.Context(), ID)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, replyValue(result))
+	}
+}
+
+// updateRoute returns a handler decoding the payload into a T and updating
+// the resource whose ID is the last parameter of the route.
+func updateRoute[K any, T any](
+	parse func(string) (K, error),
+	update func(context.Context, K, T) (*T, error),
+) func(http.ResponseWriter, *http.Request, []string) {
+	return func(w http.ResponseWriter, r *http.Request, params []string) {
+		ID, err := parse(params[len(params)-1])
+		if err != nil {
+			writeError(w, r, notFound(r.Method, resourcePath(r)))
+			return
+		}
+		payload, err := decodeBody[T](r)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		result, err := update(r.Context(), ID, payload)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, replyValue(result))
+	}
+}
+
+// deleteRoute returns a handler deleting the resource whose ID is the last
+// parameter of the route.
+func deleteRoute[K any](
+	parse func(string) (K, error),
+	del func(context.Context, K) error,
+) func(http.ResponseWriter, *http.Request, []string) {
+	return func(w http.ResponseWriter, r *http.Request, params []string) {
+		ID, err := parse(params[len(params)-1])
+		if err != nil {
+			writeError(w, r, notFound(r.Method, resourcePath(r)))
+			return
+		}
+		if err := del(r.Context(), ID); err != nil {
+			writeError(w, r, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// pagination is the way a list endpoint splits its items in pages.
+type pagination int
+
+const (
+	// pagePagination uses the page and limit query parameters.
+	pagePagination pagination = iota
+	// cursorPagination uses the limit and nextId query parameters.
+	cursorPagination
+	// noPagination returns all items at once.
+	noPagination
+)
+
+// listRoute returns a handler replying the items returned by list, split in
+// pages as requested.
+func listRoute[T any](
+	paging pagination,
+	list func(context.Context, checkly.ListOptions) *checkly.Pager[T],
+) func(http.ResponseWriter, *http.Request, []string) {
+	return func(w http.ResponseWriter, r *http.Request, _ []string) {
+		items, err := list(r.Context(), checkly.ListOptions{}).Collect(r.Context())
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		writeList(w, r, paging, items)
+	}
+}
+
+func writeList[T any](w http.ResponseWriter, r *http.Request, paging pagination, items []T) {
+	values := make([]any, len(items))
+	for i := range items {
+		values[i] = replyValue(&items[i])
+	}
+	if paging == noPagination {
+		writeJSON(w, http.StatusOK, values)
+		return
+	}
+	query := r.URL.Query()
+	limit, err := strconv.Atoi(query.Get("limit"))
+	if err != nil || limit <= 0 || limit > 100 {
+		limit = 100
+	}
+	var start int
+	if paging == pagePagination {
+		page, err := strconv.Atoi(query.Get("page"))
+		if err != nil || page < 1 {
+			page = 1
+		}
+		start = min((page-1)*limit, len(values))
+	} else {
+		start, _ = strconv.Atoi(query.Get("nextId"))
+		start = min(max(start, 0), len(values))
+	}
+	end := min(start+limit, len(values))
+	if paging == pagePagination {
+		writeJSON(w, http.StatusOK, values[start:end])
+		return
+	}
+	var next *string
+	if end < len(values) {
+		cursor := strconv.Itoa(end)
+		next = &cursor
+	}
+	writeJSON(w, http.StatusOK, struct {
+		Entries any     `json:"entries"`
+		NextID  *string `json:"nextId"`
+	}{wireValue(values[start:end]), next})
+}
+
+// checkRoutes holds the handlers for the type specific check endpoints.
+type checkRoutes struct {
+	create func(http.ResponseWriter, *http.Request, []string)
+	update func(http.ResponseWriter, *http.Request, []string)
+}
+
+func monitorRoutes[T any](
+	checkType string,
+	create func(context.Context, T) (*T, error),
+	update func(context.Context, string, T) (*T, error),
+) checkRoutes {
+	return checkRoutes{
+		create: validated(checkType, createRoute(create)),
+		update: validated(checkType, updateRoute(parseString, update)),
+	}
+}
+
+// validated returns handle preceded by the validation of the payload of a
+// check of checkType.
+func validated(
+	checkType string,
+	handle func(http.ResponseWriter, *http.Request, []string),
+) func(http.ResponseWriter, *http.Request, []string) {
+	return func(w http.ResponseWriter, r *http.Request, params []string) {
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		if err := validatePayload(r.Method, resourcePath(r), checkType, data); err != nil {
+			writeError(w, r, err)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(data))
+		handle(w, r, params)
+	}
+}
+
+func (h *handler) registerChecks() {
+	c := h.client
+	routes := map[string]checkRoutes{
+		"api":        monitorRoutes(checkly.TypeAPI, c.CreateCheck, c.UpdateCheck),
+		"browser":    monitorRoutes(checkly.TypeBrowser, c.CreateCheck, c.UpdateCheck),
+		"heartbeat":  monitorRoutes(checkly.TypeHeartbeat, c.CreateHeartbeatMonitor, c.UpdateHeartbeatMonitor),
+		"multistep":  monitorRoutes(checkly.TypeMultiStep, c.CreateMultiStepCheck, c.UpdateMultiStepCheck),
+		"playwright": monitorRoutes(checkly.TypePlaywright, c.CreatePlaywrightCheck, c.UpdatePlaywrightCheck),
+		"tcp":        monitorRoutes(checkly.TypeTCP, c.CreateTCPMonitor, c.UpdateTCPMonitor),
+		"url":        monitorRoutes(checkly.TypeURL, c.CreateURLMonitor, c.UpdateURLMonitor),
+		"dns":        monitorRoutes(checkly.TypeDNS, c.CreateDNSMonitor, c.UpdateDNSMonitor),
+		"icmp":       monitorRoutes(checkly.TypeICMP, c.CreateICMPMonitor, c.UpdateICMPMonitor),
+		"grpc":       monitorRoutes(checkly.TypeGRPC, c.CreateGRPCMonitor, c.UpdateGRPCMonitor),
+		"traceroute": monitorRoutes(checkly.TypeTraceroute, c.CreateTracerouteMonitor, c.UpdateTracerouteMonitor),
+		"ssl":        monitorRoutes(checkly.TypeSSL, c.CreateSSLMonitor, c.UpdateSSLMonitor),
+	}
+	h.handle(http.MethodGet, "/v1/checks", listRoute(pagePagination, func(ctx context.Context, opts checkly.ListOptions) *checkly.Pager[checkly.Monitor] {
+		return c.ListChecks(ctx, checkly.ListChecksOptions{ListOptions: opts})
+	}))
+	h.handle(http.MethodPost, "/v1/checks", validated("", createRoute(c.Create)))
+	h.handle(http.MethodGet, "/v1/checks/{}", getRoute(parseString, func(ctx context.Context, ID string) (*checkly.Monitor, error) {
+		monitor, err := c.GetAnyCheck(ctx, ID)
+		return &monitor, err
+	}))
+	h.handle(http.MethodPut, "/v1/checks/{}", validated("", updateRoute(parseString, c.UpdateCheck)))
+	h.handle(http.MethodDelete, "/v1/checks/{}", deleteRoute(parseString, c.DeleteCheck))
+	h.handle(http.MethodPost, "/v1/checks/{}", func(w http.ResponseWriter, r *http.Request, params []string) {
+		route, ok := routes[params[0]]
+		if !ok {
+			writeError(w, r, notFound(r.Method, resourcePath(r)))
+			return
+		}
+		route.create(w, r, params)
+	})
+	h.handle(http.MethodPut, "/v1/checks/{}/{}", func(w http.ResponseWriter, r *http.Request, params []string) {
+		route, ok := routes[params[0]]
+		if !ok {
+			writeError(w, r, notFound(r.Method, resourcePath(r)))
+			return
+		}
+		route.update(w, r, params)
+	})
+
+	h.handle(http.MethodGet, "/v1/check-results/{}", func(w http.ResponseWriter, r *http.Request, params []string) {
+		query := r.URL.Query()
+		filters := checkly.CheckResultsFilter{
+			Location:    query.Get("location"),
+			CheckType:   checkly.CheckType(query.Get("checkType")),
+			HasFailures: query.Get("hasFailures") == "1" || query.Get("hasFailures") == "true",
+		}
+		filters.From, _ = parseInt64(query.Get("from"))
+		filters.To, _ = parseInt64(query.Get("to"))
+		results, err := c.ListCheckResults(r.Context(), params[0], filters, checkly.ListOptions{}).Collect(r.Context())
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		writeList(w, r, pagePagination, results)
+	})
+	h.handle(http.MethodGet, "/v1/check-results/{}/{}", func(w http.ResponseWriter, r *http.Request, params []string) {
+		result, err := c.GetCheckResult(r.Context(), params[0], params[1])
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, result)
+	})
+
+	h.handle(http.MethodPost, "/v1/triggers/checks/{}", func(w http.ResponseWriter, r *http.Request, params []string) {
+		trigger, err := c.CreateTriggerCheck(r.Context(), params[0])
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, trigger)
+	})
+	h.handle(http.MethodGet, "/v1/triggers/checks/{}", getRoute(parseString, c.GetTriggerCheck))
+	h.handle(http.MethodDelete, "/v1/triggers/checks/{}", deleteRoute(parseString, c.DeleteTriggerCheck))
+}
+
+func (h *handler) registerResources() {
+	c := h.client
+	for _, version := range []string{"v1", "v2"} {
+		prefix := "/" + version + "/check-groups"
+		h.handle(http.MethodPost, prefix, createRoute(c.CreateGroupV2))
+		h.handle(http.MethodGet, prefix, listRoute(pagePagination, c.ListGroupsV2))
+		h.handle(http.MethodGet, prefix+"/{}", getRoute(parseInt64, c.GetGroupV2))
+		h.handle(http.MethodPut, prefix+"/{}", updateRoute(parseInt64, c.UpdateGroupV2))
+		h.handle(http.MethodDelete, prefix+"/{}", deleteRoute(parseInt64, c.DeleteGroupV2))
+	}
+	h.handle(http.MethodPost, "/v1/triggers/check-groups/{}", func(w http.ResponseWriter, r *http.Request, params []string) {
+		groupID, err := parseInt64(params[0])
+		if err != nil {
+			writeError(w, r, notFound(r.Method, resourcePath(r)))
+			return
+		}
+		trigger, err := c.CreateTriggerGroup(r.Context(), groupID)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, trigger)
+	})
+	h.handle(http.MethodGet, "/v1/triggers/check-groups/{}", getRoute(parseInt64, c.GetTriggerGroup))
+	h.handle(http.MethodDelete, "/v1/triggers/check-groups/{}", deleteRoute(parseInt64, c.DeleteTriggerGroup))
+
+	h.handle(http.MethodPost, "/v1/snippets", createRoute(c.CreateSnippet))
+	h.handle(http.MethodGet, "/v1/snippets", listRoute(pagePagination, c.ListSnippets))
+	h.handle(http.MethodGet, "/v1/snippets/{}", getRoute(parseInt64, c.GetSnippet))
+	h.handle(http.MethodPut, "/v1/snippets/{}", updateRoute(parseInt64, c.UpdateSnippet))
+	h.handle(http.MethodDelete, "/v1/snippets/{}", deleteRoute(parseInt64, c.DeleteSnippet))
+
+	h.handle(http.MethodPost, "/v1/variables", createRoute(c.CreateEnvironmentVariable))
+	h.handle(http.MethodGet, "/v1/variables", listRoute(pagePagination, c.ListEnvironmentVariables))
+	h.handle(http.MethodGet, "/v1/variables/{}", getRoute(parseString, c.GetEnvironmentVariable))
+	h.handle(http.MethodPut, "/v1/variables/{}", updateRoute(parseString, c.UpdateEnvironmentVariable))
+	h.handle(http.MethodDelete, "/v1/variables/{}", deleteRoute(parseString, c.DeleteEnvironmentVariable))
+
+	h.handle(http.MethodPost, "/v1/alert-channels", createRoute(c.CreateAlertChannel))
+	h.handle(http.MethodGet, "/v1/alert-channels", listRoute(pagePagination, c.ListAlertChannels))
+	h.handle(http.MethodGet, "/v1/alert-channels/{}", getRoute(parseInt64, c.GetAlertChannel))
+	h.handle(http.MethodPut, "/v1/alert-channels/{}", updateRoute(parseInt64, c.UpdateAlertChannel))
+	h.handle(http.MethodDelete, "/v1/alert-channels/{}", deleteRoute(parseInt64, c.DeleteAlertChannel))
+
+	h.handle(http.MethodPost, "/v1/dashboards", createRoute(c.CreateDashboard))
+	h.handle(http.MethodGet, "/v1/dashboards", listRoute(pagePagination, c.ListDashboards))
+	h.handle(http.MethodGet, "/v1/dashboards/{}", getRoute(parseString, c.GetDashboard))
+	h.handle(http.MethodPut, "/v1/dashboards/{}", updateRoute(parseString, c.UpdateDashboard))
+	h.handle(http.MethodDelete, "/v1/dashboards/{}", deleteRoute(parseString, c.DeleteDashboard))
+
+	h.handle(http.MethodPost, "/v1/maintenance-windows", createRoute(c.CreateMaintenanceWindow))
+	h.handle(http.MethodGet, "/v1/maintenance-windows", listRoute(pagePagination, c.ListMaintenanceWindows))
+	h.handle(http.MethodGet, "/v1/maintenance-windows/{}", getRoute(parseInt64, c.GetMaintenanceWindow))
+	h.handle(http.MethodPut, "/v1/maintenance-windows/{}", updateRoute(parseInt64, c.UpdateMaintenanceWindow))
+	h.handle(http.MethodDelete, "/v1/maintenance-windows/{}", deleteRoute(parseInt64, c.DeleteMaintenanceWindow))
+
+	h.handle(http.MethodPost, "/v1/private-locations", createRoute(c.CreatePrivateLocation))
+	h.handle(http.MethodGet, "/v1/private-locations", listRoute(noPagination, c.ListPrivateLocations))
+	h.handle(http.MethodGet, "/v1/private-locations/{}", getRoute(parseString, c.GetPrivateLocation))
+	h.handle(http.MethodPut, "/v1/private-locations/{}", updateRoute(parseString, c.UpdatePrivateLocation))
+	h.handle(http.MethodDelete, "/v1/private-locations/{}", deleteRoute(parseString, c.DeletePrivateLocation))
+
+	h.handle(http.MethodPost, "/v1/client-certificates", createRoute(c.CreateClientCertificate))
+	h.handle(http.MethodGet, "/v1/client-certificates", listRoute(cursorPagination, c.ListClientCertificates))
+	h.handle(http.MethodGet, "/v1/client-certificates/{}", getRoute(parseString, c.GetClientCertificate))
+	h.handle(http.MethodDelete, "/v1/client-certificates/{}", deleteRoute(parseString, c.DeleteClientCertificate))
+
+	// The services routes are registered first, as "services" would
+	// otherwise match the ID of a status page.
+	h.handle(http.MethodPost, "/v1/status-pages/services", createRoute(c.CreateStatusPageService))
+	h.handle(http.MethodGet, "/v1/status-pages/services", listRoute(cursorPagination, c.ListStatusPageServices))
+	h.handle(http.MethodGet, "/v1/status-pages/services/{}", getRoute(parseString, c.GetStatusPageService))
+	h.handle(http.MethodPut, "/v1/status-pages/services/{}", updateRoute(parseString, c.UpdateStatusPageService))
+	h.handle(http.MethodDelete, "/v1/status-pages/services/{}", deleteRoute(parseString, c.DeleteStatusPageService))
+	h.handle(http.MethodPost, "/v1/status-pages", createRoute(c.CreateStatusPage))
+	h.handle(http.MethodGet, "/v1/status-pages", listRoute(cursorPagination, c.ListStatusPages))
+	h.handle(http.MethodGet, "/v1/status-pages/{}", getRoute(parseString, c.GetStatusPage))
+	h.handle(http.MethodPut, "/v1/status-pages/{}", updateRoute(parseString, c.UpdateStatusPage))
+	h.handle(http.MethodDelete, "/v1/status-pages/{}", deleteRoute(parseString, c.DeleteStatusPage))
+
+	h.handle(http.MethodGet, "/v1/runtimes/{}", getRoute(parseString, c.GetRuntime))
+	h.handle(http.MethodGet, "/v1/static-ips-by-region", h.staticIPs(netip.Addr.Is4))
+	h.handle(http.MethodGet, "/v1/static-ipv6s-by-region", h.staticIPs(netip.Addr.Is6))
+
+	h.handle(http.MethodPost, "/next/checkly-storage/upload-code-bundle", func(w http.ResponseWriter, r *http.Request, _ []string) {
+		bundle, err := c.UploadCodeBundle(r.Context(), r.Body, r.ContentLength, checkly.UploadCodeBundleOptions{
+			ChecksumSha256: r.Header.Get("x-bundle-checksum-sha256"),
+		})
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, bundle)
+	})
+	h.handle(http.MethodPost, "/next/checkly-storage/peek-code-bundle", func(w http.ResponseWriter, r *http.Request, _ []string) {
+		payload, err := decodeBody[struct {
+			Key string `json:"key"`
+		}](r)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		bundle, err := c.PeekCodeBundle(r.Context(), payload.Key)
+		if errors.Is(err, checkly.ErrCodeBundleNotFound) {
+			err = notFound(r.Method, resourcePath(r))
+		}
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, bundle)
+	})
+}
+
+// staticIPs returns a handler replying the static IPs of the client whose
+// address family is matched by family, grouped by region.
+func (h *handler) staticIPs(family func(netip.Addr) bool) func(http.ResponseWriter, *http.Request, []string) {
+	return func(w http.ResponseWriter, r *http.Request, _ []string) {
+		IPs, err := h.client.GetStaticIPs(r.Context())
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		result := map[string][]string{}
+		for _, IP := range IPs {
+			if family(IP.Address.Addr()) {
+				result[IP.Region] = append(result[IP.Region], IP.Address.String())
+			}
+		}
+		writeJSON(w, http.StatusOK, result)
+	}
+}
+
+// allowedFrequencies are the check frequencies, in minutes, accepted by the
+// API.
+var allowedFrequencies = []int{0, 1, 2, 5, 10, 15, 30, 60, 120, 180, 360, 720, 1440}
+
+// validatePayload checks the payload of a check of checkType, empty when the
+// type is taken from the payload, roughly like the public API does.
+func validatePayload(method, path, checkType string, data []byte) error {
+	var payload struct {
+		Name             *string          `json:"name"`
+		Type             string           `json:"checkType"`
+		Frequency        *int             `json:"frequency"`
+		PrivateLocations *json.RawMessage `json:"privateLocations"`
+		Request          struct {
+			GRPCConfig map[string]any `json:"grpcConfig"`
+		} `json:"request"`
+	}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return apiError(method, path, http.StatusBadRequest, "Invalid request payload JSON format")
+	}
+	if checkType == "" {
+		checkType = payload.Type
+	}
+	if payload.Name == nil || *payload.Name == "" {
+		return invalid(method, path, "name", `"name" is not allowed to be empty`)
+	}
+	if payload.Frequency != nil && checkType != checkly.TypeHeartbeat {
+		allowed := false
+		for _, f := range allowedFrequencies {
+			allowed = allowed || *payload.Frequency == f
+		}
+		if !allowed {
+			return invalid(method, path, "frequency", fmt.Sprintf(
+				`child "frequency" fails because ["frequency" must be one of %v]`,
+				allowedFrequencies,
+			))
+		}
+	}
+	switch checkType {
+	case checkly.TypeTraceroute:
+		if payload.PrivateLocations != nil {
+			return invalid(method, path, "privateLocations", `"privateLocations" is not allowed`)
+		}
+	case checkly.TypeGRPC:
+		return validateGRPCConfig(method, path, payload.Request.GRPCConfig)
+	}
+	return nil
+}
+
+// validateGRPCConfig checks that the fields of a GRPC monitor's config match
+// its mode: BEHAVIOR, the default, requires a method and forbids service,
+// while HEALTH forbids the fields describing the method to call.
+func validateGRPCConfig(method, path string, config map[string]any) error {
+	mode, _ := config["mode"].(string)
+	if mode == "" {
+		mode = "BEHAVIOR"
+	}
+	var forbidden []string
+	switch mode {
+	case "HEALTH":
+		forbidden = []string{"serviceDefinition", "method", "protoContent", "message"}
+	case "BEHAVIOR":
+		if m, _ := config["method"].(string); m == "" {
+			return invalid(method, path, "request.grpcConfig.method", `"request.grpcConfig.method" is required`)
+		}
+		forbidden = []string{"service"}
+	default:
+		return invalid(method, path, "request.grpcConfig.mode", `"request.grpcConfig.mode" must be one of [BEHAVIOR, HEALTH]`)
+	}
+	for _, field := range forbidden {
+		if _, ok := config[field]; ok {
+			key := "request.grpcConfig." + field
+			return invalid(method, path, key, fmt.Sprintf("%q is not allowed", key))
+		}
+	}
+	return nil
+}
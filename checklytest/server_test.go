@@ -0,0 +1,181 @@
+package checklytest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	checkly "github.com/checkly/checkly-go-sdk"
+	"github.com/checkly/checkly-go-sdk/checklytest"
+)
+
+func newServerClient(t *testing.T) (*checklytest.Server, checkly.Client) {
+	server := checklytest.NewServer()
+	t.Cleanup(server.Close)
+	return server, checkly.NewClient(server.URL, "dummy-key", server.Client(), nil)
+}
+
+func TestServerChecks(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	server, client := newServerClient(t)
+	for _, name := range []string{"first", "second", "third"} {
+		if _, err := client.CreateTCPMonitor(ctx, checkly.TCPMonitor{
+			Name:      name,
+			Frequency: 10,
+			Request:   checkly.TCPRequest{Hostname: "example.com", Port: 443},
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	checks, err := client.ListChecks(ctx, checkly.ListChecksOptions{
+		ListOptions: checkly.ListOptions{PageSize: 2},
+	}).Collect(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(checks) != 3 {
+		t.Fatalf("want 3 checks, got %d", len(checks))
+	}
+	monitor, err := client.GetAnyCheck(ctx, checks[2].GetID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m, ok := monitor.(*checkly.TCPMonitor); !ok || m.Request.Hostname != "example.com" {
+		t.Errorf("want the third *checkly.TCPMonitor, got %#v", monitor)
+	}
+	// The server state is shared with its fake client.
+	if _, err := server.Fake.GetTCPMonitor(ctx, monitor.GetID()); err != nil {
+		t.Error(err)
+	}
+	if err := client.DeleteTCPMonitor(ctx, monitor.GetID()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetTCPMonitor(ctx, monitor.GetID()); !checkly.IsNotFound(err) {
+		t.Errorf("want a not found error after deletion, got %v", err)
+	}
+}
+
+func TestServerValidation(t *testing.T) {
+	t.Parallel()
+	server, _ := newServerClient(t)
+	tests := []struct {
+		name, path, payload, key string
+	}{
+		{
+			"traceroute private locations",
+			"/v1/checks/traceroute",
+			`{"name":"traceroute","frequency":10,"privateLocations":[]}`,
+			"privateLocations",
+		},
+		{
+			"frequency",
+			"/v1/checks/tcp",
+			`{"name":"tcp","frequency":7}`,
+			"frequency",
+		},
+		{
+			"GRPC health mode with method",
+			"/v1/checks/grpc",
+			`{"name":"grpc","request":{"grpcConfig":{"mode":"HEALTH","method":"Say"}}}`,
+			"request.grpcConfig.method",
+		},
+		{
+			"GRPC behavior mode with service",
+			"/v1/checks/grpc",
+			`{"name":"grpc","request":{"grpcConfig":{"method":"Say","service":"greeter"}}}`,
+			"request.grpcConfig.service",
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			req, err := http.NewRequest(http.MethodPost, server.URL+tc.path, strings.NewReader(tc.payload))
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("Authorization", "Bearer dummy-key")
+			resp, err := server.Client().Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusBadRequest {
+				t.Errorf("want status %d, got %d", http.StatusBadRequest, resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestServerValidationError(t *testing.T) {
+	t.Parallel()
+	_, client := newServerClient(t)
+	_, err := client.CreateGRPCMonitor(context.Background(), checkly.GRPCMonitor{
+		Name: "grpc",
+		Request: checkly.GRPCRequest{
+			GRPCConfig: checkly.GRPCConfig{Mode: "HEALTH", Method: "Say"},
+		},
+	})
+	var apiErr *checkly.APIError
+	if !errors.As(err, &apiErr) || !checkly.IsValidationError(err) {
+		t.Fatalf("want a validation error, got %v", err)
+	}
+	if got := apiErr.Validation.Keys; len(got) != 1 || got[0] != "request.grpcConfig.method" {
+		t.Errorf("want the GRPC method to be reported, got %v", got)
+	}
+}
+
+func TestServerCursorPagination(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	_, client := newServerClient(t)
+	for _, name := range []string{"api", "web", "db"} {
+		if _, err := client.CreateStatusPageService(ctx, checkly.StatusPageService{Name: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	services, err := client.ListStatusPageServices(ctx, checkly.ListOptions{PageSize: 1}).Collect(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(services) != 3 || services[2].Name != "db" {
+		t.Errorf("want the 3 services in creation order, got %v", services)
+	}
+}
+
+func TestServerRequiresAPIKey(t *testing.T) {
+	t.Parallel()
+	server, _ := newServerClient(t)
+	resp, err := server.Client().Get(server.URL + "/v1/checks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("want status %d, got %d", http.StatusUnauthorized, resp.StatusCode)
+	}
+}
+
+func TestServerCodeBundles(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	_, client := newServerClient(t)
+	data := "export default {}"
+	bundle, err := client.UploadCodeBundle(ctx, strings.NewReader(data), int64(len(data)), checkly.UploadCodeBundleOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	peeked, err := client.PeekCodeBundle(ctx, bundle.Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if peeked.ContentLength != int64(len(data)) {
+		t.Errorf("want content length %d, got %d", len(data), peeked.ContentLength)
+	}
+	if _, err := client.PeekCodeBundle(ctx, "missing"); !errors.Is(err, checkly.ErrCodeBundleNotFound) {
+		t.Errorf("want ErrCodeBundleNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,413 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	checkly "github.com/checkly/checkly-go-sdk"
+)
+
+// Apply makes the changes of the plan in order. References to resources
+// created by the plan are resolved to their new IDs as they are created. Apply
+// stops at the first error, leaving the changes made before it in place; a new
+// plan picks up from there.
+func (p *Plan) Apply(ctx context.Context, client checkly.Client) error {
+	a := applier{client: client, ids: map[Kind]map[string]string{}}
+	for kind, ids := range p.ids {
+		a.ids[kind] = map[string]string{}
+		for key, ID := range ids {
+			a.ids[kind][key] = ID
+		}
+	}
+	for _, c := range p.Changes {
+		var err error
+		switch c.Action {
+		case Create, Update:
+			var ID string
+			ID, err = a.save(ctx, c)
+			if err == nil {
+				a.ids[c.Kind][c.Key] = ID
+			}
+		case Delete:
+			err = a.delete(ctx, c)
+		}
+		if err != nil {
+			return fmt.Errorf("%s %s %q: %w", c.Action, c.Kind, c.Key, err)
+		}
+	}
+	return nil
+}
+
+type applier struct {
+	client checkly.Client
+	ids    map[Kind]map[string]string
+}
+
+// id returns the ID of the resource of kind with key.
+func (a *applier) id(kind Kind, key string) (string, error) {
+	ID, ok := a.ids[kind][key]
+	if !ok {
+		return "", fmt.Errorf("unknown %s %q", kind, key)
+	}
+	return ID, nil
+}
+
+func (a *applier) int64ID(kind Kind, key string) (int64, error) {
+	ID, err := a.id(kind, key)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(ID, 10, 64)
+}
+
+func (a *applier) subscriptions(keys []string) ([]checkly.AlertChannelSubscription, error) {
+	var subs []checkly.AlertChannelSubscription
+	for _, key := range keys {
+		ID, err := a.int64ID(KindAlertChannel, key)
+		if err != nil {
+			return nil, err
+		}
+		subs = append(subs, checkly.AlertChannelSubscription{ChannelID: ID, Activated: true})
+	}
+	return subs, nil
+}
+
+// save creates or updates the resource of c and returns its ID. Updates keep
+// the values in the account of the fields which are null in the desired
+// State, see NewPlan.
+func (a *applier) save(ctx context.Context, c Change) (string, error) {
+	if a.ids[c.Kind] == nil {
+		a.ids[c.Kind] = map[string]string{}
+	}
+	create := c.Action == Create
+	value := c.desired.value
+	var numericID int64
+	if !create {
+		var err error
+		if value, err = mergeLive(c.desired, c.live); err != nil {
+			return "", err
+		}
+		if numericKind(c.Kind) {
+			if numericID, err = parseID(c.ID); err != nil {
+				return "", err
+			}
+		}
+	}
+	switch v := value.(type) {
+	case Snippet:
+		var result *checkly.Snippet
+		var err error
+		if create {
+			result, err = a.client.CreateSnippet(ctx, v.Snippet)
+		} else {
+			result, err = a.client.UpdateSnippet(ctx, numericID, v.Snippet)
+		}
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatInt(result.ID, 10), nil
+
+	case checkly.EnvironmentVariable:
+		var err error
+		if create {
+			_, err = a.client.CreateEnvironmentVariable(ctx, v)
+		} else {
+			_, err = a.client.UpdateEnvironmentVariable(ctx, v.Key, v)
+		}
+		return v.Key, err
+
+	case AlertChannel:
+		var result *checkly.AlertChannel
+		var err error
+		if create {
+			result, err = a.client.CreateAlertChannel(ctx, v.AlertChannel)
+		} else {
+			result, err = a.client.UpdateAlertChannel(ctx, numericID, v.AlertChannel)
+		}
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatInt(result.ID, 10), nil
+
+	case PrivateLocation:
+		var result *checkly.PrivateLocation
+		var err error
+		if create {
+			result, err = a.client.CreatePrivateLocation(ctx, v.PrivateLocation)
+		} else {
+			result, err = a.client.UpdatePrivateLocation(ctx, c.ID, v.PrivateLocation)
+		}
+		if err != nil {
+			return "", err
+		}
+		return result.ID, nil
+
+	case StatusPageService:
+		var result *checkly.StatusPageService
+		var err error
+		if create {
+			result, err = a.client.CreateStatusPageService(ctx, v.Service)
+		} else {
+			result, err = a.client.UpdateStatusPageService(ctx, c.ID, v.Service)
+		}
+		if err != nil {
+			return "", err
+		}
+		return result.ID, nil
+
+	case StatusPage:
+		page := v.StatusPage
+		page.Cards = nil
+		for _, card := range v.Cards {
+			services := []checkly.StatusPageService{}
+			for _, key := range card.Services {
+				ID, err := a.id(KindStatusPageService, key)
+				if err != nil {
+					return "", err
+				}
+				services = append(services, checkly.StatusPageService{ID: ID})
+			}
+			page.Cards = append(page.Cards, checkly.StatusPageCard{Name: card.Name, Services: services})
+		}
+		var result *checkly.StatusPage
+		var err error
+		if create {
+			result, err = a.client.CreateStatusPage(ctx, page)
+		} else {
+			result, err = a.client.UpdateStatusPage(ctx, c.ID, page)
+		}
+		if err != nil {
+			return "", err
+		}
+		return result.ID, nil
+
+	case Group:
+		group := v.Group
+		group.Tags = append(withoutKeyTag(group.Tags), KeyTag(v.Key))
+		subs, err := a.subscriptions(v.AlertChannels)
+		if err != nil {
+			return "", err
+		}
+		group.AlertChannelSubscriptions = subs
+		group.SetupSnippetID, err = a.snippetID(v.SetupSnippet)
+		if err != nil {
+			return "", err
+		}
+		group.TearDownSnippetID, err = a.snippetID(v.TearDownSnippet)
+		if err != nil {
+			return "", err
+		}
+		var result *checkly.GroupV2
+		if create {
+			result, err = a.client.CreateGroupV2(ctx, group)
+		} else {
+			result, err = a.client.UpdateGroupV2(ctx, numericID, group)
+		}
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatInt(result.ID, 10), nil
+
+	case Check:
+		monitor, err := a.monitor(v, c.ID)
+		if err != nil {
+			return "", err
+		}
+		if create {
+			monitor, err = checkly.CreateMonitor(ctx, a.client, monitor)
+		} else {
+			monitor, err = checkly.UpdateMonitor(ctx, a.client, monitor)
+		}
+		if err != nil {
+			return "", err
+		}
+		return monitor.GetID(), nil
+
+	case MaintenanceWindow:
+		window := v.MaintenanceWindow
+		window.Tags = append(withoutKeyTag(window.Tags), KeyTag(v.Key))
+		var result *checkly.MaintenanceWindow
+		var err error
+		if create {
+			result, err = a.client.CreateMaintenanceWindow(ctx, window)
+		} else {
+			result, err = a.client.UpdateMaintenanceWindow(ctx, numericID, window)
+		}
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatInt(result.ID, 10), nil
+
+	case Dashboard:
+		var result *checkly.Dashboard
+		var err error
+		if create {
+			result, err = a.client.CreateDashboard(ctx, v.Dashboard)
+		} else {
+			result, err = a.client.UpdateDashboard(ctx, c.ID, v.Dashboard)
+		}
+		if err != nil {
+			return "", err
+		}
+		return result.DashboardID, nil
+	}
+	return "", fmt.Errorf("unsupported resource %T", c.desired.value)
+}
+
+func (a *applier) snippetID(key string) (*int64, error) {
+	if key == "" {
+		return nil, nil
+	}
+	ID, err := a.int64ID(KindSnippet, key)
+	if err != nil {
+		return nil, err
+	}
+	return &ID, nil
+}
+
+// monitor returns the monitor of c with its references resolved, the key tag
+// added and the given ID. Monitor has no accessors for some of the
+// references, so they are set on its JSON document.
+func (a *applier) monitor(c Check, ID string) (checkly.Monitor, error) {
+	doc, err := toDocument(c.Monitor)
+	if err != nil {
+		return nil, err
+	}
+	doc["checkType"] = c.Monitor.CheckType()
+	doc["id"] = ID
+	tags := []any{}
+	for _, tag := range withoutKeyTag(c.Monitor.GetTags()) {
+		tags = append(tags, tag)
+	}
+	doc["tags"] = append(tags, KeyTag(c.Key))
+	delete(doc, "groupId")
+	if c.Group != "" {
+		groupID, err := a.int64ID(KindGroup, c.Group)
+		if err != nil {
+			return nil, err
+		}
+		doc["groupId"] = groupID
+	}
+	subs, err := a.subscriptions(c.AlertChannels)
+	if err != nil {
+		return nil, err
+	}
+	doc["alertChannelSubscriptions"] = subs
+	for field, key := range map[string]string{"setupSnippetId": c.SetupSnippet, "tearDownSnippetId": c.TearDownSnippet} {
+		delete(doc, field)
+		snippetID, err := a.snippetID(key)
+		if err != nil {
+			return nil, err
+		}
+		if snippetID != nil {
+			doc[field] = *snippetID
+		}
+	}
+	if trigger, ok := doc["triggerIncident"].(map[string]any); ok && c.IncidentService != "" {
+		serviceID, err := a.id(KindStatusPageService, c.IncidentService)
+		if err != nil {
+			return nil, err
+		}
+		trigger["serviceId"] = serviceID
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	return checkly.DecodeMonitor(data)
+}
+
+// delete deletes the existing resource of c.
+func (a *applier) delete(ctx context.Context, c Change) error {
+	var numericID int64
+	if numericKind(c.Kind) {
+		var err error
+		if numericID, err = parseID(c.ID); err != nil {
+			return err
+		}
+	}
+	switch v := c.live.value.(type) {
+	case Snippet:
+		return a.client.DeleteSnippet(ctx, numericID)
+	case checkly.EnvironmentVariable:
+		return a.client.DeleteEnvironmentVariable(ctx, c.ID)
+	case AlertChannel:
+		return a.client.DeleteAlertChannel(ctx, numericID)
+	case PrivateLocation:
+		return a.client.DeletePrivateLocation(ctx, c.ID)
+	case StatusPageService:
+		return a.client.DeleteStatusPageService(ctx, c.ID)
+	case StatusPage:
+		return a.client.DeleteStatusPage(ctx, c.ID)
+	case Group:
+		return a.client.DeleteGroupV2(ctx, numericID)
+	case Check:
+		return checkly.DeleteMonitor(ctx, a.client, v.Monitor)
+	case MaintenanceWindow:
+		return a.client.DeleteMaintenanceWindow(ctx, numericID)
+	case Dashboard:
+		return a.client.DeleteDashboard(ctx, c.ID)
+	}
+	return fmt.Errorf("unsupported resource %T", c.live.value)
+}
+
+// numericKind reports whether the IDs of resources of kind are numbers.
+func numericKind(kind Kind) bool {
+	switch kind {
+	case KindSnippet, KindAlertChannel, KindGroup, KindMaintenanceWindow:
+		return true
+	}
+	return false
+}
+
+// parseID parses the ID of an existing resource of a numericKind.
+func parseID(ID string) (int64, error) {
+	n, err := strconv.ParseInt(ID, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid ID %q: %w", ID, err)
+	}
+	return n, nil
+}
+
+// mergeLive returns the desired resource with the fields which are null or
+// missing in its document set to their values in the live resource, as the
+// API replaces the whole resource on updates.
+func mergeLive(desired, live *resource) (any, error) {
+	if live == nil {
+		return desired.value, nil
+	}
+	data, err := json.Marshal(mergeDocuments(desired.doc, live.doc))
+	if err != nil {
+		return nil, err
+	}
+	value := reflect.New(reflect.TypeOf(desired.value))
+	if err := json.Unmarshal(data, value.Interface()); err != nil {
+		return nil, err
+	}
+	return value.Elem().Interface(), nil
+}
+
+// mergeDocuments returns desired with its null or missing fields, also those
+// of nested objects, set to their values in live.
+func mergeDocuments(desired, live map[string]any) map[string]any {
+	result := make(map[string]any, len(live))
+	for k, v := range live {
+		result[k] = v
+	}
+	for k, v := range desired {
+		switch d := v.(type) {
+		case nil:
+			continue
+		case map[string]any:
+			if l, ok := live[k].(map[string]any); ok {
+				result[k] = mergeDocuments(d, l)
+				continue
+			}
+		}
+		result[k] = v
+	}
+	return result
+}
@@ -0,0 +1,413 @@
+package config_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	checkly "github.com/checkly/checkly-go-sdk"
+	"github.com/checkly/checkly-go-sdk/checklytest"
+	"github.com/checkly/checkly-go-sdk/config"
+)
+
+func stringPtr(s string) *string { return &s }
+
+func testState() config.State {
+	email := checkly.AlertChannel{Type: checkly.AlertTypeEmail}
+	email.SetConfig(&checkly.AlertChannelEmail{Address: "ops@example.com"})
+	return config.State{
+		AlertChannels: []config.AlertChannel{
+			{Key: "ops", AlertChannel: email},
+		},
+		Snippets: []config.Snippet{
+			{Snippet: checkly.Snippet{Name: "login", Script: "await login()"}},
+		},
+		EnvironmentVariables: []checkly.EnvironmentVariable{
+			{Key: "BASE_URL", Value: "https://example.com"},
+		},
+		StatusPageServices: []config.StatusPageService{
+			{Key: "web", Service: checkly.StatusPageService{Name: "Website"}},
+		},
+		StatusPages: []config.StatusPage{
+			{
+				StatusPage: checkly.StatusPage{Name: "Status", URL: "example-status"},
+				Cards:      []config.StatusPageCard{{Name: "Public", Services: []string{"web"}}},
+			},
+		},
+		Groups: []config.Group{
+			{
+				Key:           "website",
+				AlertChannels: []string{"ops"},
+				SetupSnippet:  "login",
+				Group: checkly.GroupV2{
+					Name:      "Website",
+					Activated: true,
+					Locations: []string{"eu-west-1"},
+					Tags:      []string{"web"},
+				},
+			},
+		},
+		Checks: []config.Check{
+			{
+				Key:             "homepage",
+				Group:           "website",
+				AlertChannels:   []string{"ops"},
+				IncidentService: "web",
+				Monitor: &checkly.URLMonitor{
+					Name:             "Homepage",
+					Frequency:        10,
+					Activated:        true,
+					Locations:        []string{"eu-west-1"},
+					Request:          checkly.URLRequest{URL: "https://example.com"},
+					PrivateLocations: &[]string{"office"},
+					TriggerIncident: &checkly.IncidentTrigger{
+						Name:     "Homepage down",
+						Severity: checkly.IncidentSeverityMajor,
+					},
+				},
+			},
+			{
+				Key: "heartbeat",
+				Monitor: &checkly.HeartbeatMonitor{
+					Name:      "Nightly job",
+					Activated: true,
+					Heartbeat: checkly.Heartbeat{Period: 1, PeriodUnit: "days", Grace: 1, GraceUnit: "hours"},
+				},
+			},
+		},
+		PrivateLocations: []config.PrivateLocation{
+			{PrivateLocation: checkly.PrivateLocation{Name: "Office", SlugName: "office"}},
+		},
+		Dashboards: []config.Dashboard{
+			{Dashboard: checkly.Dashboard{CustomUrl: "example-dashboard", Header: "Example", Tags: []string{"web"}}},
+		},
+		MaintenanceWindows: []config.MaintenanceWindow{
+			{
+				Key: "release",
+				MaintenanceWindow: checkly.MaintenanceWindow{
+					Name:     "Release",
+					StartsAt: "2025-01-01",
+					EndsAt:   "2025-01-02",
+				},
+			},
+		},
+	}
+}
+
+func mustPlan(t *testing.T, client checkly.Client, state config.State, opts ...config.Option) *config.Plan {
+	t.Helper()
+	plan, err := config.NewPlan(context.Background(), client, state, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return plan
+}
+
+func mustApply(t *testing.T, client checkly.Client, state config.State, opts ...config.Option) *config.Plan {
+	t.Helper()
+	plan := mustPlan(t, client, state, opts...)
+	if err := plan.Apply(context.Background(), client); err != nil {
+		t.Fatalf("applying plan:\n%s\n%v", plan, err)
+	}
+	return plan
+}
+
+func actions(plan *config.Plan) []string {
+	var result []string
+	for _, c := range plan.Changes {
+		result = append(result, string(c.Action)+" "+string(c.Kind)+" "+c.Key)
+	}
+	return result
+}
+
+func TestPlanCreatesInDependencyOrder(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	client := checklytest.NewClient()
+	plan := mustApply(t, client, testState())
+	want := []string{
+		"create snippet login",
+		"create environment variable BASE_URL",
+		"create alert channel ops",
+		"create private location office",
+		"create status page service web",
+		"create status page example-status",
+		"create group website",
+		"create check homepage",
+		"create check heartbeat",
+		"create maintenance window release",
+		"create dashboard example-dashboard",
+	}
+	if got := actions(plan); !slices.Equal(got, want) {
+		t.Errorf("want changes %q, got %q", want, got)
+	}
+
+	monitors, err := client.ListChecks(ctx, checkly.ListChecksOptions{}).Collect(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	homepage := monitors[0].(*checkly.URLMonitor)
+	groups, err := client.ListGroupsV2(ctx, checkly.ListOptions{}).Collect(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	services, err := client.ListStatusPageServices(ctx, checkly.ListOptions{}).Collect(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if homepage.GroupID != groups[0].ID {
+		t.Errorf("want the check in group %d, got %d", groups[0].ID, homepage.GroupID)
+	}
+	if homepage.TriggerIncident.ServiceID != services[0].ID {
+		t.Errorf("want the incident trigger on service %s, got %s", services[0].ID, homepage.TriggerIncident.ServiceID)
+	}
+	if !slices.Contains(homepage.Tags, config.KeyTag("homepage")) {
+		t.Errorf("want the key tag, got tags %q", homepage.Tags)
+	}
+	if len(homepage.AlertChannelSubscriptions) != 1 {
+		t.Errorf("want a subscription to the alert channel, got %v", homepage.AlertChannelSubscriptions)
+	}
+
+	if plan := mustPlan(t, client, testState()); !plan.Empty() {
+		t.Errorf("want no changes after applying, got:\n%s", plan)
+	}
+}
+
+func TestPlanUpdatesAndDeletes(t *testing.T) {
+	t.Parallel()
+	client := checklytest.NewClient()
+	mustApply(t, client, testState())
+
+	state := testState()
+	state.Checks[0].Monitor.SetFrequency(5)
+	state.Checks[0].Monitor.SetName("Home page")
+	state.MaintenanceWindows = nil
+	plan := mustApply(t, client, state)
+	want := []string{"update check homepage", "delete maintenance window release"}
+	if got := actions(plan); !slices.Equal(got, want) {
+		t.Fatalf("want changes %q, got %q", want, got)
+	}
+	fields := plan.Changes[0].Fields
+	if len(fields) != 2 || fields[0].Path != "frequency" || fields[1].Path != "name" {
+		t.Errorf("want frequency and name to change, got %+v", fields)
+	}
+	text := plan.String()
+	for _, line := range []string{
+		`~ update check "homepage" (Home page)`,
+		"    frequency: 10 => 5",
+		`- delete maintenance window "release" (Release)`,
+	} {
+		if !strings.Contains(text, line+"\n") {
+			t.Errorf("want the plan to contain %q, got:\n%s", line, text)
+		}
+	}
+	if plan := mustPlan(t, client, state); !plan.Empty() {
+		t.Errorf("want no changes after applying, got:\n%s", plan)
+	}
+}
+
+func TestApplyKeepsFieldsUnsetInState(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	client := checklytest.NewClient()
+	mustApply(t, client, testState())
+
+	state := testState()
+	homepage := state.Checks[0].Monitor.(*checkly.URLMonitor)
+	homepage.Name = "Home page"
+	homepage.PrivateLocations = nil
+	state.Groups[0].Group.Name = "Web site"
+	groups, err := client.ListGroupsV2(ctx, checkly.ListOptions{}).Collect(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	group := groups[0]
+	group.RetryStrategy = &checkly.RetryStrategy{Type: "FIXED", BaseBackoffSeconds: 60, MaxRetries: 2}
+	if _, err := client.UpdateGroupV2(ctx, group.ID, group); err != nil {
+		t.Fatal(err)
+	}
+
+	plan := mustApply(t, client, state)
+	for _, c := range plan.Changes {
+		if len(c.Fields) != 1 || c.Fields[0].Path != "name" {
+			t.Errorf("want only the name of %s %s to change, got %+v", c.Kind, c.Key, c.Fields)
+		}
+	}
+	monitors, err := client.ListChecks(ctx, checkly.ListChecksOptions{}).Collect(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range monitors {
+		if m, ok := m.(*checkly.URLMonitor); ok {
+			if m.Name != "Home page" || m.PrivateLocations == nil || !slices.Equal(*m.PrivateLocations, []string{"office"}) {
+				t.Errorf("want the renamed check to keep its private locations, got %q with %v", m.Name, m.PrivateLocations)
+			}
+		}
+	}
+	updated, err := client.GetGroupV2(ctx, group.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Name != "Web site" || updated.RetryStrategy == nil || updated.RetryStrategy.Type != "FIXED" {
+		t.Errorf("want the renamed group to keep its retry strategy, got %q with %+v", updated.Name, updated.RetryStrategy)
+	}
+}
+
+func TestApplyRemovesReferences(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	client := checklytest.NewClient()
+	mustApply(t, client, testState())
+
+	state := testState()
+	state.Checks[0].Group = ""
+	state.Checks[0].AlertChannels = nil
+	state.Groups[0].SetupSnippet = ""
+	plan := mustApply(t, client, state)
+	want := map[string][]string{
+		"website":  {"setupSnippet"},
+		"homepage": {"alertChannels", "group"},
+	}
+	for _, c := range plan.Changes {
+		var paths []string
+		for _, f := range c.Fields {
+			paths = append(paths, f.Path)
+		}
+		if !slices.Equal(paths, want[c.Key]) {
+			t.Errorf("want %s %s to change %v, got %v", c.Kind, c.Key, want[c.Key], paths)
+		}
+	}
+	if len(plan.Changes) != len(want) {
+		t.Errorf("want %d changes, got:\n%s", len(want), plan)
+	}
+
+	monitors, err := client.ListChecks(ctx, checkly.ListChecksOptions{}).Collect(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range monitors {
+		if m, ok := m.(*checkly.URLMonitor); ok && (m.GroupID != 0 || len(m.AlertChannelSubscriptions) != 0) {
+			t.Errorf("want the check out of its group and unsubscribed, got group %d and %+v", m.GroupID, m.AlertChannelSubscriptions)
+		}
+	}
+	groups, err := client.ListGroupsV2(ctx, checkly.ListOptions{}).Collect(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if groups[0].SetupSnippetID != nil {
+		t.Errorf("want the group without a setup snippet, got %d", *groups[0].SetupSnippetID)
+	}
+	if plan := mustPlan(t, client, state); !plan.Empty() {
+		t.Errorf("want no changes after applying, got:\n%s", plan)
+	}
+}
+
+func TestPlanReplacesCheckOfAnotherType(t *testing.T) {
+	t.Parallel()
+	client := checklytest.NewClient()
+	mustApply(t, client, testState())
+
+	state := testState()
+	state.Checks[0].Monitor = &checkly.TCPMonitor{
+		Name:      "Homepage",
+		Frequency: 10,
+		Request:   checkly.TCPRequest{Hostname: "example.com", Port: 443},
+	}
+	plan := mustApply(t, client, state)
+	want := []string{"create check homepage", "delete check homepage", "delete maintenance window release"}
+	if got := actions(plan); !slices.Equal(got[:2], want[:2]) {
+		t.Errorf("want changes %q, got %q", want[:2], got)
+	}
+}
+
+func TestPlanAdoptsExistingResources(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	client := checklytest.NewClient()
+	existing, err := client.CreateGroupV2(ctx, checkly.GroupV2{Name: "Website", Tags: []string{"web"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	unmanaged, err := client.CreateSnippet(ctx, checkly.Snippet{Name: "unmanaged"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	state := config.State{Groups: []config.Group{{Key: "website", Group: checkly.GroupV2{Name: "Website", Tags: []string{"web"}}}}}
+	plan := mustApply(t, client, state)
+	if got, want := actions(plan), []string{"update group website"}; !slices.Equal(got, want) {
+		t.Fatalf("want changes %q, got %q", want, got)
+	}
+	if f := plan.Changes[0].Fields; len(f) != 1 || f[0].Path != "key" {
+		t.Errorf("want only the key to change, got %+v", f)
+	}
+	group, err := client.GetGroupV2(ctx, existing.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(group.Tags, config.KeyTag("website")) {
+		t.Errorf("want the adopted group to be tagged, got tags %q", group.Tags)
+	}
+
+	// Once adopted, the group is deleted when it is removed from the State,
+	// while resources which were never managed are only pruned on request.
+	plan = mustPlan(t, client, config.State{})
+	if got, want := actions(plan), []string{"delete group website"}; !slices.Equal(got, want) {
+		t.Errorf("want changes %q, got %q", want, got)
+	}
+	plan = mustApply(t, client, config.State{}, config.WithPrune())
+	if got, want := actions(plan), []string{"delete group website", "delete snippet unmanaged"}; !slices.Equal(got, want) {
+		t.Errorf("want changes %q, got %q", want, got)
+	}
+	if _, err := client.GetSnippet(ctx, unmanaged.ID); !checkly.IsNotFound(err) {
+		t.Errorf("want the pruned snippet to be deleted, got %v", err)
+	}
+}
+
+func TestLoadState(t *testing.T) {
+	t.Parallel()
+	client := checklytest.NewClient()
+	mustApply(t, client, testState())
+
+	data, err := json.MarshalIndent(testState(), "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "state.json")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	state, err := config.LoadState(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := state.Checks[0].Monitor.(*checkly.URLMonitor); !ok {
+		t.Errorf("want the check to be decoded as a URL monitor, got %T", state.Checks[0].Monitor)
+	}
+	if plan := mustPlan(t, client, *state); !plan.Empty() {
+		t.Errorf("want the loaded state to match the account, got:\n%s", plan)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+	state := testState()
+	state.Checks[0].Group = "missing"
+	state.Checks = append(state.Checks, state.Checks[1])
+	err := state.Validate()
+	if err == nil {
+		t.Fatal("want an error")
+	}
+	for _, msg := range []string{`check "homepage" references unknown group "missing"`, `duplicate check "heartbeat"`} {
+		if !strings.Contains(err.Error(), msg) {
+			t.Errorf("want %q in the error, got %v", msg, err)
+		}
+	}
+	if _, err := config.NewPlan(context.Background(), checklytest.NewClient(), state); err == nil {
+		t.Error("want NewPlan to validate the state")
+	}
+}
@@ -0,0 +1,152 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	checkly "github.com/checkly/checkly-go-sdk"
+)
+
+// withDefaults returns the document of r with its unset fields set to the
+// values the API gives them, see checkly.WithDefaults.
+func (r resource) withDefaults() map[string]any {
+	doc, err := toDocument(checkly.WithDefaults(r.value))
+	if err != nil {
+		return r.doc
+	}
+	withReferenceFields(r.kind, doc)
+	return doc
+}
+
+// DriftStatus is how a resource of the account differs from the desired
+// State.
+type DriftStatus string
+
+const (
+	// Missing resources are part of the desired State but not of the
+	// account.
+	Missing DriftStatus = "missing"
+	// Changed resources have fields differing from the desired State.
+	Changed DriftStatus = "changed"
+	// Unexpected resources are part of the account but not of the desired
+	// State, see WithPrune.
+	Unexpected DriftStatus = "unexpected"
+)
+
+// Drift is the difference between a single resource of the account and the
+// desired State.
+type Drift struct {
+	Status DriftStatus `json:"status"`
+	Kind   Kind        `json:"kind"`
+	Key    string      `json:"key"`
+	// Name is a human-readable name of the resource.
+	Name string `json:"name,omitempty"`
+	// ID is the ID of the resource in the account, unless it is missing.
+	ID string `json:"id,omitempty"`
+	// Fields lists the fields of changed resources which differ.
+	Fields []FieldDrift `json:"fields,omitempty"`
+}
+
+// FieldDrift is the difference of a single field of a resource. Path is the
+// JSON path of the field, Desired and Live are its JSON values in the desired
+// State and in the account.
+type FieldDrift struct {
+	Path    string `json:"path"`
+	Desired any    `json:"desired"`
+	Live    any    `json:"live"`
+}
+
+// DriftReport lists the resources of an account which differ from a desired
+// State, in dependency order. It encodes to JSON as an object with a
+// "resources" array.
+type DriftReport struct {
+	Resources []Drift `json:"resources"`
+}
+
+// DetectDrift fetches the configuration of the account and reports how it
+// differs from desired, without changing it. Unlike NewPlan, fields left unset
+// in desired are compared with the defaults the API gives them, see
+// checkly.WithDefaults, so a retry strategy set in the web UI is reported
+// while a nil one matches the FALLBACK strategy of the account. References
+// added in the web UI, like the group of a check without one in desired, are
+// reported too.
+//
+// Of the options, only WithPrune applies: it reports the resources which are
+// not part of desired as Unexpected.
+func DetectDrift(ctx context.Context, client checkly.Client, desired State, opts ...Option) (*DriftReport, error) {
+	o := options{defaults: true}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if err := desired.Validate(); err != nil {
+		return nil, err
+	}
+	l, err := fetch(ctx, client, desired)
+	if err != nil {
+		return nil, err
+	}
+	return newDriftReport(newPlan(desired.normalized(), l, o)), nil
+}
+
+func newDriftReport(p *Plan) *DriftReport {
+	// Checks of another type are replaced by plans, which is reported as
+	// a change of their type.
+	replaced := map[reference]*Change{}
+	for i, c := range p.Changes {
+		if c.Action == Delete {
+			replaced[reference{c.Kind, c.Key}] = &p.Changes[i]
+		}
+	}
+	r := &DriftReport{Resources: []Drift{}}
+	for _, c := range p.Changes {
+		d := Drift{Kind: c.Kind, Key: c.Key, Name: c.Name, ID: c.ID}
+		switch c.Action {
+		case Create:
+			d.Status = Missing
+			if old, ok := replaced[reference{c.Kind, c.Key}]; ok {
+				d.Status, d.ID = Changed, old.ID
+				d.Fields = []FieldDrift{{Path: "checkType", Desired: c.desired.doc["checkType"], Live: old.live.doc["checkType"]}}
+				delete(replaced, reference{c.Kind, c.Key})
+			}
+		case Update:
+			d.Status = Changed
+			for _, f := range c.Fields {
+				d.Fields = append(d.Fields, FieldDrift{Path: f.Path, Desired: f.New, Live: f.Old})
+			}
+		case Delete:
+			if _, ok := replaced[reference{c.Kind, c.Key}]; !ok {
+				continue
+			}
+			d.Status = Unexpected
+		}
+		r.Resources = append(r.Resources, d)
+	}
+	return r
+}
+
+// Drifted reports whether the account differs from the desired State.
+func (r *DriftReport) Drifted() bool {
+	return len(r.Resources) > 0
+}
+
+// String describes the report, one resource per line starting with its
+// status, followed by its differing fields as "path: want desired, got live"
+// with JSON values.
+func (r *DriftReport) String() string {
+	if !r.Drifted() {
+		return "No drift.\n"
+	}
+	var b strings.Builder
+	for _, d := range r.Resources {
+		fmt.Fprintf(&b, "%s %s %q", d.Status, d.Kind, d.Key)
+		if d.Name != "" && d.Name != d.Key {
+			fmt.Fprintf(&b, " (%s)", d.Name)
+		}
+		b.WriteString("\n")
+		for _, f := range d.Fields {
+			fmt.Fprintf(&b, "    %s: want %s, got %s\n", f.Path, formatValue(f.Desired), formatValue(f.Live))
+		}
+	}
+	return b.String()
+}
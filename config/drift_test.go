@@ -0,0 +1,199 @@
+package config_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	checkly "github.com/checkly/checkly-go-sdk"
+	"github.com/checkly/checkly-go-sdk/checklytest"
+	"github.com/checkly/checkly-go-sdk/config"
+)
+
+func mustDetectDrift(t *testing.T, client checkly.Client, state config.State) *config.DriftReport {
+	t.Helper()
+	report, err := config.DetectDrift(context.Background(), client, state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return report
+}
+
+func TestDetectDrift(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	client := checklytest.NewClient()
+	mustApply(t, client, testState())
+	if report := mustDetectDrift(t, client, testState()); report.Drifted() {
+		t.Fatalf("want no drift after applying, got:\n%s", report)
+	}
+
+	// Defaults set explicitly match the unset fields of the state.
+	groups, err := client.ListGroupsV2(ctx, checkly.ListOptions{}).Collect(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	group := groups[0]
+	group.PrivateLocations = &[]string{}
+	group.RetryStrategy = &checkly.RetryStrategy{Type: "FALLBACK"}
+	if _, err := client.UpdateGroupV2(ctx, group.ID, group); err != nil {
+		t.Fatal(err)
+	}
+	dashboards, err := client.ListDashboards(ctx, checkly.ListOptions{}).Collect(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dashboard := dashboards[0]
+	show := true
+	dashboard.ShowP95 = &show
+	if _, err := client.UpdateDashboard(ctx, dashboard.DashboardID, dashboard); err != nil {
+		t.Fatal(err)
+	}
+	if report := mustDetectDrift(t, client, testState()); report.Drifted() {
+		t.Fatalf("want explicit defaults not to drift, got:\n%s", report)
+	}
+
+	// Edits made outside of the state drift.
+	monitors, err := client.ListChecks(ctx, checkly.ListChecksOptions{}).Collect(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	homepage := monitors[0]
+	homepage.SetFrequency(5)
+	if _, err := checkly.UpdateMonitor(ctx, client, homepage); err != nil {
+		t.Fatal(err)
+	}
+	hide := false
+	dashboard.ShowP99 = &hide
+	if _, err := client.UpdateDashboard(ctx, dashboard.DashboardID, dashboard); err != nil {
+		t.Fatal(err)
+	}
+	group.RetryStrategy = &checkly.RetryStrategy{Type: "FIXED", BaseBackoffSeconds: 60, MaxRetries: 2, MaxDurationSeconds: 600}
+	if _, err := client.UpdateGroupV2(ctx, group.ID, group); err != nil {
+		t.Fatal(err)
+	}
+	windows, err := client.ListMaintenanceWindows(ctx, checkly.ListOptions{}).Collect(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.DeleteMaintenanceWindow(ctx, windows[0].ID); err != nil {
+		t.Fatal(err)
+	}
+
+	report := mustDetectDrift(t, client, testState())
+	want := []string{
+		"changed group website retryStrategy",
+		"changed check homepage frequency",
+		"missing maintenance window release",
+		"changed dashboard example-dashboard showP99",
+	}
+	var got []string
+	for _, d := range report.Resources {
+		line := string(d.Status) + " " + string(d.Kind) + " " + d.Key
+		for _, f := range d.Fields {
+			line += " " + f.Path
+		}
+		got = append(got, line)
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("want drift %q, got %q", want, got)
+	}
+	text := report.String()
+	for _, line := range []string{
+		`changed check "homepage" (Homepage)`,
+		"    frequency: want 10, got 5",
+		`missing maintenance window "release" (Release)`,
+	} {
+		if !strings.Contains(text, line+"\n") {
+			t.Errorf("want the report to contain %q, got:\n%s", line, text)
+		}
+	}
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded struct {
+		Resources []struct {
+			Status string
+			Fields []struct {
+				Path    string
+				Desired any
+				Live    any
+			}
+		}
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if f := decoded.Resources[1].Fields[0]; f.Path != "frequency" || f.Desired != 10.0 || f.Live != 5.0 {
+		t.Errorf("want the frequency to drift from 10 to 5 in JSON, got %s", data)
+	}
+}
+
+func TestDetectDriftReportsReplacedChecks(t *testing.T) {
+	t.Parallel()
+	client := checklytest.NewClient()
+	mustApply(t, client, testState())
+
+	state := testState()
+	state.Checks[0].Monitor = &checkly.TCPMonitor{
+		Name:      "Homepage",
+		Frequency: 10,
+		Request:   checkly.TCPRequest{Hostname: "example.com", Port: 443},
+	}
+	report := mustDetectDrift(t, client, state)
+	if len(report.Resources) != 1 {
+		t.Fatalf("want a single drifted resource, got:\n%s", report)
+	}
+	d := report.Resources[0]
+	if d.Status != config.Changed || d.ID == "" || len(d.Fields) != 1 || d.Fields[0].Path != "checkType" {
+		t.Errorf("want the type of the check to change, got %+v", d)
+	}
+}
+
+func TestDetectDriftReportsReferencesAddedOutOfBand(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	client := checklytest.NewClient()
+	state := testState()
+	state.Checks[0].Group = ""
+	state.Checks[0].AlertChannels = nil
+	mustApply(t, client, state)
+
+	groups, err := client.ListGroupsV2(ctx, checkly.ListOptions{}).Collect(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	channels, err := client.ListAlertChannels(ctx, checkly.ListOptions{}).Collect(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	monitors, err := client.ListChecks(ctx, checkly.ListChecksOptions{}).Collect(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range monitors {
+		if m, ok := m.(*checkly.URLMonitor); ok {
+			m.GroupID = groups[0].ID
+			m.AlertChannelSubscriptions = []checkly.AlertChannelSubscription{{ChannelID: channels[0].ID, Activated: true}}
+			if _, err := checkly.UpdateMonitor(ctx, client, m); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	report := mustDetectDrift(t, client, state)
+	if len(report.Resources) != 1 {
+		t.Fatalf("want a single drifted resource, got:\n%s", report)
+	}
+	d := report.Resources[0]
+	var paths []string
+	for _, f := range d.Fields {
+		paths = append(paths, f.Path)
+	}
+	if d.Key != "homepage" || strings.Join(paths, " ") != "alertChannels group" {
+		t.Errorf("want the group and alert channel of the check to drift, got:\n%s", report)
+	}
+}
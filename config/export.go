@@ -0,0 +1,346 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	checkly "github.com/checkly/checkly-go-sdk"
+	"gopkg.in/yaml.v3"
+)
+
+// Format is the encoding of the files written by Export and WriteDir.
+type Format string
+
+const (
+	YAML Format = "yaml"
+	JSON Format = "json"
+)
+
+// WithFormat sets the format of the files written by Export and WriteDir,
+// YAML by default.
+func WithFormat(format Format) Option {
+	return func(o *options) {
+		o.format = format
+	}
+}
+
+// kindDirs are the directories holding the resources of each kind in the
+// directories written by WriteDir, in dependency order.
+var kindDirs = []struct {
+	kind Kind
+	dir  string
+}{
+	{KindSnippet, "snippets"},
+	{KindEnvironmentVariable, "environment-variables"},
+	{KindAlertChannel, "alert-channels"},
+	{KindPrivateLocation, "private-locations"},
+	{KindStatusPageService, "status-page-services"},
+	{KindStatusPage, "status-pages"},
+	{KindGroup, "groups"},
+	{KindCheck, "checks"},
+	{KindMaintenanceWindow, "maintenance-windows"},
+	{KindDashboard, "dashboards"},
+}
+
+// Fetch returns the configuration of the account as a State. Checks, groups
+// and maintenance windows without a key tag are keyed by name, other
+// resources by their natural identity, see the package documentation.
+func Fetch(ctx context.Context, client checkly.Client) (*State, error) {
+	l, err := fetch(ctx, client, State{})
+	if err != nil {
+		return nil, err
+	}
+	return &l.state, nil
+}
+
+// Export writes the configuration of the account to dir, see WriteDir.
+// Client certificates are not exported, as the API doesn't return their
+// private keys, and neither are the values of secret environment variables.
+func Export(ctx context.Context, client checkly.Client, dir string, opts ...Option) error {
+	s, err := Fetch(ctx, client)
+	if err != nil {
+		return err
+	}
+	return s.WriteDir(dir, opts...)
+}
+
+// Import reads the configuration written to dir by Export and applies it to
+// the account, remapping the references between resources to the IDs they
+// get in the account. It returns the applied plan.
+func Import(ctx context.Context, client checkly.Client, dir string, opts ...Option) (*Plan, error) {
+	s, err := LoadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := NewPlan(ctx, client, *s, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return plan, plan.Apply(ctx, client)
+}
+
+// WriteDir writes every resource of s to its own file, in a directory per
+// kind of resource, for example checks/homepage.yaml. Fields set by the
+// server, like IDs, creation dates and ping tokens, are left out and
+// references between resources use keys. Files left in those directories by
+// earlier calls are removed.
+func (s State) WriteDir(dir string, opts ...Option) error {
+	o := options{format: YAML}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.format != YAML && o.format != JSON {
+		return fmt.Errorf("unsupported format %q", o.format)
+	}
+	byKind := map[Kind][]resource{}
+	for _, r := range s.normalized().resources() {
+		byKind[r.kind] = append(byKind[r.kind], r)
+	}
+	for _, kd := range kindDirs {
+		kindDir := filepath.Join(dir, kd.dir)
+		if err := removeResourceFiles(kindDir); err != nil {
+			return err
+		}
+		if len(byKind[kd.kind]) == 0 {
+			continue
+		}
+		if err := os.MkdirAll(kindDir, 0o755); err != nil {
+			return err
+		}
+		used := map[string]bool{}
+		for _, r := range byKind[kd.kind] {
+			data, err := json.Marshal(r.value)
+			if err != nil {
+				return fmt.Errorf("encoding %s %q: %w", r.kind, r.key, err)
+			}
+			data, err = encodeFile(data, o.format)
+			if err != nil {
+				return fmt.Errorf("encoding %s %q: %w", r.kind, r.key, err)
+			}
+			name := fileName(r.key, used)
+			if err := os.WriteFile(filepath.Join(kindDir, name+"."+string(o.format)), data, 0o644); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// LoadDir reads a State from a directory written by WriteDir. Files are read
+// in name order, YAML and JSON files may be mixed.
+func LoadDir(dir string) (*State, error) {
+	if _, err := os.Stat(dir); err != nil {
+		return nil, err
+	}
+	var s State
+	for _, kd := range kindDirs {
+		paths, err := resourceFiles(filepath.Join(dir, kd.dir))
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range paths {
+			data, err := readJSON(path)
+			if err != nil {
+				return nil, err
+			}
+			if err := s.add(kd.kind, data); err != nil {
+				return nil, fmt.Errorf("decoding %s: %w", path, err)
+			}
+		}
+	}
+	return &s, nil
+}
+
+// add decodes a resource of kind from data and appends it to s.
+func (s *State) add(kind Kind, data []byte) error {
+	switch kind {
+	case KindSnippet:
+		return appendDecoded(&s.Snippets, data)
+	case KindEnvironmentVariable:
+		return appendDecoded(&s.EnvironmentVariables, data)
+	case KindAlertChannel:
+		return appendDecoded(&s.AlertChannels, data)
+	case KindPrivateLocation:
+		return appendDecoded(&s.PrivateLocations, data)
+	case KindStatusPageService:
+		return appendDecoded(&s.StatusPageServices, data)
+	case KindStatusPage:
+		return appendDecoded(&s.StatusPages, data)
+	case KindGroup:
+		return appendDecoded(&s.Groups, data)
+	case KindCheck:
+		return appendDecoded(&s.Checks, data)
+	case KindMaintenanceWindow:
+		return appendDecoded(&s.MaintenanceWindows, data)
+	case KindDashboard:
+		return appendDecoded(&s.Dashboards, data)
+	}
+	return fmt.Errorf("unsupported kind %q", kind)
+}
+
+func appendDecoded[T any](items *[]T, data []byte) error {
+	var item T
+	if err := json.Unmarshal(data, &item); err != nil {
+		return err
+	}
+	*items = append(*items, item)
+	return nil
+}
+
+// resourceFiles returns the YAML and JSON files of dir in name order, or
+// none if dir doesn't exist.
+func resourceFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, e := range entries {
+		if !e.IsDir() && isResourceFile(e.Name()) {
+			paths = append(paths, filepath.Join(dir, e.Name()))
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+func removeResourceFiles(dir string) error {
+	paths, err := resourceFiles(dir)
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func isResourceFile(name string) bool {
+	switch filepath.Ext(name) {
+	case ".json", ".yaml", ".yml":
+		return true
+	}
+	return false
+}
+
+// readJSON reads the JSON or YAML file at path and returns its content as
+// JSON.
+func readJSON(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if filepath.Ext(path) == ".json" {
+		return data, nil
+	}
+	data, err = yamlToJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", path, err)
+	}
+	return data, nil
+}
+
+func yamlToJSON(data []byte) ([]byte, error) {
+	var v any
+	if err := yaml.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// encodeFile re-encodes a JSON document in format, leaving out null fields.
+func encodeFile(data []byte, format Format) ([]byte, error) {
+	var v any
+	d := json.NewDecoder(bytes.NewReader(data))
+	// Decode numbers as json.Number, so that large IDs keep their
+	// precision.
+	d.UseNumber()
+	if err := d.Decode(&v); err != nil {
+		return nil, err
+	}
+	v = withoutNulls(v)
+	if format == JSON {
+		data, err := json.MarshalIndent(v, "", "  ")
+		return append(data, '\n'), err
+	}
+	return yaml.Marshal(yamlNumbers(v))
+}
+
+// withoutNulls removes the null fields of the objects in v.
+func withoutNulls(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, item := range v {
+			if item == nil {
+				delete(v, k)
+				continue
+			}
+			v[k] = withoutNulls(item)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = withoutNulls(item)
+		}
+	}
+	return v
+}
+
+// yamlNumbers replaces the json.Number values in v with integers or floats,
+// which YAML encodes without quotes.
+func yamlNumbers(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, item := range v {
+			v[k] = yamlNumbers(item)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = yamlNumbers(item)
+		}
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+		f, _ := v.Float64()
+		return f
+	}
+	return v
+}
+
+// fileName returns a file name without extension for key, unique among
+// used.
+func fileName(key string, used map[string]bool) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(key) {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '_' || r == '.' {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	base := strings.Trim(b.String(), "-.")
+	if base == "" {
+		base = "resource"
+	}
+	name := base
+	for i := 2; used[name]; i++ {
+		name = base + "-" + strconv.Itoa(i)
+	}
+	used[name] = true
+	return name
+}
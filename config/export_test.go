@@ -0,0 +1,111 @@
+package config_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	checkly "github.com/checkly/checkly-go-sdk"
+	"github.com/checkly/checkly-go-sdk/checklytest"
+	"github.com/checkly/checkly-go-sdk/config"
+)
+
+func TestExportImport(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	source := checklytest.NewClient()
+	mustApply(t, source, testState())
+	dir := t.TempDir()
+	if err := config.Export(ctx, source, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "checks", "homepage.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	exported := string(data)
+	// Without a desired State, resources without a key tag are keyed by
+	// their natural identity.
+	for _, want := range []string{"key: homepage\n", "group: website\n", "incidentService: Website\n", "checkType: URL\n", "- email:ops@example.com\n"} {
+		if !strings.Contains(exported, want) {
+			t.Errorf("want %q in the exported check, got:\n%s", want, exported)
+		}
+	}
+	for _, unwanted := range []string{"id:", "groupId:", "created_at:", "serviceId:", "null", config.KeyTagPrefix} {
+		if strings.Contains(exported, unwanted) {
+			t.Errorf("want no %q in the exported check, got:\n%s", unwanted, exported)
+		}
+	}
+	data, err = os.ReadFile(filepath.Join(dir, "checks", "heartbeat.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "pingToken") {
+		t.Errorf("want no ping token in the exported heartbeat, got:\n%s", data)
+	}
+
+	// IDs in the target account differ from those in the source one.
+	target := checklytest.NewClient()
+	for _, name := range []string{"one", "two", "three"} {
+		if _, err := target.CreateGroupV2(ctx, checkly.GroupV2{Name: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := config.Import(ctx, target, dir); err != nil {
+		t.Fatal(err)
+	}
+	monitors, err := target.ListChecks(ctx, checkly.ListChecksOptions{}).Collect(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	groups, err := target.ListGroupsV2(ctx, checkly.ListOptions{}).Collect(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Files are read in name order, the heartbeat check comes first.
+	if got, want := monitors[1].GetGroupID(), groups[3].ID; got != want {
+		t.Errorf("want the imported check in group %d, got %d", want, got)
+	}
+	if plan := mustPlan(t, target, testState()); !plan.Empty() {
+		t.Errorf("want the imported account to match the state, got:\n%s", plan)
+	}
+}
+
+func TestWriteDirJSON(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	// Files of earlier exports are replaced.
+	stale := filepath.Join(dir, "snippets", "stale.json")
+	if err := os.MkdirAll(filepath.Dir(stale), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(stale, []byte("{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	state := testState()
+	if err := state.WriteDir(dir, config.WithFormat(config.JSON)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Errorf("want the stale file to be removed, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "alert-channels", "email-ops-example.com.json")); err == nil {
+		t.Error("want alert channels to be named by key")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "alert-channels", "ops.json")); err != nil {
+		t.Error(err)
+	}
+
+	loaded, err := config.LoadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := checklytest.NewClient()
+	mustApply(t, client, *loaded)
+	if plan := mustPlan(t, client, testState()); !plan.Empty() {
+		t.Errorf("want the loaded state to match the original one, got:\n%s", plan)
+	}
+}
@@ -0,0 +1,306 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	checkly "github.com/checkly/checkly-go-sdk"
+)
+
+// live is the configuration of an account as fetched from the API. Its state
+// references resources by key like a desired State, ids and managed hold the
+// ID and whether the key was read from a tag for each of its resources.
+type live struct {
+	state   State
+	ids     map[Kind]map[string]string
+	managed map[Kind]map[string]bool
+}
+
+func (l *live) set(kind Kind, key, ID string, managed bool) {
+	if l.ids[kind] == nil {
+		l.ids[kind] = map[string]string{}
+		l.managed[kind] = map[string]bool{}
+	}
+	l.ids[kind][key] = ID
+	l.managed[kind][key] = managed
+}
+
+// keyer assigns keys to the resources of a kind fetched from the account,
+// preferring the keys of the desired resources they match.
+type keyer struct {
+	// desired maps the natural identities of desired resources to their
+	// keys. For the kinds storing their key in a tag the identity is the
+	// name, used to adopt resources without the tag.
+	desired map[string]string
+	claimed map[string]bool
+	used    map[string]bool
+}
+
+func newKeyer() *keyer {
+	return &keyer{desired: map[string]string{}, claimed: map[string]bool{}, used: map[string]bool{}}
+}
+
+// claim reserves key, read from the tag of a resource.
+func (k *keyer) claim(key string) {
+	k.claimed[key] = true
+}
+
+// key returns the key of a resource with the given identity. Keys are unique,
+// duplicates get a numbered suffix.
+func (k *keyer) key(tagged string, identity string) (key string, managed bool) {
+	adopted := false
+	switch {
+	case tagged != "":
+		key, managed = tagged, true
+	case k.desired[identity] != "" && !k.claimed[k.desired[identity]]:
+		key, adopted = k.desired[identity], true
+		k.claimed[key] = true
+	default:
+		key = identity
+	}
+	// Other resources don't take the keys claimed by tags or adoption.
+	base := key
+	for i := 2; k.used[key] || !managed && !adopted && k.claimed[key]; i++ {
+		key = fmt.Sprintf("%s (%d)", base, i)
+	}
+	k.used[key] = true
+	return key, managed
+}
+
+// fetch reads the configuration of the account. Resources are given the keys
+// of the resources of desired they match.
+func fetch(ctx context.Context, client checkly.Client, desired State) (*live, error) {
+	desired = desired.normalized()
+	l := &live{ids: map[Kind]map[string]string{}, managed: map[Kind]map[string]bool{}}
+	all := checkly.ListOptions{}
+
+	snippets, err := client.ListSnippets(ctx, all).Collect(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing snippets: %w", err)
+	}
+	snippetKeys := map[int64]string{}
+	k := newKeyer()
+	for _, s := range desired.Snippets {
+		k.desired[s.Snippet.Name] = s.Key
+	}
+	for _, s := range snippets {
+		key, _ := k.key("", s.Name)
+		snippetKeys[s.ID] = key
+		l.set(KindSnippet, key, strconv.FormatInt(s.ID, 10), false)
+		l.state.Snippets = append(l.state.Snippets, Snippet{Key: key, Snippet: s})
+	}
+
+	vars, err := client.ListEnvironmentVariables(ctx, all).Collect(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing environment variables: %w", err)
+	}
+	for _, v := range vars {
+		l.set(KindEnvironmentVariable, v.Key, v.Key, false)
+		l.state.EnvironmentVariables = append(l.state.EnvironmentVariables, v)
+	}
+
+	channels, err := client.ListAlertChannels(ctx, all).Collect(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing alert channels: %w", err)
+	}
+	channelKeys := map[int64]string{}
+	k = newKeyer()
+	for _, ac := range desired.AlertChannels {
+		k.desired[alertChannelIdentity(ac.AlertChannel)] = ac.Key
+	}
+	for _, ac := range channels {
+		key, _ := k.key("", alertChannelIdentity(ac))
+		channelKeys[ac.ID] = key
+		l.set(KindAlertChannel, key, strconv.FormatInt(ac.ID, 10), false)
+		l.state.AlertChannels = append(l.state.AlertChannels, AlertChannel{Key: key, AlertChannel: ac})
+	}
+
+	locations, err := client.ListPrivateLocations(ctx, all).Collect(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing private locations: %w", err)
+	}
+	k = newKeyer()
+	for _, pl := range desired.PrivateLocations {
+		k.desired[pl.PrivateLocation.SlugName] = pl.Key
+	}
+	for _, pl := range locations {
+		key, _ := k.key("", pl.SlugName)
+		l.set(KindPrivateLocation, key, pl.ID, false)
+		l.state.PrivateLocations = append(l.state.PrivateLocations, PrivateLocation{Key: key, PrivateLocation: pl})
+	}
+
+	services, err := client.ListStatusPageServices(ctx, all).Collect(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing status page services: %w", err)
+	}
+	serviceKeys := map[string]string{}
+	k = newKeyer()
+	for _, s := range desired.StatusPageServices {
+		k.desired[s.Service.Name] = s.Key
+	}
+	for _, s := range services {
+		key, _ := k.key("", s.Name)
+		serviceKeys[s.ID] = key
+		l.set(KindStatusPageService, key, s.ID, false)
+		l.state.StatusPageServices = append(l.state.StatusPageServices, StatusPageService{Key: key, Service: s})
+	}
+
+	pages, err := client.ListStatusPages(ctx, all).Collect(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing status pages: %w", err)
+	}
+	k = newKeyer()
+	for _, p := range desired.StatusPages {
+		k.desired[p.StatusPage.URL] = p.Key
+	}
+	for _, p := range pages {
+		key, _ := k.key("", p.URL)
+		page := StatusPage{Key: key, StatusPage: p}
+		for _, card := range p.Cards {
+			c := StatusPageCard{Name: card.Name, Services: []string{}}
+			for _, s := range card.Services {
+				c.Services = append(c.Services, serviceKeys[s.ID])
+			}
+			page.Cards = append(page.Cards, c)
+		}
+		l.set(KindStatusPage, key, p.ID, false)
+		l.state.StatusPages = append(l.state.StatusPages, page)
+	}
+
+	groups, err := client.ListGroupsV2(ctx, all).Collect(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing groups: %w", err)
+	}
+	groupKeys := map[int64]string{}
+	k = newKeyer()
+	for _, g := range desired.Groups {
+		k.desired[g.Group.Name] = g.Key
+	}
+	for _, g := range groups {
+		if key, ok := keyFromTags(g.Tags); ok {
+			k.claim(key)
+		}
+	}
+	for _, g := range groups {
+		tagged, _ := keyFromTags(g.Tags)
+		key, managed := k.key(tagged, g.Name)
+		groupKeys[g.ID] = key
+		group := Group{
+			Key:           key,
+			AlertChannels: subscribedKeys(g.AlertChannelSubscriptions, channelKeys),
+			Group:         g,
+		}
+		if g.SetupSnippetID != nil {
+			group.SetupSnippet = snippetKeys[*g.SetupSnippetID]
+		}
+		if g.TearDownSnippetID != nil {
+			group.TearDownSnippet = snippetKeys[*g.TearDownSnippetID]
+		}
+		l.set(KindGroup, key, strconv.FormatInt(g.ID, 10), managed)
+		l.state.Groups = append(l.state.Groups, group)
+	}
+
+	monitors, err := client.ListChecks(ctx, checkly.ListChecksOptions{}).Collect(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing checks: %w", err)
+	}
+	k = newKeyer()
+	for _, c := range desired.Checks {
+		if c.Monitor != nil {
+			k.desired[c.Monitor.GetName()] = c.Key
+		}
+	}
+	for _, m := range monitors {
+		if key, ok := keyFromTags(m.GetTags()); ok {
+			k.claim(key)
+		}
+	}
+	for _, m := range monitors {
+		tagged, _ := keyFromTags(m.GetTags())
+		key, managed := k.key(tagged, m.GetName())
+		check := Check{
+			Key:     key,
+			Group:   groupKeys[m.GetGroupID()],
+			Monitor: m,
+		}
+		fields, err := toDocument(m)
+		if err != nil {
+			return nil, err
+		}
+		check.AlertChannels = subscribedKeys(documentSubscriptions(fields), channelKeys)
+		check.SetupSnippet = snippetKeys[documentInt(fields, "setupSnippetId")]
+		check.TearDownSnippet = snippetKeys[documentInt(fields, "tearDownSnippetId")]
+		if trigger, ok := fields["triggerIncident"].(map[string]any); ok {
+			serviceID, _ := trigger["serviceId"].(string)
+			check.IncidentService = serviceKeys[serviceID]
+		}
+		l.set(KindCheck, key, m.GetID(), managed)
+		l.state.Checks = append(l.state.Checks, check)
+	}
+
+	windows, err := client.ListMaintenanceWindows(ctx, all).Collect(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing maintenance windows: %w", err)
+	}
+	k = newKeyer()
+	for _, w := range desired.MaintenanceWindows {
+		k.desired[w.MaintenanceWindow.Name] = w.Key
+	}
+	for _, w := range windows {
+		if key, ok := keyFromTags(w.Tags); ok {
+			k.claim(key)
+		}
+	}
+	for _, w := range windows {
+		tagged, _ := keyFromTags(w.Tags)
+		key, managed := k.key(tagged, w.Name)
+		l.set(KindMaintenanceWindow, key, strconv.FormatInt(w.ID, 10), managed)
+		l.state.MaintenanceWindows = append(l.state.MaintenanceWindows, MaintenanceWindow{Key: key, MaintenanceWindow: w})
+	}
+
+	dashboards, err := client.ListDashboards(ctx, all).Collect(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing dashboards: %w", err)
+	}
+	k = newKeyer()
+	for _, d := range desired.Dashboards {
+		k.desired[d.Dashboard.CustomUrl] = d.Key
+	}
+	for _, d := range dashboards {
+		key, _ := k.key("", d.CustomUrl)
+		l.set(KindDashboard, key, d.DashboardID, false)
+		l.state.Dashboards = append(l.state.Dashboards, Dashboard{Key: key, Dashboard: d})
+	}
+	return l, nil
+}
+
+// subscribedKeys returns the keys of the activated alert channels of subs.
+func subscribedKeys(subs []checkly.AlertChannelSubscription, keys map[int64]string) []string {
+	var result []string
+	for _, sub := range subs {
+		if key, ok := keys[sub.ChannelID]; ok && sub.Activated {
+			result = append(result, key)
+		}
+	}
+	return result
+}
+
+// documentSubscriptions reads the alert channel subscriptions of a check
+// from its JSON document, as Monitor has no accessor for them.
+func documentSubscriptions(doc map[string]any) []checkly.AlertChannelSubscription {
+	var subs []checkly.AlertChannelSubscription
+	items, _ := doc["alertChannelSubscriptions"].([]any)
+	for _, item := range items {
+		fields, _ := item.(map[string]any)
+		ID, _ := fields["alertChannelId"].(float64)
+		activated, _ := fields["activated"].(bool)
+		subs = append(subs, checkly.AlertChannelSubscription{ChannelID: int64(ID), Activated: activated})
+	}
+	return subs
+}
+
+func documentInt(doc map[string]any, field string) int64 {
+	n, _ := doc[field].(float64)
+	return int64(n)
+}
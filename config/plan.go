@@ -0,0 +1,416 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	checkly "github.com/checkly/checkly-go-sdk"
+)
+
+// Kind is the type of a resource of a State.
+type Kind string
+
+const (
+	KindSnippet             Kind = "snippet"
+	KindEnvironmentVariable Kind = "environment variable"
+	KindAlertChannel        Kind = "alert channel"
+	KindStatusPageService   Kind = "status page service"
+	KindStatusPage          Kind = "status page"
+	KindGroup               Kind = "group"
+	KindCheck               Kind = "check"
+	KindMaintenanceWindow   Kind = "maintenance window"
+	KindPrivateLocation     Kind = "private location"
+	KindDashboard           Kind = "dashboard"
+)
+
+// resource is a resource of a State in a form common to all kinds.
+type resource struct {
+	kind  Kind
+	key   string
+	name  string
+	value any
+	// doc is the JSON document of the resource, see encodeDocument.
+	doc map[string]any
+}
+
+type reference struct {
+	kind Kind
+	key  string
+}
+
+// resources returns the resources of s in dependency order, resources only
+// referencing resources of the kinds before theirs. Their documents
+// are only nil if s cannot be encoded, which Validate and NewPlan report.
+func (s State) resources() []resource {
+	var result []resource
+	add := func(kind Kind, key, name string, value any) {
+		doc, _ := toDocument(value)
+		withReferenceFields(kind, doc)
+		result = append(result, resource{kind: kind, key: key, name: name, value: value, doc: doc})
+	}
+	for _, r := range s.Snippets {
+		add(KindSnippet, r.Key, r.Snippet.Name, r)
+	}
+	for _, r := range s.EnvironmentVariables {
+		add(KindEnvironmentVariable, r.Key, r.Key, r)
+	}
+	for _, r := range s.AlertChannels {
+		add(KindAlertChannel, r.Key, alertChannelIdentity(r.AlertChannel), r)
+	}
+	for _, r := range s.PrivateLocations {
+		add(KindPrivateLocation, r.Key, r.PrivateLocation.Name, r)
+	}
+	for _, r := range s.StatusPageServices {
+		add(KindStatusPageService, r.Key, r.Service.Name, r)
+	}
+	for _, r := range s.StatusPages {
+		add(KindStatusPage, r.Key, r.StatusPage.Name, r)
+	}
+	for _, r := range s.Groups {
+		add(KindGroup, r.Key, r.Group.Name, r)
+	}
+	for _, r := range s.Checks {
+		name := ""
+		if r.Monitor != nil {
+			name = r.Monitor.GetName()
+		}
+		add(KindCheck, r.Key, name, r)
+	}
+	for _, r := range s.MaintenanceWindows {
+		add(KindMaintenanceWindow, r.Key, r.MaintenanceWindow.Name, r)
+	}
+	for _, r := range s.Dashboards {
+		add(KindDashboard, r.Key, r.Dashboard.Header, r)
+	}
+	return result
+}
+
+// withReferenceFields adds the reference fields of kind missing in doc, which
+// encodeDocument omits when they are empty, with the value meaning no
+// reference. Removing a reference is then a change like any other instead of
+// a field left to its value in the account.
+func withReferenceFields(kind Kind, doc map[string]any) {
+	var fields []string
+	switch kind {
+	case KindGroup:
+		fields = []string{"alertChannels", "setupSnippet", "tearDownSnippet"}
+	case KindCheck:
+		fields = []string{"group", "alertChannels", "setupSnippet", "tearDownSnippet", "incidentService"}
+	}
+	for _, f := range fields {
+		if doc == nil || doc[f] != nil {
+			continue
+		}
+		if f == "alertChannels" {
+			doc[f] = []any{}
+		} else {
+			doc[f] = ""
+		}
+	}
+}
+
+// references returns the resources r references.
+func (r resource) references() []reference {
+	var refs []reference
+	channels := func(keys []string) {
+		for _, key := range keys {
+			refs = append(refs, reference{KindAlertChannel, key})
+		}
+	}
+	switch v := r.value.(type) {
+	case StatusPage:
+		for _, card := range v.Cards {
+			for _, key := range card.Services {
+				refs = append(refs, reference{KindStatusPageService, key})
+			}
+		}
+	case Group:
+		channels(v.AlertChannels)
+		refs = append(refs,
+			reference{KindSnippet, v.SetupSnippet},
+			reference{KindSnippet, v.TearDownSnippet},
+		)
+	case Check:
+		channels(v.AlertChannels)
+		refs = append(refs,
+			reference{KindGroup, v.Group},
+			reference{KindSnippet, v.SetupSnippet},
+			reference{KindSnippet, v.TearDownSnippet},
+			reference{KindStatusPageService, v.IncidentService},
+		)
+	}
+	return refs
+}
+
+// Action is what applying a Change does to a resource.
+type Action string
+
+const (
+	Create Action = "create"
+	Update Action = "update"
+	Delete Action = "delete"
+)
+
+// Change is a change to a single resource of the account.
+type Change struct {
+	Action Action
+	Kind   Kind
+	Key    string
+	// Name is a human-readable name of the resource.
+	Name string
+	// ID is the ID of the existing resource changed by updates and deletes.
+	ID string
+	// Fields lists the differences an update resolves. Adopting a resource
+	// without the tag holding its key is reported as a change of "key".
+	Fields []checkly.FieldChange
+
+	// desired is the resource created or updated, live the existing one.
+	desired *resource
+	live    *resource
+}
+
+// Plan lists the changes making an account match a desired State, in the
+// order they are applied: creates and updates in dependency order, then
+// deletes in reverse dependency order.
+type Plan struct {
+	Changes []Change
+
+	// ids maps the keys of the existing resources to their IDs.
+	ids map[Kind]map[string]string
+}
+
+// Option configures NewPlan, DetectDrift, Import, Export and WriteDir. Each
+// of them ignores the options which don't apply to it.
+type Option func(*options)
+
+type options struct {
+	prune  bool
+	format Format
+	// defaults compares the fields left unset in the desired State with the
+	// values the API gives them, see DetectDrift.
+	defaults bool
+}
+
+// WithPrune deletes all resources which are not part of the desired State.
+// By default only checks, groups and maintenance windows tagged with a key
+// which is no longer part of the State are deleted.
+func WithPrune() Option {
+	return func(o *options) {
+		o.prune = true
+	}
+}
+
+// NewPlan fetches the configuration of the account and returns the changes
+// to make it match desired. Fields which are null in desired, like nil
+// pointers, are left to their values in the account. References are not:
+// an empty Group, AlertChannels or snippet key removes the reference.
+func NewPlan(ctx context.Context, client checkly.Client, desired State, opts ...Option) (*Plan, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if err := desired.Validate(); err != nil {
+		return nil, err
+	}
+	l, err := fetch(ctx, client, desired)
+	if err != nil {
+		return nil, err
+	}
+	return newPlan(desired.normalized(), l, o), nil
+}
+
+func newPlan(desired State, l *live, o options) *Plan {
+	p := &Plan{ids: l.ids}
+	liveResources := map[Kind]map[string]*resource{}
+	for _, r := range l.state.resources() {
+		r := r
+		if liveResources[r.kind] == nil {
+			liveResources[r.kind] = map[string]*resource{}
+		}
+		liveResources[r.kind][r.key] = &r
+	}
+	matched := map[Kind]map[string]bool{}
+	for _, r := range desired.resources() {
+		r := r
+		if matched[r.kind] == nil {
+			matched[r.kind] = map[string]bool{}
+		}
+		current, ok := liveResources[r.kind][r.key]
+		if ok && r.doc["checkType"] != current.doc["checkType"] {
+			// The type of a check cannot change, it is replaced instead.
+			ok = false
+		}
+		if !ok {
+			p.Changes = append(p.Changes, Change{Action: Create, Kind: r.kind, Key: r.key, Name: r.name, desired: &r})
+			continue
+		}
+		matched[r.kind][r.key] = true
+		desiredDoc, liveDoc := r.doc, current.doc
+		if o.defaults {
+			desiredDoc, liveDoc = r.withDefaults(), current.withDefaults()
+		}
+		fields := diffDocuments(r.kind, desiredDoc, liveDoc)
+		if !l.managed[r.kind][r.key] && taggedKind(r.kind) {
+			fields = append([]checkly.FieldChange{{Path: "key", New: r.key}}, fields...)
+		}
+		if len(fields) > 0 {
+			p.Changes = append(p.Changes, Change{
+				Action:  Update,
+				Kind:    r.kind,
+				Key:     r.key,
+				Name:    r.name,
+				ID:      l.ids[r.kind][r.key],
+				Fields:  fields,
+				desired: &r,
+				live:    current,
+			})
+		}
+	}
+	liveList := l.state.resources()
+	for i := len(liveList) - 1; i >= 0; i-- {
+		r := liveList[i]
+		if matched[r.kind][r.key] || !o.prune && !l.managed[r.kind][r.key] {
+			continue
+		}
+		p.Changes = append(p.Changes, Change{
+			Action: Delete,
+			Kind:   r.kind,
+			Key:    r.key,
+			Name:   r.name,
+			ID:     l.ids[r.kind][r.key],
+			live:   &r,
+		})
+	}
+	return p
+}
+
+// taggedKind reports whether resources of kind store their key in a tag.
+func taggedKind(kind Kind) bool {
+	return kind == KindCheck || kind == KindGroup || kind == KindMaintenanceWindow
+}
+
+// Empty reports whether the account already matches the desired State.
+func (p *Plan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+// String describes the changes of the plan, one per line starting with "+",
+// "~" or "-" for creates, updates and deletes. The fields changed by an update
+// follow it, one per line as "path: old => new" with JSON values.
+func (p *Plan) String() string {
+	if p.Empty() {
+		return "No changes.\n"
+	}
+	var b strings.Builder
+	for _, c := range p.Changes {
+		symbol := map[Action]string{Create: "+", Update: "~", Delete: "-"}[c.Action]
+		fmt.Fprintf(&b, "%s %s %s %q", symbol, c.Action, c.Kind, c.Key)
+		if c.Name != "" && c.Name != c.Key {
+			fmt.Fprintf(&b, " (%s)", c.Name)
+		}
+		b.WriteString("\n")
+		for _, f := range c.Fields {
+			fmt.Fprintf(&b, "    %s: %s => %s\n", f.Path, formatValue(f.Old), formatValue(f.New))
+		}
+	}
+	return b.String()
+}
+
+func formatValue(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+// diffDocuments returns the differences between the desired and live
+// documents of a resource. Only the fields set in desired are compared.
+func diffDocuments(kind Kind, desired, live map[string]any) []checkly.FieldChange {
+	if kind == KindEnvironmentVariable && live["secret"] == true {
+		// The API doesn't return the values of secret variables.
+		desired = withoutField(desired, "value")
+	}
+	var changes []checkly.FieldChange
+	diffValues("", desired, live, &changes)
+	return changes
+}
+
+func withoutField(doc map[string]any, field string) map[string]any {
+	result := make(map[string]any, len(doc))
+	for k, v := range doc {
+		if k != field {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+func diffValues(path string, desired, live any, changes *[]checkly.FieldChange) {
+	switch d := desired.(type) {
+	case nil:
+		// Unset in the desired State.
+		return
+	case map[string]any:
+		l, ok := live.(map[string]any)
+		if !ok {
+			if !isEmpty(d) || !isEmpty(live) {
+				*changes = append(*changes, checkly.FieldChange{Path: path, Old: live, New: desired})
+			}
+			return
+		}
+		keys := make([]string, 0, len(d))
+		for k := range d {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			field := k
+			if path != "" {
+				field = path + "." + k
+			}
+			diffValues(field, d[k], l[k], changes)
+		}
+	case []any:
+		l, ok := live.([]any)
+		if !ok || len(l) != len(d) {
+			if !isEmpty(d) || !isEmpty(live) {
+				*changes = append(*changes, checkly.FieldChange{Path: path, Old: live, New: desired})
+			}
+			return
+		}
+		// Arrays are reported as a whole, which is more readable than
+		// their elements when they were reordered.
+		var elementChanges []checkly.FieldChange
+		for i := range d {
+			diffValues(fmt.Sprintf("%s[%d]", path, i), d[i], l[i], &elementChanges)
+		}
+		if len(elementChanges) > 0 {
+			*changes = append(*changes, checkly.FieldChange{Path: path, Old: live, New: desired})
+		}
+	default:
+		if !reflect.DeepEqual(desired, live) && !(isEmpty(desired) && isEmpty(live)) {
+			*changes = append(*changes, checkly.FieldChange{Path: path, Old: live, New: desired})
+		}
+	}
+}
+
+// isEmpty reports whether v is a JSON value the API treats like a missing
+// field.
+func isEmpty(v any) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []any:
+		return len(v) == 0
+	case map[string]any:
+		return len(v) == 0
+	}
+	return false
+}
@@ -0,0 +1,575 @@
+// Package config manages the configuration of a Checkly account
+// declaratively. The desired configuration is described as a State, built
+// from Go values or loaded from a file, and compared with what exists in the
+// account to compute a Plan of creates, updates and deletes:
+//
+//	plan, err := config.NewPlan(ctx, client, desired)
+//	if err != nil {
+//		return err
+//	}
+//	fmt.Print(plan)
+//	if err := plan.Apply(ctx, client); err != nil {
+//		return err
+//	}
+//
+// Every resource of a State has a logical key, which the other resources use
+// to reference it instead of its ID. Checks, groups and maintenance windows
+// store their key in a tag (see KeyTag), so that they are recognised even
+// after they have been renamed. Existing resources without that tag are
+// adopted by name the first time a plan is applied. The other resource types
+// have no tags and are matched by a natural identity instead: snippets and
+// status page services by name, environment variables by key, status pages by
+// URL, dashboards by custom URL, private locations by slug name and alert
+// channels by their type and address.
+//
+// Export writes the configuration of an account to a directory of YAML or
+// JSON files, one per resource, which Import recreates in another account.
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	checkly "github.com/checkly/checkly-go-sdk"
+)
+
+// KeyTagPrefix prefixes the tag holding the key of a check, group or
+// maintenance window, see KeyTag.
+const KeyTagPrefix = "checkly-key:"
+
+// KeyTag returns the tag storing key on a check, group or maintenance window.
+func KeyTag(key string) string {
+	return KeyTagPrefix + key
+}
+
+// keyFromTags returns the key stored in tags, if any.
+func keyFromTags(tags []string) (string, bool) {
+	for _, tag := range tags {
+		if key, ok := strings.CutPrefix(tag, KeyTagPrefix); ok {
+			return key, true
+		}
+	}
+	return "", false
+}
+
+// withoutKeyTag returns tags without the tag holding the key.
+func withoutKeyTag(tags []string) []string {
+	var result []string
+	for _, tag := range tags {
+		if !strings.HasPrefix(tag, KeyTagPrefix) {
+			result = append(result, tag)
+		}
+	}
+	return result
+}
+
+// State is the configuration of an account. Resources reference each other
+// by key, the IDs and reference fields of the embedded SDK values are
+// ignored.
+type State struct {
+	AlertChannels        []AlertChannel                `json:"alertChannels,omitempty"`
+	Snippets             []Snippet                     `json:"snippets,omitempty"`
+	EnvironmentVariables []checkly.EnvironmentVariable `json:"environmentVariables,omitempty"`
+	StatusPageServices   []StatusPageService           `json:"statusPageServices,omitempty"`
+	StatusPages          []StatusPage                  `json:"statusPages,omitempty"`
+	Groups               []Group                       `json:"groups,omitempty"`
+	Checks               []Check                       `json:"checks,omitempty"`
+	MaintenanceWindows   []MaintenanceWindow           `json:"maintenanceWindows,omitempty"`
+	PrivateLocations     []PrivateLocation             `json:"privateLocations,omitempty"`
+	Dashboards           []Dashboard                   `json:"dashboards,omitempty"`
+}
+
+// AlertChannel is an alert channel of a State. Its key defaults to its
+// natural identity, the type and address of the channel, for example
+// "email:ops@example.com".
+type AlertChannel struct {
+	Key          string
+	AlertChannel checkly.AlertChannel
+}
+
+// Snippet is a snippet of a State. Its key defaults to its name.
+type Snippet struct {
+	Key     string
+	Snippet checkly.Snippet
+}
+
+// StatusPageService is a status page service of a State. Its key defaults to
+// its name.
+type StatusPageService struct {
+	Key     string
+	Service checkly.StatusPageService
+}
+
+// StatusPage is a status page of a State. Its key defaults to its URL. The
+// cards of StatusPage are replaced by Cards, which reference services by key.
+type StatusPage struct {
+	Key        string
+	StatusPage checkly.StatusPage
+	Cards      []StatusPageCard
+}
+
+// StatusPageCard is a card of a status page, listing the keys of its
+// services.
+type StatusPageCard struct {
+	Name     string   `json:"name"`
+	Services []string `json:"services"`
+}
+
+// Group is a check group of a State. AlertChannels lists the keys of the
+// alert channels the group is subscribed to, SetupSnippet and
+// TearDownSnippet the keys of its snippets.
+type Group struct {
+	Key             string
+	AlertChannels   []string
+	SetupSnippet    string
+	TearDownSnippet string
+	Group           checkly.GroupV2
+}
+
+// Check is a check or monitor of a State. Group is the key of its group,
+// AlertChannels lists the keys of the alert channels it is subscribed to and
+// IncidentService is the key of the status page service of its
+// TriggerIncident. SetupSnippet and TearDownSnippet are the keys of the
+// snippets of API checks.
+type Check struct {
+	Key             string
+	Group           string
+	AlertChannels   []string
+	SetupSnippet    string
+	TearDownSnippet string
+	IncidentService string
+	Monitor         checkly.Monitor
+}
+
+// MaintenanceWindow is a maintenance window of a State.
+type MaintenanceWindow struct {
+	Key               string
+	MaintenanceWindow checkly.MaintenanceWindow
+}
+
+// PrivateLocation is a private location of a State. Its key defaults to its
+// slug name, which checks and groups use to reference it.
+type PrivateLocation struct {
+	Key             string
+	PrivateLocation checkly.PrivateLocation
+}
+
+// Dashboard is a dashboard of a State. Its key defaults to its custom URL.
+type Dashboard struct {
+	Key       string
+	Dashboard checkly.Dashboard
+}
+
+// LoadState reads a State from the file at path, which holds JSON unless its
+// extension is .yaml or .yml. See LoadDir for directories written by Export.
+func LoadState(path string) (*State, error) {
+	data, err := readJSON(path)
+	if err != nil {
+		return nil, err
+	}
+	var s State
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", path, err)
+	}
+	return &s, nil
+}
+
+// Validate reports duplicate keys, resources without a key where one is
+// required and references to keys which are not part of s.
+func (s *State) Validate() error {
+	n := s.normalized()
+	keys := map[Kind]map[string]bool{}
+	var errs []error
+	add := func(kind Kind, key string) {
+		if key == "" {
+			errs = append(errs, fmt.Errorf("%s without a key", kind))
+			return
+		}
+		if keys[kind] == nil {
+			keys[kind] = map[string]bool{}
+		}
+		if keys[kind][key] {
+			errs = append(errs, fmt.Errorf("duplicate %s %q", kind, key))
+		}
+		keys[kind][key] = true
+	}
+	for _, r := range n.resources() {
+		add(r.kind, r.key)
+		if r.kind == KindCheck && r.doc["checkType"] == nil {
+			errs = append(errs, fmt.Errorf("check %q without a monitor", r.key))
+		}
+	}
+	for _, r := range n.resources() {
+		for _, ref := range r.references() {
+			if ref.key != "" && !keys[ref.kind][ref.key] {
+				errs = append(errs, fmt.Errorf("%s %q references unknown %s %q", r.kind, r.key, ref.kind, ref.key))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// normalized returns a copy of s with the default keys filled in.
+func (s State) normalized() State {
+	s.AlertChannels = append([]AlertChannel(nil), s.AlertChannels...)
+	for i := range s.AlertChannels {
+		if s.AlertChannels[i].Key == "" {
+			s.AlertChannels[i].Key = alertChannelIdentity(s.AlertChannels[i].AlertChannel)
+		}
+	}
+	s.Snippets = append([]Snippet(nil), s.Snippets...)
+	for i := range s.Snippets {
+		if s.Snippets[i].Key == "" {
+			s.Snippets[i].Key = s.Snippets[i].Snippet.Name
+		}
+	}
+	s.StatusPageServices = append([]StatusPageService(nil), s.StatusPageServices...)
+	for i := range s.StatusPageServices {
+		if s.StatusPageServices[i].Key == "" {
+			s.StatusPageServices[i].Key = s.StatusPageServices[i].Service.Name
+		}
+	}
+	s.StatusPages = append([]StatusPage(nil), s.StatusPages...)
+	for i := range s.StatusPages {
+		if s.StatusPages[i].Key == "" {
+			s.StatusPages[i].Key = s.StatusPages[i].StatusPage.URL
+		}
+	}
+	s.PrivateLocations = append([]PrivateLocation(nil), s.PrivateLocations...)
+	for i := range s.PrivateLocations {
+		if s.PrivateLocations[i].Key == "" {
+			s.PrivateLocations[i].Key = s.PrivateLocations[i].PrivateLocation.SlugName
+		}
+	}
+	s.Dashboards = append([]Dashboard(nil), s.Dashboards...)
+	for i := range s.Dashboards {
+		if s.Dashboards[i].Key == "" {
+			s.Dashboards[i].Key = s.Dashboards[i].Dashboard.CustomUrl
+		}
+	}
+	return s
+}
+
+// alertChannelIdentity identifies an alert channel by its type and address.
+func alertChannelIdentity(ac checkly.AlertChannel) string {
+	var address string
+	switch {
+	case ac.Email != nil:
+		address = ac.Email.Address
+	case ac.Slack != nil:
+		address = ac.Slack.WebhookURL + "#" + ac.Slack.Channel
+	case ac.SlackApp != nil:
+		address = strings.Join(ac.SlackApp.SlackChannels, ",")
+	case ac.SMS != nil:
+		address = ac.SMS.Number
+	case ac.CALL != nil:
+		address = ac.CALL.Number
+	case ac.Opsgenie != nil:
+		address = ac.Opsgenie.Name
+	case ac.Webhook != nil:
+		address = ac.Webhook.Name
+	case ac.Pagerduty != nil:
+		address = ac.Pagerduty.ServiceKey
+	}
+	return strings.ToLower(ac.Type) + ":" + address
+}
+
+// The resources of a State are encoded as flat JSON documents: the fields of
+// the SDK value, without those set by the server and the reference fields,
+// next to the key and the references of the resource.
+
+// serverFields are set by the server or replaced by references to keys.
+var serverFields = []string{
+	"id", "createdAt", "updatedAt", "created_at", "updated_at",
+	"groupId", "alertChannelSubscriptions", "setupSnippetId", "tearDownSnippetId",
+}
+
+// encodeDocument encodes value and the fields of refs as a single JSON
+// object, without the serverFields and the fields listed in strip.
+func encodeDocument(value any, refs any, strip ...string) ([]byte, error) {
+	doc, err := toDocument(value)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range serverFields {
+		delete(doc, f)
+	}
+	for _, f := range strip {
+		delete(doc, f)
+	}
+	if tags, ok := doc["tags"].([]any); ok {
+		var kept []any
+		for _, tag := range tags {
+			if s, ok := tag.(string); !ok || !strings.HasPrefix(s, KeyTagPrefix) {
+				kept = append(kept, tag)
+			}
+		}
+		doc["tags"] = kept
+	}
+	refDoc, err := toDocument(refs)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range refDoc {
+		doc[k] = v
+	}
+	return json.Marshal(doc)
+}
+
+// decodeDocument decodes a JSON object encoded by encodeDocument into value
+// and refs.
+func decodeDocument(data []byte, value any, refs any) error {
+	if err := json.Unmarshal(data, refs); err != nil {
+		return err
+	}
+	return json.Unmarshal(data, value)
+}
+
+func toDocument(v any) (map[string]any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	doc := map[string]any{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+type alertChannelRefs struct {
+	Key    string          `json:"key"`
+	Config json.RawMessage `json:"config,omitempty"`
+}
+
+// MarshalJSON encodes the alert channel with its configuration in a "config"
+// field, as the API does.
+func (a AlertChannel) MarshalJSON() ([]byte, error) {
+	config, err := json.Marshal(a.AlertChannel.GetConfig())
+	if err != nil {
+		return nil, err
+	}
+	return encodeDocument(a.AlertChannel, alertChannelRefs{Key: a.Key, Config: config})
+}
+
+// UnmarshalJSON decodes an alert channel encoded by MarshalJSON.
+func (a *AlertChannel) UnmarshalJSON(data []byte) error {
+	var refs alertChannelRefs
+	if err := decodeDocument(data, &a.AlertChannel, &refs); err != nil {
+		return err
+	}
+	a.Key = refs.Key
+	if len(refs.Config) > 0 {
+		config, err := checkly.AlertChannelConfigFromJSON(a.AlertChannel.Type, refs.Config)
+		if err != nil {
+			return err
+		}
+		a.AlertChannel.SetConfig(config)
+	}
+	return nil
+}
+
+type keyRefs struct {
+	Key string `json:"key"`
+}
+
+// MarshalJSON encodes the snippet and its key as a single object.
+func (s Snippet) MarshalJSON() ([]byte, error) {
+	return encodeDocument(s.Snippet, keyRefs{Key: s.Key})
+}
+
+// UnmarshalJSON decodes a snippet encoded by MarshalJSON.
+func (s *Snippet) UnmarshalJSON(data []byte) error {
+	var refs keyRefs
+	err := decodeDocument(data, &s.Snippet, &refs)
+	s.Key = refs.Key
+	return err
+}
+
+// MarshalJSON encodes the service and its key as a single object.
+func (s StatusPageService) MarshalJSON() ([]byte, error) {
+	return encodeDocument(s.Service, keyRefs{Key: s.Key})
+}
+
+// UnmarshalJSON decodes a service encoded by MarshalJSON.
+func (s *StatusPageService) UnmarshalJSON(data []byte) error {
+	var refs keyRefs
+	err := decodeDocument(data, &s.Service, &refs)
+	s.Key = refs.Key
+	return err
+}
+
+type statusPageRefs struct {
+	Key   string           `json:"key"`
+	Cards []StatusPageCard `json:"cards"`
+}
+
+// MarshalJSON encodes the status page with the keys of its services.
+func (p StatusPage) MarshalJSON() ([]byte, error) {
+	page := p.StatusPage
+	page.Cards = nil
+	return encodeDocument(page, statusPageRefs{Key: p.Key, Cards: p.Cards})
+}
+
+// UnmarshalJSON decodes a status page encoded by MarshalJSON.
+func (p *StatusPage) UnmarshalJSON(data []byte) error {
+	var refs statusPageRefs
+	if err := json.Unmarshal(data, &refs); err != nil {
+		return err
+	}
+	var page struct {
+		checkly.StatusPage
+		// Cards shadows the cards of the status page, which are decoded
+		// into refs.
+		Cards json.RawMessage `json:"cards"`
+	}
+	if err := json.Unmarshal(data, &page); err != nil {
+		return err
+	}
+	p.Key = refs.Key
+	p.StatusPage = page.StatusPage
+	p.Cards = refs.Cards
+	return nil
+}
+
+type groupRefs struct {
+	Key             string   `json:"key"`
+	AlertChannels   []string `json:"alertChannels,omitempty"`
+	SetupSnippet    string   `json:"setupSnippet,omitempty"`
+	TearDownSnippet string   `json:"tearDownSnippet,omitempty"`
+}
+
+// MarshalJSON encodes the group with the keys it references.
+func (g Group) MarshalJSON() ([]byte, error) {
+	return encodeDocument(g.Group, groupRefs{
+		Key:             g.Key,
+		AlertChannels:   g.AlertChannels,
+		SetupSnippet:    g.SetupSnippet,
+		TearDownSnippet: g.TearDownSnippet,
+	})
+}
+
+// UnmarshalJSON decodes a group encoded by MarshalJSON.
+func (g *Group) UnmarshalJSON(data []byte) error {
+	var refs groupRefs
+	if err := decodeDocument(data, &g.Group, &refs); err != nil {
+		return err
+	}
+	g.Key = refs.Key
+	g.AlertChannels = refs.AlertChannels
+	g.SetupSnippet = refs.SetupSnippet
+	g.TearDownSnippet = refs.TearDownSnippet
+	return nil
+}
+
+type checkRefs struct {
+	Key             string   `json:"key"`
+	CheckType       string   `json:"checkType,omitempty"`
+	Group           string   `json:"group,omitempty"`
+	AlertChannels   []string `json:"alertChannels,omitempty"`
+	SetupSnippet    string   `json:"setupSnippet,omitempty"`
+	TearDownSnippet string   `json:"tearDownSnippet,omitempty"`
+	IncidentService string   `json:"incidentService,omitempty"`
+}
+
+// MarshalJSON encodes the monitor of the check, including its checkType,
+// with the keys it references.
+func (c Check) MarshalJSON() ([]byte, error) {
+	refs := checkRefs{
+		Key:             c.Key,
+		Group:           c.Group,
+		AlertChannels:   c.AlertChannels,
+		SetupSnippet:    c.SetupSnippet,
+		TearDownSnippet: c.TearDownSnippet,
+		IncidentService: c.IncidentService,
+	}
+	if c.Monitor == nil {
+		return json.Marshal(refs)
+	}
+	refs.CheckType = c.Monitor.CheckType()
+	data, err := encodeDocument(c.Monitor, refs)
+	if err != nil {
+		return nil, err
+	}
+	// The service of the incident trigger is referenced by IncidentService,
+	// the ping token of heartbeats is assigned by the server.
+	doc := map[string]any{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if trigger, ok := doc["triggerIncident"].(map[string]any); ok {
+		delete(trigger, "serviceId")
+	}
+	if heartbeat, ok := doc["heartbeat"].(map[string]any); ok {
+		delete(heartbeat, "pingToken")
+	}
+	return json.Marshal(doc)
+}
+
+// UnmarshalJSON decodes a check encoded by MarshalJSON.
+func (c *Check) UnmarshalJSON(data []byte) error {
+	var refs checkRefs
+	if err := json.Unmarshal(data, &refs); err != nil {
+		return err
+	}
+	*c = Check{
+		Key:             refs.Key,
+		Group:           refs.Group,
+		AlertChannels:   refs.AlertChannels,
+		SetupSnippet:    refs.SetupSnippet,
+		TearDownSnippet: refs.TearDownSnippet,
+		IncidentService: refs.IncidentService,
+	}
+	if refs.CheckType == "" {
+		return fmt.Errorf("check %q has no checkType", refs.Key)
+	}
+	monitor, err := checkly.DecodeMonitor(data)
+	if err != nil {
+		return err
+	}
+	c.Monitor = monitor
+	return nil
+}
+
+// MarshalJSON encodes the maintenance window and its key as a single object.
+func (w MaintenanceWindow) MarshalJSON() ([]byte, error) {
+	return encodeDocument(w.MaintenanceWindow, keyRefs{Key: w.Key})
+}
+
+// UnmarshalJSON decodes a maintenance window encoded by MarshalJSON.
+func (w *MaintenanceWindow) UnmarshalJSON(data []byte) error {
+	var refs keyRefs
+	err := decodeDocument(data, &w.MaintenanceWindow, &refs)
+	w.Key = refs.Key
+	return err
+}
+
+// MarshalJSON encodes the private location and its key as a single object,
+// without its agent keys and status.
+func (l PrivateLocation) MarshalJSON() ([]byte, error) {
+	return encodeDocument(l.PrivateLocation, keyRefs{Key: l.Key}, "keys", "lastSeen", "agentCount")
+}
+
+// UnmarshalJSON decodes a private location encoded by MarshalJSON.
+func (l *PrivateLocation) UnmarshalJSON(data []byte) error {
+	var refs keyRefs
+	err := decodeDocument(data, &l.PrivateLocation, &refs)
+	l.Key = refs.Key
+	return err
+}
+
+// MarshalJSON encodes the dashboard and its key as a single object, without
+// its API keys.
+func (d Dashboard) MarshalJSON() ([]byte, error) {
+	return encodeDocument(d.Dashboard, keyRefs{Key: d.Key}, "dashboardId", "keys")
+}
+
+// UnmarshalJSON decodes a dashboard encoded by MarshalJSON.
+func (d *Dashboard) UnmarshalJSON(data []byte) error {
+	var refs keyRefs
+	err := decodeDocument(data, &d.Dashboard, &refs)
+	d.Key = refs.Key
+	return err
+}
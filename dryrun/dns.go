@@ -0,0 +1,181 @@
+package dryrun
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	checkly "github.com/checkly/checkly-go-sdk"
+	"github.com/checkly/checkly-go-sdk/assert"
+)
+
+// resolvConf is the configuration of the system resolver, which provides the
+// default name server.
+const resolvConf = "/etc/resolv.conf"
+
+// RunDNSMonitor queries the name server of a DNS monitor for the records of
+// its query, and evaluates its assertions on the response, see
+// assert.DNSSample.
+//
+// The query is sent over the protocol of the monitor, UDP by default, to its
+// name server and port, by default the first name server of the system
+// resolver and port 53. Queries for PTR records of IP addresses are sent for
+// their reverse lookup name. The check fails if an assertion fails, or the
+// response time exceeds MaxResponseTime, and is degraded if it exceeds
+// DegradedResponseTime.
+//
+// Check results have no field for the details of DNS monitors, which are in
+// the ApiCheckResult of the result: the "request", the "response" in the
+// format the JSON_RESPONSE assertions select from, with the "timingPhases",
+// and the "assertions".
+func RunDNSMonitor(ctx context.Context, monitor checkly.DNSMonitor, opts ...Option) (*checkly.CheckResult, error) {
+	o := newOptions(opts)
+	request := monitor.Request
+	if request.Query == "" {
+		return nil, fmt.Errorf("dryrun: the query of DNS monitors is required")
+	}
+	qtype, err := dnsType(request.RecordType)
+	if err != nil {
+		return nil, err
+	}
+	protocol := strings.ToUpper(request.Protocol)
+	switch protocol {
+	case "":
+		protocol = "UDP"
+	case "UDP", "TCP":
+	default:
+		return nil, fmt.Errorf("dryrun: unsupported protocol %q", request.Protocol)
+	}
+	server := request.NameServer
+	if server == "" {
+		if server, err = systemNameServer(); err != nil {
+			return nil, err
+		}
+	}
+	port := request.Port
+	if port == 0 {
+		port = 53
+	}
+	id := uint16(rand.Intn(1 << 16))
+	query, err := newDNSQuery(id, fqdn(request.Query, qtype), qtype)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, o.timeout)
+	defer cancel()
+
+	start := time.Now()
+	resp, err := exchangeDNS(ctx, protocol, net.JoinHostPort(server, strconv.Itoa(port)), query, id)
+	stop := time.Now()
+
+	result := newResult(monitor.ID, monitor.Name, start, stop)
+	dnsResult := checkly.ApiCheckResult{"request": map[string]any{
+		"query":      request.Query,
+		"recordType": strings.ToUpper(request.RecordType),
+		"nameServer": server,
+		"port":       port,
+		"protocol":   protocol,
+	}}
+	result.ApiCheckResult = &dnsResult
+	if err != nil {
+		result.HasErrors = true
+		result.HasFailures = true
+		dnsResult["requestError"] = err.Error()
+		return result, nil
+	}
+	resp.ResponseTime = stop.Sub(start)
+	assertions := assert.Evaluate(request.Assertions, assert.DNSSample(resp))
+	grade(result, assertions, false, monitor.DegradedResponseTime, monitor.MaxResponseTime)
+	response, err := jsonObject(resp)
+	if err != nil {
+		return nil, err
+	}
+	response["timingPhases"] = map[string]any{"total": milliseconds(resp.ResponseTime)}
+	dnsResult["response"] = response
+	dnsResult["assertions"] = assertionResults(assertions)
+	return result, nil
+}
+
+// exchangeDNS sends query to the name server at addr, and returns its
+// response.
+func exchangeDNS(ctx context.Context, protocol, addr string, query []byte, id uint16) (assert.DNSResponse, error) {
+	conn, err := new(net.Dialer).DialContext(ctx, strings.ToLower(protocol), addr)
+	if err != nil {
+		return assert.DNSResponse{}, err
+	}
+	defer conn.Close()
+	deadline, _ := ctx.Deadline()
+	conn.SetDeadline(deadline)
+
+	if protocol == "TCP" {
+		// Messages over TCP are prefixed with their length.
+		msg := binary.BigEndian.AppendUint16(nil, uint16(len(query)))
+		if _, err := conn.Write(append(msg, query...)); err != nil {
+			return assert.DNSResponse{}, err
+		}
+		var length [2]byte
+		if _, err := io.ReadFull(conn, length[:]); err != nil {
+			return assert.DNSResponse{}, err
+		}
+		msg = make([]byte, binary.BigEndian.Uint16(length[:]))
+		if _, err := io.ReadFull(conn, msg); err != nil {
+			return assert.DNSResponse{}, err
+		}
+		return parseDNSResponse(msg, id)
+	}
+
+	if _, err := conn.Write(query); err != nil {
+		return assert.DNSResponse{}, err
+	}
+	buf := make([]byte, 65535)
+	for {
+		n, err := conn.Read(buf)
+		if err != nil {
+			return assert.DNSResponse{}, err
+		}
+		// Datagrams which aren't the response to the query, like late
+		// responses to earlier queries, are ignored.
+		if n >= 2 && binary.BigEndian.Uint16(buf) == id {
+			return parseDNSResponse(buf[:n], id)
+		}
+	}
+}
+
+// systemNameServer returns the first name server of the system resolver.
+func systemNameServer() (string, error) {
+	f, err := os.Open(resolvConf)
+	if err != nil {
+		return "", fmt.Errorf("dryrun: no name server set, and reading the system resolver configuration: %w", err)
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "nameserver" {
+			return fields[1], nil
+		}
+	}
+	return "", fmt.Errorf("dryrun: no name server set, and none in %s", resolvConf)
+}
+
+// jsonObject returns v as decoded from JSON.
+func jsonObject(v any) (map[string]any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var object map[string]any
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, err
+	}
+	return object, nil
+}
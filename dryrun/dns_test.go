@@ -0,0 +1,255 @@
+package dryrun_test
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	checkly "github.com/checkly/checkly-go-sdk"
+	"github.com/checkly/checkly-go-sdk/assert"
+	"github.com/checkly/checkly-go-sdk/dryrun"
+)
+
+// stubRecord is a record served by the stub DNS server, with its data in
+// wire format.
+type stubRecord struct {
+	rtype uint16
+	ttl   uint32
+	data  []byte
+}
+
+// stubZone are the records of the stub DNS server by name and type.
+var stubZone = map[string][]stubRecord{
+	"example.com./1": {
+		{1, 300, []byte{93, 184, 216, 34}},
+		{1, 300, []byte{93, 184, 216, 35}},
+	},
+	"example.com./28": {
+		{28, 300, net.ParseIP("2606:2800:220:1:248:1893:25c8:1946")},
+	},
+	// The exchange is compressed, pointing to the name of the question.
+	"example.com./15": {
+		{15, 3600, append([]byte{0, 10, 4, 'm', 'a', 'i', 'l'}, 0xc0, 12)},
+	},
+	"example.com./16": {
+		{16, 60, append(append([]byte{11}, "v=spf1 -all"...), append([]byte{8}, `say "hi"`...)...)},
+	},
+	"www.example.com./5": {
+		{5, 60, wireName("example.com.")},
+	},
+	"34.216.184.93.in-addr.arpa./12": {
+		{12, 60, wireName("example.com.")},
+	},
+}
+
+func wireName(name string) []byte {
+	var b []byte
+	for _, label := range strings.Split(strings.TrimSuffix(name, "."), ".") {
+		b = append(b, byte(len(label)))
+		b = append(b, label...)
+	}
+	return append(b, 0)
+}
+
+// stubAnswer returns the response to query.
+func stubAnswer(query []byte) []byte {
+	var labels []string
+	off := 12
+	for query[off] != 0 {
+		n := int(query[off])
+		labels = append(labels, string(query[off+1:off+1+n]))
+		off += 1 + n
+	}
+	question := query[12 : off+5]
+	qtype := binary.BigEndian.Uint16(query[off+1:])
+	key := strings.Join(labels, ".") + "./" + strconv.Itoa(int(qtype))
+	records, ok := stubZone[key]
+
+	resp := append([]byte{}, query[:2]...)
+	flags := uint16(0x8180)
+	if !ok {
+		flags |= 3 // NXDOMAIN
+	}
+	resp = binary.BigEndian.AppendUint16(resp, flags)
+	resp = binary.BigEndian.AppendUint16(resp, 1)
+	resp = binary.BigEndian.AppendUint16(resp, uint16(len(records)))
+	resp = append(resp, 0, 0, 0, 0)
+	resp = append(resp, question...)
+	for _, r := range records {
+		resp = append(resp, 0xc0, 12)
+		resp = binary.BigEndian.AppendUint16(resp, r.rtype)
+		resp = binary.BigEndian.AppendUint16(resp, 1)
+		resp = binary.BigEndian.AppendUint32(resp, r.ttl)
+		resp = binary.BigEndian.AppendUint16(resp, uint16(len(r.data)))
+		resp = append(resp, r.data...)
+	}
+	return resp
+}
+
+// stubDNSServer serves stubZone over UDP and TCP on the same loopback port,
+// and returns the port.
+func stubDNSServer(t *testing.T) int {
+	t.Helper()
+	udp, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { udp.Close() })
+	port := udp.LocalAddr().(*net.UDPAddr).Port
+	tcp, err := net.Listen("tcp4", udp.LocalAddr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { tcp.Close() })
+
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := udp.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			// A stray datagram with another ID precedes the response.
+			stray := stubAnswer(buf[:n])
+			stray[0]++
+			udp.WriteTo(stray, addr)
+			udp.WriteTo(stubAnswer(buf[:n]), addr)
+		}
+	}()
+	go func() {
+		for {
+			conn, err := tcp.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				var length [2]byte
+				if _, err := io.ReadFull(conn, length[:]); err != nil {
+					return
+				}
+				query := make([]byte, binary.BigEndian.Uint16(length[:]))
+				if _, err := io.ReadFull(conn, query); err != nil {
+					return
+				}
+				resp := stubAnswer(query)
+				conn.Write(append(binary.BigEndian.AppendUint16(nil, uint16(len(resp))), resp...))
+			}()
+		}
+	}()
+	return port
+}
+
+func dnsMonitor(port int, protocol, recordType, query string, assertions ...checkly.Assertion) checkly.DNSMonitor {
+	return checkly.DNSMonitor{
+		Name: "DNS monitor",
+		Request: checkly.DNSRequest{
+			RecordType: recordType,
+			Query:      query,
+			NameServer: "127.0.0.1",
+			Port:       port,
+			Protocol:   protocol,
+			Assertions: assertions,
+		},
+	}
+}
+
+func TestRunDNSMonitor(t *testing.T) {
+	t.Parallel()
+	port := stubDNSServer(t)
+	tests := []struct {
+		name       string
+		recordType string
+		query      string
+		assertion  checkly.Assertion
+		answers    []any
+	}{
+		{"A", "A", "example.com", assert.JSONResponse("$.answers[1].data").Equals("93.184.216.35"), []any{
+			map[string]any{"name": "example.com.", "type": "A", "class": "IN", "ttl": 300.0, "data": "93.184.216.34"},
+			map[string]any{"name": "example.com.", "type": "A", "class": "IN", "ttl": 300.0, "data": "93.184.216.35"},
+		}},
+		{"AAAA", "AAAA", "example.com.", assert.JSONResponse("$.answers[0].data").Contains("2606:2800"), []any{
+			map[string]any{"name": "example.com.", "type": "AAAA", "class": "IN", "ttl": 300.0, "data": "2606:2800:220:1:248:1893:25c8:1946"},
+		}},
+		{"MX", "MX", "example.com", checkly.Assertion{Source: "TEXT_ANSWER", Comparison: "CONTAINS", Target: "MX 10 mail.example.com."}, []any{
+			map[string]any{"name": "example.com.", "type": "MX", "class": "IN", "ttl": 3600.0, "data": "10 mail.example.com."},
+		}},
+		{"TXT", "TXT", "example.com", checkly.Assertion{Source: "JSON_ANSWER", Property: "$[0].data", Comparison: "CONTAINS", Target: "v=spf1"}, []any{
+			map[string]any{"name": "example.com.", "type": "TXT", "class": "IN", "ttl": 60.0, "data": `"v=spf1 -all" "say \"hi\""`},
+		}},
+		{"CNAME", "CNAME", "www.example.com", assert.JSONResponse("$.answers[0].data").Equals("example.com."), []any{
+			map[string]any{"name": "www.example.com.", "type": "CNAME", "class": "IN", "ttl": 60.0, "data": "example.com."},
+		}},
+		{"PTR", "PTR", "93.184.216.34", assert.JSONResponse("$.answers.length").Equals(1), []any{
+			map[string]any{"name": "34.216.184.93.in-addr.arpa.", "type": "PTR", "class": "IN", "ttl": 60.0, "data": "example.com."},
+		}},
+		{"NXDOMAIN", "A", "missing.example.com", assert.ResponseCode().Equals("NXDOMAIN"), []any{}},
+	}
+	for _, protocol := range []string{"UDP", "TCP"} {
+		for _, tc := range tests {
+			monitor := dnsMonitor(port, protocol, tc.recordType, tc.query, tc.assertion, assert.ResponseTime().LessThan(5000))
+			result, err := dryrun.Run(context.Background(), &monitor)
+			if err != nil {
+				t.Fatalf("%s %s: %v", protocol, tc.name, err)
+			}
+			if result.HasErrors || result.HasFailures {
+				t.Errorf("%s %s: want the check to pass, got %v", protocol, tc.name, *result.ApiCheckResult)
+				continue
+			}
+			response := (*result.ApiCheckResult)["response"].(map[string]any)
+			if diff := cmp.Diff(tc.answers, response["answers"]); diff != "" {
+				t.Errorf("%s %s: answers (-want +got):\n%s", protocol, tc.name, diff)
+			}
+		}
+	}
+}
+
+func TestRunDNSMonitorFailures(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	port := stubDNSServer(t)
+
+	monitor := dnsMonitor(port, "", "A", "missing.example.com", assert.ResponseCode().Equals("NOERROR"))
+	result, err := dryrun.RunDNSMonitor(ctx, monitor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.HasFailures || result.HasErrors {
+		t.Errorf("want a failed assertion, got %v", *result.ApiCheckResult)
+	}
+
+	// Nothing answers over UDP on the port of a closed TCP listener.
+	l, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closed := l.Addr().(*net.TCPAddr).Port
+	l.Close()
+	for _, protocol := range []string{"UDP", "TCP"} {
+		monitor := dnsMonitor(closed, protocol, "A", "example.com")
+		result, err := dryrun.RunDNSMonitor(ctx, monitor, dryrun.WithTimeout(200*time.Millisecond))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !result.HasErrors {
+			t.Errorf("%s: want an error without a name server", protocol)
+		}
+	}
+
+	for _, monitor := range []checkly.DNSMonitor{
+		dnsMonitor(port, "UDP", "BOGUS", "example.com"),
+		dnsMonitor(port, "QUIC", "A", "example.com"),
+		dnsMonitor(port, "UDP", "A", ""),
+	} {
+		if _, err := dryrun.RunDNSMonitor(ctx, monitor); err == nil {
+			t.Errorf("want an error for %+v", monitor.Request)
+		}
+	}
+}
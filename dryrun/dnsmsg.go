@@ -0,0 +1,293 @@
+package dryrun
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/checkly/checkly-go-sdk/assert"
+)
+
+// The DNS messages of DNS monitors are encoded and decoded following RFC 1035,
+// with the presentation formats of the record types DNS monitors query.
+
+// dnsTypes are the codes of the record types by name.
+var dnsTypes = map[string]uint16{
+	"A":     1,
+	"NS":    2,
+	"CNAME": 5,
+	"SOA":   6,
+	"PTR":   12,
+	"MX":    15,
+	"TXT":   16,
+	"AAAA":  28,
+	"SRV":   33,
+	"NAPTR": 35,
+	"DS":    43,
+	"CAA":   257,
+	"ANY":   255,
+}
+
+// dnsClasses are the names of the record classes by code.
+var dnsClasses = map[uint16]string{1: "IN", 3: "CH", 4: "HS"}
+
+// dnsResponseCodes are the names of the response codes by code.
+var dnsResponseCodes = []string{
+	"NOERROR", "FORMERR", "SERVFAIL", "NXDOMAIN", "NOTIMP", "REFUSED",
+	"YXDOMAIN", "YXRRSET", "NXRRSET", "NOTAUTH", "NOTZONE",
+}
+
+const dnsClassIN = 1
+
+var errDNSMessage = errors.New("invalid DNS message")
+
+// dnsType returns the code of a record type, like "A" or "TYPE65".
+func dnsType(name string) (uint16, error) {
+	name = strings.ToUpper(name)
+	if t, ok := dnsTypes[name]; ok {
+		return t, nil
+	}
+	if n, err := strconv.ParseUint(strings.TrimPrefix(name, "TYPE"), 10, 16); err == nil && strings.HasPrefix(name, "TYPE") {
+		return uint16(n), nil
+	}
+	return 0, fmt.Errorf("dryrun: unsupported record type %q", name)
+}
+
+func dnsTypeName(t uint16) string {
+	for name, code := range dnsTypes {
+		if code == t {
+			return name
+		}
+	}
+	return fmt.Sprintf("TYPE%d", t)
+}
+
+func dnsClassName(c uint16) string {
+	if name, ok := dnsClasses[c]; ok {
+		return name
+	}
+	return fmt.Sprintf("CLASS%d", c)
+}
+
+func dnsResponseCode(code int) string {
+	if code < len(dnsResponseCodes) {
+		return dnsResponseCodes[code]
+	}
+	return fmt.Sprintf("RCODE%d", code)
+}
+
+// fqdn returns the fully qualified name of a query, with the reverse lookup
+// name of IP addresses for PTR queries.
+func fqdn(query string, qtype uint16) string {
+	if ip := net.ParseIP(query); ip != nil && qtype == dnsTypes["PTR"] {
+		if ip4 := ip.To4(); ip4 != nil {
+			return fmt.Sprintf("%d.%d.%d.%d.in-addr.arpa.", ip4[3], ip4[2], ip4[1], ip4[0])
+		}
+		digits := hex.EncodeToString(ip)
+		labels := make([]string, 0, len(digits))
+		for i := len(digits) - 1; i >= 0; i-- {
+			labels = append(labels, digits[i:i+1])
+		}
+		return strings.Join(labels, ".") + ".ip6.arpa."
+	}
+	if !strings.HasSuffix(query, ".") {
+		query += "."
+	}
+	return query
+}
+
+// newDNSQuery returns a recursive query for the records of type qtype of
+// name, a fully qualified name.
+func newDNSQuery(id uint16, name string, qtype uint16) ([]byte, error) {
+	msg := make([]byte, 12, 512)
+	binary.BigEndian.PutUint16(msg[0:], id)
+	binary.BigEndian.PutUint16(msg[2:], 1<<8) // Recursion desired.
+	binary.BigEndian.PutUint16(msg[4:], 1)    // One question.
+	if name != "." {
+		for _, label := range strings.Split(strings.TrimSuffix(name, "."), ".") {
+			if label == "" || len(label) > 63 {
+				return nil, fmt.Errorf("dryrun: invalid query %q", name)
+			}
+			msg = append(msg, byte(len(label)))
+			msg = append(msg, label...)
+		}
+	}
+	msg = append(msg, 0)
+	if len(msg) > 12+255 {
+		return nil, fmt.Errorf("dryrun: invalid query %q: too long", name)
+	}
+	msg = binary.BigEndian.AppendUint16(msg, qtype)
+	return binary.BigEndian.AppendUint16(msg, dnsClassIN), nil
+}
+
+// parseDNSResponse returns the response code and answers of msg, the
+// response to the query with id.
+func parseDNSResponse(msg []byte, id uint16) (assert.DNSResponse, error) {
+	var resp assert.DNSResponse
+	if len(msg) < 12 {
+		return resp, errDNSMessage
+	}
+	if binary.BigEndian.Uint16(msg[0:]) != id || msg[2]&0x80 == 0 {
+		return resp, fmt.Errorf("%w: not a response to the query", errDNSMessage)
+	}
+	resp.ResponseCode = dnsResponseCode(int(msg[3] & 0x0f))
+	questions := binary.BigEndian.Uint16(msg[4:])
+	answers := binary.BigEndian.Uint16(msg[6:])
+	off := 12
+	for i := 0; i < int(questions); i++ {
+		_, next, err := readDNSName(msg, off)
+		if err != nil || next+4 > len(msg) {
+			return resp, errDNSMessage
+		}
+		off = next + 4
+	}
+	resp.Answers = make([]assert.DNSRecord, 0, answers)
+	for i := 0; i < int(answers); i++ {
+		name, next, err := readDNSName(msg, off)
+		if err != nil || next+10 > len(msg) {
+			return resp, errDNSMessage
+		}
+		rtype := binary.BigEndian.Uint16(msg[next:])
+		class := binary.BigEndian.Uint16(msg[next+2:])
+		ttl := binary.BigEndian.Uint32(msg[next+4:])
+		length := int(binary.BigEndian.Uint16(msg[next+8:]))
+		start := next + 10
+		if start+length > len(msg) {
+			return resp, errDNSMessage
+		}
+		data, err := formatRData(msg, start, length, rtype)
+		if err != nil {
+			return resp, err
+		}
+		resp.Answers = append(resp.Answers, assert.DNSRecord{
+			Name:  name,
+			Type:  dnsTypeName(rtype),
+			Class: dnsClassName(class),
+			TTL:   ttl,
+			Data:  data,
+		})
+		off = start + length
+	}
+	return resp, nil
+}
+
+// readDNSName returns the possibly compressed name at off in msg, and the
+// offset following it.
+func readDNSName(msg []byte, off int) (string, int, error) {
+	var labels []string
+	next := -1
+	for jumps := 0; ; {
+		if off >= len(msg) {
+			return "", 0, errDNSMessage
+		}
+		n := int(msg[off])
+		switch {
+		case n == 0:
+			if next < 0 {
+				next = off + 1
+			}
+			return strings.Join(labels, ".") + ".", next, nil
+		case n&0xc0 == 0xc0:
+			if off+1 >= len(msg) || jumps > 32 {
+				return "", 0, errDNSMessage
+			}
+			if next < 0 {
+				next = off + 2
+			}
+			off = int(binary.BigEndian.Uint16(msg[off:]) & 0x3fff)
+			jumps++
+		case n&0xc0 != 0 || off+1+n > len(msg):
+			return "", 0, errDNSMessage
+		default:
+			labels = append(labels, string(msg[off+1:off+1+n]))
+			off += 1 + n
+		}
+	}
+}
+
+// formatRData returns the data of a record in presentation format.
+func formatRData(msg []byte, off, length int, rtype uint16) (string, error) {
+	rdata := msg[off : off+length]
+	name := func(at int) (string, int, error) {
+		return readDNSName(msg, off+at)
+	}
+	switch dnsTypeName(rtype) {
+	case "A", "AAAA":
+		if len(rdata) != net.IPv4len && len(rdata) != net.IPv6len {
+			return "", errDNSMessage
+		}
+		return net.IP(rdata).String(), nil
+	case "NS", "CNAME", "PTR":
+		n, _, err := name(0)
+		return n, err
+	case "MX":
+		if len(rdata) < 3 {
+			return "", errDNSMessage
+		}
+		n, _, err := name(2)
+		return fmt.Sprintf("%d %s", binary.BigEndian.Uint16(rdata), n), err
+	case "SRV":
+		if len(rdata) < 7 {
+			return "", errDNSMessage
+		}
+		n, _, err := name(6)
+		return fmt.Sprintf("%d %d %d %s", binary.BigEndian.Uint16(rdata), binary.BigEndian.Uint16(rdata[2:]),
+			binary.BigEndian.Uint16(rdata[4:]), n), err
+	case "SOA":
+		mname, next, err := name(0)
+		if err != nil {
+			return "", err
+		}
+		rname, next, err := readDNSName(msg, next)
+		if err != nil || next+20 > off+length {
+			return "", errDNSMessage
+		}
+		v := msg[next:]
+		return fmt.Sprintf("%s %s %d %d %d %d %d", mname, rname, binary.BigEndian.Uint32(v),
+			binary.BigEndian.Uint32(v[4:]), binary.BigEndian.Uint32(v[8:]), binary.BigEndian.Uint32(v[12:]),
+			binary.BigEndian.Uint32(v[16:])), nil
+	case "TXT":
+		var texts []string
+		for i := 0; i < len(rdata); {
+			n := int(rdata[i])
+			if i+1+n > len(rdata) {
+				return "", errDNSMessage
+			}
+			texts = append(texts, quoteDNSText(rdata[i+1:i+1+n]))
+			i += 1 + n
+		}
+		return strings.Join(texts, " "), nil
+	case "CAA":
+		if len(rdata) < 2 || 2+int(rdata[1]) > len(rdata) {
+			return "", errDNSMessage
+		}
+		tag := string(rdata[2 : 2+rdata[1]])
+		return fmt.Sprintf("%d %s %s", rdata[0], tag, quoteDNSText(rdata[2+rdata[1]:])), nil
+	default:
+		// RFC 3597 format of unknown record types.
+		return fmt.Sprintf("\\# %d %s", len(rdata), hex.EncodeToString(rdata)), nil
+	}
+}
+
+// quoteDNSText returns a character string in presentation format.
+func quoteDNSText(s []byte) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, c := range s {
+		switch {
+		case c == '"' || c == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c < ' ' || c > '~':
+			fmt.Fprintf(&b, "\\%03d", c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
@@ -0,0 +1,223 @@
+// Package dryrun runs checks and monitors locally, to try them out before
+// creating them. A dry run makes the same request as a check run, evaluates
+// the assertions of the check with the assert package and returns a
+// checkly.CheckResult, so the same code can report on local and remote runs:
+//
+//	result, err := dryrun.Run(ctx, &check,
+//		dryrun.WithGroup(group),
+//		dryrun.WithEnvironmentVariables(accountVariables),
+//	)
+//	if err != nil {
+//		panic(err)
+//	}
+//	if result.HasFailures || result.HasErrors {
+//		fmt.Println("the check would fail")
+//	}
+//
+// Failures of the request itself, like a refused connection, are reported in
+// the result with HasErrors set, as they are for check runs. Errors are only
+// returned for checks which cannot run at all.
+package dryrun
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+
+	checkly "github.com/checkly/checkly-go-sdk"
+	"github.com/checkly/checkly-go-sdk/assert"
+)
+
+// RunLocation is the run location of the results of dry runs.
+const RunLocation = "local"
+
+// DefaultTimeout is the longest a dry run takes unless configured
+// otherwise, see WithTimeout.
+const DefaultTimeout = 30 * time.Second
+
+// Option configures a dry run.
+type Option func(*options)
+
+type options struct {
+	group             *checkly.GroupV2
+	variables         []checkly.EnvironmentVariable
+	timeout           time.Duration
+	rootCAs           *x509.CertPool
+	clientCertificate *checkly.ClientCertificate
+}
+
+// WithGroup runs the check as part of group, which provides environment
+// variables and the defaults for API checks.
+func WithGroup(group checkly.GroupV2) Option {
+	return func(o *options) {
+		o.group = &group
+	}
+}
+
+// WithEnvironmentVariables sets the environment variables of the account.
+// Variables of the check and its group with the same key take precedence.
+func WithEnvironmentVariables(variables []checkly.EnvironmentVariable) Option {
+	return func(o *options) {
+		o.variables = variables
+	}
+}
+
+// WithTimeout limits the time to connect, send the request and receive the
+// response of a dry run, DefaultTimeout by default. A run exceeding it
+// reports an error in its result.
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.timeout = timeout
+	}
+}
+
+// WithRootCAs sets the certificate authorities which are trusted to verify
+// the certificates of servers, instead of those of the system. This is
+// useful to test against a server with a self-signed certificate, like
+// httptest.NewTLSServer.
+func WithRootCAs(pool *x509.CertPool) Option {
+	return func(o *options) {
+		o.rootCAs = pool
+	}
+}
+
+// WithClientCertificate sets the client certificate of SSL monitors, which
+// is otherwise stored in the account. Its TrustedCA bundle is added to the
+// trusted certificate authorities.
+func WithClientCertificate(certificate checkly.ClientCertificate) Option {
+	return func(o *options) {
+		o.clientCertificate = &certificate
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := options{timeout: DefaultTimeout}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// Run runs m locally, see the Run functions of its type. Only API checks, URL
+// monitors and the monitor types with a Run function are supported.
+func Run(ctx context.Context, m checkly.Monitor, opts ...Option) (*checkly.CheckResult, error) {
+	switch m := m.(type) {
+	case *checkly.Check:
+		return RunCheck(ctx, *m, opts...)
+	case *checkly.URLMonitor:
+		return RunURLMonitor(ctx, *m, opts...)
+	case *checkly.TCPMonitor:
+		return RunTCPMonitor(ctx, *m, opts...)
+	case *checkly.DNSMonitor:
+		return RunDNSMonitor(ctx, *m, opts...)
+	case *checkly.SSLMonitor:
+		return RunSSLMonitor(ctx, *m, opts...)
+	default:
+		return nil, fmt.Errorf("dryrun: %s checks are not supported", m.CheckType())
+	}
+}
+
+// variablePattern matches the {{KEY}} placeholders of environment variables.
+var variablePattern = regexp.MustCompile(`{{\s*([^{}\s]+)\s*}}`)
+
+// variables resolves the placeholders of environment variables.
+type variables map[string]string
+
+// newVariables returns the variables of a check, which take precedence over
+// those of its group and then those of the account.
+func newVariables(o options, check []checkly.EnvironmentVariable) variables {
+	v := variables{}
+	for _, e := range o.variables {
+		v[e.Key] = e.Value
+	}
+	if o.group != nil {
+		for _, e := range o.group.EnvironmentVariables {
+			v[e.Key] = e.Value
+		}
+		if o.group.APICheckDefaults.BaseURL != "" {
+			v["GROUP_BASE_URL"] = o.group.APICheckDefaults.BaseURL
+		}
+	}
+	for _, e := range check {
+		v[e.Key] = e.Value
+	}
+	return v
+}
+
+// replace returns s with its placeholders replaced by the values of the
+// variables. Placeholders of the built-in variables, like {{$UUID}}, are kept
+// as they are, while those of undefined variables are an error.
+func (v variables) replace(s string) (string, error) {
+	var undefined []string
+	result := variablePattern.ReplaceAllStringFunc(s, func(placeholder string) string {
+		key := variablePattern.FindStringSubmatch(placeholder)[1]
+		if value, ok := v[key]; ok {
+			return value
+		}
+		if !strings.HasPrefix(key, "$") {
+			undefined = append(undefined, key)
+		}
+		return placeholder
+	})
+	if len(undefined) > 0 {
+		return "", fmt.Errorf("dryrun: undefined environment variable %s", strings.Join(undefined, ", "))
+	}
+	return result, nil
+}
+
+// newResult returns the result of a run of a check named name with ID id,
+// which took from start to stop.
+func newResult(id, name string, start, stop time.Time) *checkly.CheckResult {
+	return &checkly.CheckResult{
+		Name:         name,
+		CheckID:      id,
+		RunLocation:  RunLocation,
+		ResponseTime: int64(math.Round(milliseconds(stop.Sub(start)))),
+		Attempts:     1,
+		StartedAt:    start,
+		StoppedAt:    stop,
+		CreatedAt:    stop,
+	}
+}
+
+// grade sets the failure and degradation flags of result, given the results
+// of its assertions and the degraded and maximum response times of its check
+// in milliseconds, if any. With shouldFail the assertions are expected to
+// fail.
+func grade(result *checkly.CheckResult, assertions assert.Results, shouldFail bool, degraded, max int) {
+	result.HasFailures = assertions.Passed() == shouldFail
+	if max > 0 && result.ResponseTime > int64(max) {
+		result.OverMaxResponseTime = true
+		result.HasFailures = true
+	}
+	result.IsDegraded = degraded > 0 && result.ResponseTime > int64(degraded)
+}
+
+// assertionResults returns assertion results in the format of check results.
+func assertionResults(results assert.Results) []map[string]any {
+	list := make([]map[string]any, len(results))
+	for i, r := range results {
+		a := map[string]any{
+			"order":      i,
+			"source":     r.Assertion.Source,
+			"property":   r.Assertion.Property,
+			"comparison": r.Assertion.Comparison,
+			"target":     r.Assertion.Target,
+			"actual":     r.Actual,
+			"passed":     r.Passed,
+		}
+		if r.Err != nil {
+			a["error"] = r.Err.Error()
+		}
+		list[i] = a
+	}
+	return list
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,305 @@
+package dryrun
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"strings"
+	"time"
+
+	checkly "github.com/checkly/checkly-go-sdk"
+	"github.com/checkly/checkly-go-sdk/assert"
+)
+
+// RunCheck runs the request of an API check and evaluates its assertions.
+//
+// The {{KEY}} placeholders in the URL, headers, query parameters, body and
+// basic authentication of the request are replaced by the environment
+// variables of the check, its group and the account, and {{GROUP_BASE_URL}}
+// by the base URL of the group. The API check defaults of the group are added
+// to the request, see checkly.ResolveEffective.
+//
+// The check fails if an assertion fails, or the response time exceeds
+// MaxResponseTime, and is degraded if it exceeds DegradedResponseTime. The
+// details of the request and response are in the ApiCheckResult of the
+// result, in the format of check runs.
+func RunCheck(ctx context.Context, check checkly.Check, opts ...Option) (*checkly.CheckResult, error) {
+	if check.Type != checkly.TypeAPI {
+		return nil, fmt.Errorf("dryrun: %s checks are not supported", check.Type)
+	}
+	o := newOptions(opts)
+	check = checkly.ResolveEffective(check, o.group, nil).Check
+	return runHTTP(ctx, httpRun{
+		id:         check.ID,
+		name:       check.Name,
+		request:    check.Request,
+		variables:  newVariables(o, check.EnvironmentVariables),
+		timeout:    o.timeout,
+		rootCAs:    o.rootCAs,
+		shouldFail: check.ShouldFail,
+		degraded:   check.DegradedResponseTime,
+		max:        check.MaxResponseTime,
+	})
+}
+
+// RunURLMonitor requests the URL of a URL monitor and evaluates its
+// assertions, like RunCheck. Only the environment variables apply to URL
+// monitors, not the API check defaults of the group.
+func RunURLMonitor(ctx context.Context, monitor checkly.URLMonitor, opts ...Option) (*checkly.CheckResult, error) {
+	o := newOptions(opts)
+	return runHTTP(ctx, httpRun{
+		id:   monitor.ID,
+		name: monitor.Name,
+		request: checkly.Request{
+			Method:          http.MethodGet,
+			URL:             monitor.Request.URL,
+			FollowRedirects: monitor.Request.FollowRedirects,
+			SkipSSL:         monitor.Request.SkipSSL,
+			Assertions:      monitor.Request.Assertions,
+			IPFamily:        monitor.Request.IPFamily,
+		},
+		variables:  newVariables(o, nil),
+		timeout:    o.timeout,
+		rootCAs:    o.rootCAs,
+		shouldFail: monitor.ShouldFail,
+		degraded:   monitor.DegradedResponseTime,
+		max:        monitor.MaxResponseTime,
+	})
+}
+
+// httpRun is a run of an API check or URL monitor.
+type httpRun struct {
+	id, name      string
+	request       checkly.Request
+	variables     variables
+	timeout       time.Duration
+	rootCAs       *x509.CertPool
+	shouldFail    bool
+	degraded, max int
+}
+
+func runHTTP(ctx context.Context, run httpRun) (*checkly.CheckResult, error) {
+	req, body, err := newHTTPRequest(ctx, run.request, run.variables)
+	if err != nil {
+		return nil, err
+	}
+	client := newHTTPClient(run.request, run.timeout, run.rootCAs)
+	defer client.CloseIdleConnections()
+
+	var phases timingPhases
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), phases.trace()))
+	var respBody []byte
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err == nil {
+		respBody, err = io.ReadAll(resp.Body)
+		resp.Body.Close()
+	}
+	stop := time.Now()
+
+	result := newResult(run.id, run.name, start, stop)
+	apiResult := checkly.ApiCheckResult{"request": requestDetails(req, body)}
+	if err != nil {
+		result.HasErrors = true
+		result.HasFailures = true
+		apiResult["requestError"] = err.Error()
+		result.ApiCheckResult = &apiResult
+		return result, nil
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	sample, err := assert.HTTPSample(resp, stop.Sub(start))
+	if err != nil {
+		return nil, err
+	}
+	assertions := assert.Evaluate(run.request.Assertions, sample)
+	grade(result, assertions, run.shouldFail, run.degraded, run.max)
+	apiResult["response"] = responseDetails(resp, respBody, phases.durations(start, stop))
+	apiResult["assertions"] = assertionResults(assertions)
+	result.ApiCheckResult = &apiResult
+	return result, nil
+}
+
+// newHTTPRequest returns the HTTP request of request and its body, with the
+// placeholders of variables replaced.
+func newHTTPRequest(ctx context.Context, request checkly.Request, vars variables) (*http.Request, string, error) {
+	rawURL, err := vars.replace(request.URL)
+	if err != nil {
+		return nil, "", err
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, "", fmt.Errorf("dryrun: invalid URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, "", fmt.Errorf("dryrun: invalid URL %q: the scheme must be http or https", rawURL)
+	}
+	if len(request.QueryParameters) > 0 {
+		query := u.Query()
+		for _, kv := range request.QueryParameters {
+			value, err := vars.replace(kv.Value)
+			if err != nil {
+				return nil, "", err
+			}
+			query.Add(kv.Key, value)
+		}
+		u.RawQuery = query.Encode()
+	}
+	body, err := vars.replace(request.Body)
+	if err != nil {
+		return nil, "", err
+	}
+	method := request.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), strings.NewReader(body))
+	if err != nil {
+		return nil, "", fmt.Errorf("dryrun: %w", err)
+	}
+	switch request.BodyType {
+	case "JSON", "GRAPHQL":
+		req.Header.Set("Content-Type", "application/json")
+	case "FORM":
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	for _, kv := range request.Headers {
+		value, err := vars.replace(kv.Value)
+		if err != nil {
+			return nil, "", err
+		}
+		if strings.EqualFold(kv.Key, "Host") {
+			req.Host = value
+			continue
+		}
+		req.Header.Set(kv.Key, value)
+	}
+	if auth := request.BasicAuth; auth != nil && auth.Username != "" {
+		username, err := vars.replace(auth.Username)
+		if err != nil {
+			return nil, "", err
+		}
+		password, err := vars.replace(auth.Password)
+		if err != nil {
+			return nil, "", err
+		}
+		req.SetBasicAuth(username, password)
+	}
+	return req, body, nil
+}
+
+// newHTTPClient returns a client following the redirects, TLS and IP family
+// settings of request, which times out after timeout and trusts rootCAs, or
+// the certificate authorities of the system if nil.
+func newHTTPClient(request checkly.Request, timeout time.Duration, rootCAs *x509.CertPool) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: request.SkipSSL, RootCAs: rootCAs}
+	if network := ipNetwork(request.IPFamily); network != "tcp" {
+		var dialer net.Dialer
+		transport.DialContext = func(ctx context.Context, _, addr string) (net.Conn, error) {
+			return dialer.DialContext(ctx, network, addr)
+		}
+	}
+	client := &http.Client{Transport: transport, Timeout: timeout}
+	if !request.FollowRedirects {
+		client.CheckRedirect = func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	}
+	return client
+}
+
+// ipNetwork returns the network to dial for an IP family.
+func ipNetwork(family string) string {
+	switch family {
+	case "IPv4":
+		return "tcp4"
+	case "IPv6":
+		return "tcp6"
+	default:
+		return "tcp"
+	}
+}
+
+// timingPhases records the timing of an HTTP request.
+type timingPhases struct {
+	dnsStart, dnsDone         time.Time
+	connectStart, connectDone time.Time
+	tlsStart, tlsDone         time.Time
+	wroteRequest, firstByte   time.Time
+}
+
+func (t *timingPhases) trace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart:             func(httptrace.DNSStartInfo) { t.dnsStart = time.Now() },
+		DNSDone:              func(httptrace.DNSDoneInfo) { t.dnsDone = time.Now() },
+		ConnectStart:         func(string, string) { t.connectStart = time.Now() },
+		ConnectDone:          func(string, string, error) { t.connectDone = time.Now() },
+		TLSHandshakeStart:    func() { t.tlsStart = time.Now() },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { t.tlsDone = time.Now() },
+		WroteRequest:         func(httptrace.WroteRequestInfo) { t.wroteRequest = time.Now() },
+		GotFirstResponseByte: func() { t.firstByte = time.Now() },
+	}
+}
+
+// durations returns the duration of the phases in milliseconds, in the
+// format of check results.
+func (t *timingPhases) durations(start, stop time.Time) map[string]any {
+	phase := func(from, to time.Time) float64 {
+		if from.IsZero() || to.IsZero() {
+			return 0
+		}
+		return milliseconds(to.Sub(from))
+	}
+	return map[string]any{
+		"dns":       phase(t.dnsStart, t.dnsDone),
+		"tcp":       phase(t.connectStart, t.connectDone),
+		"tls":       phase(t.tlsStart, t.tlsDone),
+		"firstByte": phase(t.wroteRequest, t.firstByte),
+		"download":  phase(t.firstByte, stop),
+		"total":     phase(start, stop),
+	}
+}
+
+// requestDetails returns the details of req in the format of check results.
+func requestDetails(req *http.Request, body string) map[string]any {
+	params := map[string]any{}
+	for k, v := range req.URL.Query() {
+		params[k] = strings.Join(v, ", ")
+	}
+	headers := map[string]any{}
+	for k, v := range req.Header {
+		headers[strings.ToLower(k)] = strings.Join(v, ", ")
+	}
+	return map[string]any{
+		"url":     req.URL.String(),
+		"method":  req.Method,
+		"data":    body,
+		"params":  params,
+		"headers": headers,
+	}
+}
+
+// responseDetails returns the details of resp in the format of check
+// results.
+func responseDetails(resp *http.Response, body []byte, phases map[string]any) map[string]any {
+	headers := map[string]any{}
+	for k, v := range resp.Header {
+		headers[strings.ToLower(k)] = strings.Join(v, ", ")
+	}
+	return map[string]any{
+		"href":         resp.Request.URL.String(),
+		"status":       resp.StatusCode,
+		"statusText":   http.StatusText(resp.StatusCode),
+		"headers":      headers,
+		"body":         string(body),
+		"truncated":    false,
+		"timingPhases": phases,
+	}
+}
@@ -0,0 +1,313 @@
+package dryrun_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	checkly "github.com/checkly/checkly-go-sdk"
+	"github.com/checkly/checkly-go-sdk/assert"
+	"github.com/checkly/checkly-go-sdk/dryrun"
+)
+
+// echoServer serves the request it receives as JSON, and redirects /old to
+// /new.
+func echoServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/old", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/new", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		fmt.Fprint(w, "done")
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		username, password, _ := r.BasicAuth()
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{
+			"method":      r.Method,
+			"path":        r.URL.Path,
+			"query":       r.URL.Query(),
+			"headers":     r.Header,
+			"body":        string(body),
+			"username":    username,
+			"password":    password,
+			"contentType": r.Header.Get("Content-Type"),
+		})
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func apiCheck(url string, assertions ...checkly.Assertion) checkly.Check {
+	return checkly.Check{
+		ID:   "check-id",
+		Name: "API check",
+		Type: checkly.TypeAPI,
+		Request: checkly.Request{
+			Method:     http.MethodGet,
+			URL:        url,
+			Assertions: assertions,
+		},
+	}
+}
+
+func apiResponse(t *testing.T, result *checkly.CheckResult) map[string]any {
+	t.Helper()
+	if result.ApiCheckResult == nil {
+		t.Fatal("no API check result")
+	}
+	response, ok := (*result.ApiCheckResult)["response"].(map[string]any)
+	if !ok {
+		t.Fatalf("no response in %v", *result.ApiCheckResult)
+	}
+	return response
+}
+
+func TestRunCheck(t *testing.T) {
+	t.Parallel()
+	server := echoServer(t)
+	check := apiCheck("{{GROUP_BASE_URL}}/users/{{USER_ID}}",
+		assert.StatusCode().Equals(200),
+		assert.JSONBody("$.path").Equals("/users/42"),
+		assert.JSONBody("$.query.page[0]").Equals("2"),
+		assert.JSONBody("$.query.limit[0]").Equals("10"),
+		assert.JSONBody("$.headers.X-Env[0]").Equals("staging"),
+		assert.JSONBody("$.headers.X-Team[0]").Equals("web"),
+		assert.JSONBody("$.username").Equals("admin"),
+		assert.JSONBody("$.password").Equals("s3cret"),
+		assert.JSONBody("$.body").Equals(`{"user": 42}`),
+		assert.JSONBody("$.contentType").Equals("application/json"),
+		assert.JSONBody("$.method").Equals("POST"),
+	)
+	check.Request.Method = http.MethodPost
+	check.Request.Body = `{"user": {{USER_ID}}}`
+	check.Request.BodyType = "JSON"
+	check.Request.Headers = []checkly.KeyValue{{Key: "x-env", Value: "{{ENV}}"}}
+	check.Request.QueryParameters = []checkly.KeyValue{{Key: "page", Value: "2"}}
+	check.EnvironmentVariables = []checkly.EnvironmentVariable{{Key: "USER_ID", Value: "42"}}
+	group := checkly.GroupV2{
+		EnvironmentVariables: []checkly.EnvironmentVariable{
+			{Key: "ENV", Value: "staging"},
+			{Key: "USER_ID", Value: "7"},
+		},
+		APICheckDefaults: checkly.APICheckDefaults{
+			BaseURL: server.URL,
+			Headers: []checkly.KeyValue{
+				{Key: "X-Env", Value: "production"},
+				{Key: "X-Team", Value: "{{TEAM}}"},
+			},
+			QueryParameters: []checkly.KeyValue{{Key: "limit", Value: "10"}},
+			BasicAuth:       checkly.BasicAuth{Username: "admin", Password: "{{PASSWORD}}"},
+			Assertions:      []checkly.Assertion{assert.ResponseTime().LessThan(10000)},
+		},
+	}
+	account := []checkly.EnvironmentVariable{
+		{Key: "TEAM", Value: "web"},
+		{Key: "PASSWORD", Value: "s3cret"},
+		{Key: "ENV", Value: "dev"},
+	}
+
+	result, err := dryrun.RunCheck(context.Background(), check,
+		dryrun.WithGroup(group),
+		dryrun.WithEnvironmentVariables(account),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertions := (*result.ApiCheckResult)["assertions"].([]map[string]any)
+	if result.HasFailures || result.HasErrors {
+		t.Fatalf("want the check to pass, got %v", assertions)
+	}
+	if len(assertions) != 12 {
+		t.Errorf("want the assertions of the check and group, got %d", len(assertions))
+	}
+	if result.Name != "API check" || result.CheckID != "check-id" || result.RunLocation != dryrun.RunLocation || result.Attempts != 1 {
+		t.Errorf("unexpected result %+v", result)
+	}
+	if result.StoppedAt.Before(result.StartedAt) {
+		t.Errorf("stopped at %v before starting at %v", result.StoppedAt, result.StartedAt)
+	}
+	response := apiResponse(t, result)
+	if response["status"] != 200 || response["statusText"] != "OK" {
+		t.Errorf("unexpected status %v %v", response["status"], response["statusText"])
+	}
+	if _, ok := response["timingPhases"].(map[string]any)["total"]; !ok {
+		t.Errorf("no total in timing phases %v", response["timingPhases"])
+	}
+	request := (*result.ApiCheckResult)["request"].(map[string]any)
+	if want := server.URL + "/users/42?limit=10&page=2"; request["url"] != want {
+		t.Errorf("want request URL %s, got %v", want, request["url"])
+	}
+	if request["data"] != `{"user": 42}` {
+		t.Errorf("unexpected request data %v", request["data"])
+	}
+}
+
+func TestRunCheckFailures(t *testing.T) {
+	t.Parallel()
+	server := echoServer(t)
+	ctx := context.Background()
+
+	result, err := dryrun.RunCheck(ctx, apiCheck(server.URL, assert.StatusCode().Equals(404)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.HasFailures || result.HasErrors {
+		t.Errorf("want a failed assertion, got %+v", result)
+	}
+
+	check := apiCheck(server.URL, assert.StatusCode().Equals(404))
+	check.ShouldFail = true
+	result, err = dryrun.RunCheck(ctx, check)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.HasFailures {
+		t.Error("want a check which should fail to pass when its assertion fails")
+	}
+
+	check = apiCheck(server.URL + "/slow")
+	check.DegradedResponseTime = 10
+	result, err = dryrun.RunCheck(ctx, check)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.IsDegraded || result.OverMaxResponseTime || result.HasFailures {
+		t.Errorf("want a degraded check, got %+v", result)
+	}
+	if result.ResponseTime < 50 {
+		t.Errorf("want a response time of at least 50ms, got %d", result.ResponseTime)
+	}
+	check.MaxResponseTime = 20
+	result, err = dryrun.RunCheck(ctx, check)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.OverMaxResponseTime || !result.HasFailures {
+		t.Errorf("want a check over its maximum response time, got %+v", result)
+	}
+
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+	result, err = dryrun.RunCheck(ctx, apiCheck(closed.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.HasErrors || !result.HasFailures {
+		t.Errorf("want an error for a closed server, got %+v", result)
+	}
+	if _, ok := (*result.ApiCheckResult)["requestError"].(string); !ok {
+		t.Errorf("no request error in %v", *result.ApiCheckResult)
+	}
+
+	check = apiCheck(server.URL)
+	check.Request.IPFamily = "IPv6"
+	result, err = dryrun.RunCheck(ctx, check)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.HasErrors {
+		t.Error("want an error requesting an IPv4 server over IPv6")
+	}
+}
+
+func TestRunCheckErrors(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	tests := []struct {
+		name  string
+		check checkly.Check
+		err   string
+	}{
+		{"undefined variable", apiCheck("https://{{HOST}}/{{$RANDOM_NUMBER}}"), "undefined environment variable HOST"},
+		{"invalid scheme", apiCheck("ftp://example.com"), "scheme must be http or https"},
+		{"browser check", checkly.Check{Type: checkly.TypeBrowser}, "BROWSER checks are not supported"},
+	}
+	for _, tc := range tests {
+		_, err := dryrun.RunCheck(ctx, tc.check)
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("%s: want error containing %q, got %v", tc.name, tc.err, err)
+		}
+	}
+}
+
+func TestRunURLMonitor(t *testing.T) {
+	t.Parallel()
+	server := echoServer(t)
+	ctx := context.Background()
+	monitor := checkly.URLMonitor{
+		Name: "URL monitor",
+		Request: checkly.URLRequest{
+			URL:        server.URL + "/{{PATH}}",
+			Assertions: []checkly.Assertion{assert.StatusCode().Equals(301)},
+		},
+	}
+	vars := dryrun.WithEnvironmentVariables([]checkly.EnvironmentVariable{{Key: "PATH", Value: "old"}})
+
+	result, err := dryrun.Run(ctx, &monitor, vars)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.HasFailures {
+		t.Errorf("want the redirect not to be followed, got %v", apiResponse(t, result)["status"])
+	}
+
+	monitor.Request.FollowRedirects = true
+	monitor.Request.Assertions = []checkly.Assertion{assert.StatusCode().Equals(200)}
+	result, err = dryrun.Run(ctx, &monitor, vars)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.HasFailures {
+		t.Errorf("want the redirect to be followed, got %v", apiResponse(t, result)["status"])
+	}
+	if href := apiResponse(t, result)["href"]; href != server.URL+"/new" {
+		t.Errorf("want the response of %s/new, got %v", server.URL, href)
+	}
+}
+
+func TestRunSkipSSL(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Config.ErrorLog = log.New(io.Discard, "", 0)
+	server.StartTLS()
+	defer server.Close()
+	ctx := context.Background()
+	monitor := checkly.URLMonitor{Request: checkly.URLRequest{URL: server.URL}}
+
+	result, err := dryrun.RunURLMonitor(ctx, monitor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.HasErrors {
+		t.Error("want an error for the untrusted certificate of the server")
+	}
+
+	monitor.Request.SkipSSL = true
+	result, err = dryrun.RunURLMonitor(ctx, monitor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.HasErrors {
+		t.Errorf("want SkipSSL to skip the certificate verification, got %v", (*result.ApiCheckResult)["requestError"])
+	}
+}
+
+func TestRunUnsupported(t *testing.T) {
+	t.Parallel()
+	_, err := dryrun.Run(context.Background(), &checkly.HeartbeatMonitor{})
+	if err == nil {
+		t.Error("want an error for heartbeat monitors")
+	}
+}
@@ -0,0 +1,540 @@
+package dryrun
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	checkly "github.com/checkly/checkly-go-sdk"
+	"github.com/checkly/checkly-go-sdk/assert"
+)
+
+// The severities of the rules of security baselines.
+const (
+	severityFail   = "fail"
+	severityWarn   = "warn"
+	severityIgnore = "ignore"
+)
+
+// defaultBaseline are the rules of the security baseline of SSL monitors
+// which don't override them.
+var defaultBaseline = checkly.SecurityBaseline{
+	MinTLSVersion:           &checkly.SSLBaselineTLSRule{Value: "TLS1.2", Severity: severityFail},
+	MinKeySizeBits:          &checkly.SSLBaselineKeySizeRule{Value: 2048, Severity: severityFail},
+	WeakSignatureAlgorithm:  &checkly.SSLBaselineSeverityRule{Severity: severityFail},
+	WeakCipherSuite:         &checkly.SSLBaselineSeverityRule{Severity: severityFail},
+	KnownBadCA:              &checkly.SSLBaselineSeverityRule{Severity: severityFail},
+	RecommendedTLSVersion:   &checkly.SSLBaselineTLSRule{Value: "TLS1.3", Severity: severityIgnore},
+	RecommendedKeySizeBits:  &checkly.SSLBaselineKeySizeRule{Value: 3072, Severity: severityIgnore},
+	OCSPMustStapleRespected: &checkly.SSLBaselineSeverityRule{Severity: severityIgnore},
+	SCTPresent:              &checkly.SSLBaselineSeverityRule{Severity: severityIgnore},
+}
+
+// The failure categories of SSL monitor results.
+const (
+	failureConnection = "connection"
+	failureHandshake  = "handshake"
+	failureExpired    = "expired"
+	failureExpiring   = "expiring"
+	failureUntrusted  = "untrusted"
+	failureHostname   = "hostname"
+	failureBaseline   = "baseline"
+	failureAssertion  = "assertion"
+)
+
+var (
+	// oidMustStaple is the TLS feature extension requesting OCSP stapling.
+	oidMustStaple = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 1, 24}
+	// oidSCTList is the extension embedding signed certificate timestamps.
+	oidSCTList = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 11129, 2, 4, 2}
+)
+
+// RunSSLMonitor performs the TLS handshake of an SSL monitor, grades the
+// connection and certificate against its security baseline and evaluates its
+// assertions, see assert.SSLSample.
+//
+// The handshake connects to the hostname and port of the monitor, 443 by
+// default, with its IP family and the server name, which defaults to the
+// hostname, for SNI and hostname verification. It times out after the
+// handshake timeout of the monitor, or the timeout of the run. The client
+// certificate set WithClientCertificate is presented in explicit client
+// certificate mode, and in auto mode if its host is the hostname.
+//
+// The check fails if the handshake fails, the certificate expired or expires
+// within AlertDaysBeforeExpiry days, the chain isn't trusted unless
+// SkipChainValidation is set, the certificate isn't valid for the server
+// name, the baseline verdict is FAIL, an assertion fails or the response
+// time exceeds MaxResponseTime. FailureCategory names the first of these
+// reasons.
+//
+// The rules of the baseline of the monitor override those of the default
+// baseline: a minimum of TLS 1.2 and 2048 bit keys and no weak signature
+// algorithms or cipher suites, which fail the check, and the advisory TLS 1.3
+// and 3072 bit keys, OCSP must-staple and signed certificate timestamps,
+// which are ignored. The sizes of elliptic curve keys are compared with the
+// size of RSA keys of equivalent strength. The known bad CA rule needs the
+// list of the server, and is skipped. The verdict is FAIL if a rule with
+// severity "fail" is violated, WARN if one with severity "warn" is violated
+// and PASS otherwise. The grade is A without violations, B with only
+// advisory violations, C with one enforceable violation and F otherwise,
+// disregarding ignored rules. The details of each rule are in the
+// SecurityBaseline of the response.
+func RunSSLMonitor(ctx context.Context, monitor checkly.SSLMonitor, opts ...Option) (*checkly.CheckResult, error) {
+	o := newOptions(opts)
+	config := monitor.Request.SSLConfig
+	if config.Hostname == "" {
+		return nil, fmt.Errorf("dryrun: the hostname of SSL monitors is required")
+	}
+	baseline, err := mergeBaseline(config.SecurityBaseline)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig, err := sslClientConfig(config, o)
+	if err != nil {
+		return nil, err
+	}
+	port := config.Port
+	if port == 0 {
+		port = 443
+	}
+	timeout := o.timeout
+	if config.HandshakeTimeoutMs > 0 {
+		timeout = time.Duration(config.HandshakeTimeoutMs) * time.Millisecond
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ssl := &checkly.SSLCheckResult{Request: map[string]any{
+		"hostname":   config.Hostname,
+		"port":       port,
+		"serverName": tlsConfig.ServerName,
+		"ipFamily":   config.IPFamily,
+	}}
+	var state tls.ConnectionState
+	var resolvedIP string
+	var handshake time.Duration
+	start := time.Now()
+	conn, err := new(net.Dialer).DialContext(ctx, ipNetwork(config.IPFamily), net.JoinHostPort(config.Hostname, strconv.Itoa(port)))
+	if err == nil {
+		resolvedIP, _, _ = net.SplitHostPort(conn.RemoteAddr().String())
+		tlsConn := tls.Client(conn, tlsConfig)
+		handshakeStart := time.Now()
+		if err = tlsConn.HandshakeContext(ctx); err == nil {
+			handshake = time.Since(handshakeStart)
+			state = tlsConn.ConnectionState()
+		} else {
+			err = fmt.Errorf("%w: %v", errHandshake, err)
+		}
+		tlsConn.Close()
+	}
+	stop := time.Now()
+
+	result := newResult(monitor.ID, monitor.Name, start, stop)
+	result.SSLCheckResult = ssl
+	if err != nil {
+		message := err.Error()
+		ssl.RequestError = &message
+		ssl.FailureCategory = failureConnection
+		if errors.Is(err, errHandshake) {
+			ssl.FailureCategory = failureHandshake
+		}
+		result.HasErrors = true
+		result.HasFailures = true
+		return result, nil
+	}
+
+	leaf := state.PeerCertificates[0]
+	now := time.Now()
+	days := int(math.Floor(leaf.NotAfter.Sub(now).Hours() / 24))
+	_, chainErr := leaf.Verify(x509.VerifyOptions{
+		Roots:         tlsConfig.RootCAs,
+		Intermediates: intermediates(state.PeerCertificates),
+		CurrentTime:   now,
+	})
+	chainTrusted := chainErr == nil
+	hostnameVerified := leaf.VerifyHostname(tlsConfig.ServerName) == nil
+	handshakeMs := milliseconds(handshake)
+	resp := &checkly.SSLCheckResponse{
+		ResolvedIP:       resolvedIP,
+		Protocol:         tlsVersionName(state.Version),
+		CipherSuite:      tls.CipherSuiteName(state.CipherSuite),
+		HandshakeTimeMs:  handshakeMs,
+		HostnameVerified: hostnameVerified,
+		ChainTrusted:     chainTrusted,
+		DaysUntilExpiry:  days,
+		OCSPStapled:      len(state.OCSPResponse) > 0,
+		Certificate:      certificateDetails(leaf),
+	}
+	for _, c := range state.PeerCertificates[1:] {
+		resp.Chain = append(resp.Chain, map[string]any{
+			"subjectCN": c.Subject.CommonName,
+			"issuer":    issuerName(c),
+			"validTo":   c.NotAfter.UTC().Format(time.RFC3339),
+		})
+	}
+	verdict, baselineGrade, rules := gradeBaseline(baseline, state)
+	resp.SecurityBaseline = map[string]any{"verdict": verdict, "grade": baselineGrade, "rules": rules}
+	ssl.Response = resp
+	ssl.TLSVersion = resp.Protocol
+	ssl.CipherSuite = resp.CipherSuite
+	ssl.DaysUntilExpiry = &days
+	ssl.HandshakeTimeMs = &handshakeMs
+	ssl.ChainTrusted = &chainTrusted
+	ssl.HostnameVerified = &hostnameVerified
+	ssl.BaselineVerdict = verdict
+	ssl.BaselineGrade = baselineGrade
+
+	assertions := assert.Evaluate(monitor.Request.Assertions, assert.SSLSample(ssl))
+	ssl.Assertions = assertionResults(assertions)
+	grade(result, assertions, monitor.ShouldFail, monitor.DegradedResponseTime, monitor.MaxResponseTime)
+	switch {
+	case now.After(leaf.NotAfter):
+		ssl.FailureCategory = failureExpired
+	case config.AlertDaysBeforeExpiry > 0 && days < config.AlertDaysBeforeExpiry:
+		ssl.FailureCategory = failureExpiring
+	case !chainTrusted && !config.SkipChainValidation:
+		ssl.FailureCategory = failureUntrusted
+	case !hostnameVerified:
+		ssl.FailureCategory = failureHostname
+	case verdict == "FAIL":
+		ssl.FailureCategory = failureBaseline
+	case result.HasFailures:
+		ssl.FailureCategory = failureAssertion
+	}
+	if ssl.FailureCategory != "" {
+		result.HasFailures = true
+	}
+	return result, nil
+}
+
+var errHandshake = errors.New("TLS handshake failed")
+
+// sslClientConfig returns the TLS configuration of the handshake of an SSL
+// monitor. The chain and hostname are verified after the handshake, to report
+// on the certificates of servers which fail verification.
+func sslClientConfig(config checkly.SSLConfig, o options) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		ServerName:         config.Hostname,
+		InsecureSkipVerify: true,
+		RootCAs:            o.rootCAs,
+		// The baseline grades old versions rather than refusing them.
+		MinVersion: tls.VersionTLS10,
+	}
+	if config.ServerName != nil && *config.ServerName != "" {
+		tlsConfig.ServerName = *config.ServerName
+	}
+	if tlsConfig.RootCAs == nil {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			return nil, fmt.Errorf("dryrun: loading the system certificate authorities: %w", err)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	c := o.clientCertificate
+	if c == nil || (config.ClientCertificateMode != "explicit" && !strings.EqualFold(c.Host, config.Hostname)) {
+		return tlsConfig, nil
+	}
+	if c.Passphrase != "" {
+		return nil, fmt.Errorf("dryrun: client certificates with encrypted private keys are not supported")
+	}
+	certificate, err := tls.X509KeyPair([]byte(c.Certificate), []byte(c.PrivateKey))
+	if err != nil {
+		return nil, fmt.Errorf("dryrun: invalid client certificate: %w", err)
+	}
+	tlsConfig.Certificates = []tls.Certificate{certificate}
+	if c.TrustedCA != "" {
+		tlsConfig.RootCAs = tlsConfig.RootCAs.Clone()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM([]byte(c.TrustedCA)) {
+			return nil, fmt.Errorf("dryrun: invalid trusted CA bundle of the client certificate")
+		}
+	}
+	return tlsConfig, nil
+}
+
+func intermediates(certificates []*x509.Certificate) *x509.CertPool {
+	pool := x509.NewCertPool()
+	for _, c := range certificates[1:] {
+		pool.AddCert(c)
+	}
+	return pool
+}
+
+// tlsVersionName returns the name of a TLS version, like "TLSv1.3".
+func tlsVersionName(version uint16) string {
+	return strings.Replace(tls.VersionName(version), "TLS ", "TLSv", 1)
+}
+
+// parseTLSVersion returns the TLS version of a baseline rule, like "TLS1.2",
+// "TLSv1.2" or "TLS 1.2".
+func parseTLSVersion(s string) (uint16, error) {
+	normalized := strings.NewReplacer(" ", "", "v", "", "V", "").Replace(strings.ToUpper(s))
+	switch normalized {
+	case "TLS1.0", "TLS1":
+		return tls.VersionTLS10, nil
+	case "TLS1.1":
+		return tls.VersionTLS11, nil
+	case "TLS1.2":
+		return tls.VersionTLS12, nil
+	case "TLS1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("dryrun: invalid TLS version %q in the security baseline", s)
+	}
+}
+
+func issuerName(c *x509.Certificate) string {
+	if c.Issuer.CommonName != "" || len(c.Issuer.Organization) == 0 {
+		return c.Issuer.CommonName
+	}
+	return c.Issuer.Organization[0]
+}
+
+// keyType returns the type and size in bits of the public key of c.
+func keyType(c *x509.Certificate) (string, int) {
+	switch key := c.PublicKey.(type) {
+	case *rsa.PublicKey:
+		return "RSA", key.N.BitLen()
+	case *ecdsa.PublicKey:
+		return "EC", key.Curve.Params().BitSize
+	case ed25519.PublicKey:
+		return "Ed25519", 256
+	default:
+		return c.PublicKeyAlgorithm.String(), 0
+	}
+}
+
+// rsaEquivalentBits returns the size of an RSA key of the strength of a key
+// of type keyType with bits, following NIST SP 800-57.
+func rsaEquivalentBits(keyType string, bits int) int {
+	if keyType == "RSA" {
+		return bits
+	}
+	switch {
+	case bits >= 512:
+		return 15360
+	case bits >= 384:
+		return 7680
+	case bits >= 256:
+		return 3072
+	case bits >= 224:
+		return 2048
+	default:
+		return 1024
+	}
+}
+
+// certificateDetails returns the details of c in the format of SSL check
+// results.
+func certificateDetails(c *x509.Certificate) map[string]any {
+	keyType, bits := keyType(c)
+	fingerprint := sha256.Sum256(c.Raw)
+	return map[string]any{
+		"subjectCN":          c.Subject.CommonName,
+		"issuer":             issuerName(c),
+		"subjectAltNames":    subjectAltNames(c),
+		"serialNumber":       c.SerialNumber.Text(16),
+		"validFrom":          c.NotBefore.UTC().Format(time.RFC3339),
+		"validTo":            c.NotAfter.UTC().Format(time.RFC3339),
+		"keyType":            keyType,
+		"keySizeBits":        bits,
+		"signatureAlgorithm": c.SignatureAlgorithm.String(),
+		"fingerprintSha256":  hex.EncodeToString(fingerprint[:]),
+	}
+}
+
+func subjectAltNames(c *x509.Certificate) []string {
+	names := append([]string{}, c.DNSNames...)
+	for _, ip := range c.IPAddresses {
+		names = append(names, ip.String())
+	}
+	return names
+}
+
+// mergeBaseline returns the default baseline with the rules of override.
+func mergeBaseline(override *checkly.SecurityBaseline) (checkly.SecurityBaseline, error) {
+	b := defaultBaseline
+	if override == nil {
+		return b, nil
+	}
+	b.Enabled = override.Enabled
+	tlsRule := func(base, o *checkly.SSLBaselineTLSRule) *checkly.SSLBaselineTLSRule {
+		if o == nil {
+			return base
+		}
+		r := *base
+		if o.Value != "" {
+			r.Value = o.Value
+		}
+		if o.Severity != "" {
+			r.Severity = o.Severity
+		}
+		return &r
+	}
+	keySizeRule := func(base, o *checkly.SSLBaselineKeySizeRule) *checkly.SSLBaselineKeySizeRule {
+		if o == nil {
+			return base
+		}
+		r := *base
+		if o.Value != 0 {
+			r.Value = o.Value
+		}
+		if o.Severity != "" {
+			r.Severity = o.Severity
+		}
+		return &r
+	}
+	severityRule := func(base, o *checkly.SSLBaselineSeverityRule) *checkly.SSLBaselineSeverityRule {
+		if o == nil || o.Severity == "" {
+			return base
+		}
+		return o
+	}
+	b.MinTLSVersion = tlsRule(b.MinTLSVersion, override.MinTLSVersion)
+	b.RecommendedTLSVersion = tlsRule(b.RecommendedTLSVersion, override.RecommendedTLSVersion)
+	b.MinKeySizeBits = keySizeRule(b.MinKeySizeBits, override.MinKeySizeBits)
+	b.RecommendedKeySizeBits = keySizeRule(b.RecommendedKeySizeBits, override.RecommendedKeySizeBits)
+	b.WeakSignatureAlgorithm = severityRule(b.WeakSignatureAlgorithm, override.WeakSignatureAlgorithm)
+	b.WeakCipherSuite = severityRule(b.WeakCipherSuite, override.WeakCipherSuite)
+	b.KnownBadCA = severityRule(b.KnownBadCA, override.KnownBadCA)
+	b.OCSPMustStapleRespected = severityRule(b.OCSPMustStapleRespected, override.OCSPMustStapleRespected)
+	b.SCTPresent = severityRule(b.SCTPresent, override.SCTPresent)
+
+	for _, r := range []*checkly.SSLBaselineTLSRule{b.MinTLSVersion, b.RecommendedTLSVersion} {
+		if _, err := parseTLSVersion(r.Value); err != nil {
+			return b, err
+		}
+	}
+	for _, severity := range []string{
+		b.MinTLSVersion.Severity, b.RecommendedTLSVersion.Severity,
+		b.MinKeySizeBits.Severity, b.RecommendedKeySizeBits.Severity,
+		b.WeakSignatureAlgorithm.Severity, b.WeakCipherSuite.Severity, b.KnownBadCA.Severity,
+		b.OCSPMustStapleRespected.Severity, b.SCTPresent.Severity,
+	} {
+		if severity != severityFail && severity != severityWarn && severity != severityIgnore {
+			return b, fmt.Errorf("dryrun: invalid severity %q in the security baseline, use one of fail, warn, ignore", severity)
+		}
+	}
+	return b, nil
+}
+
+// baselineRule is the outcome of a rule of a security baseline.
+type baselineRule struct {
+	name     string
+	severity string
+	advisory bool
+	value    any
+	actual   any
+	passed   bool
+	skipped  bool
+}
+
+// gradeBaseline grades a connection against baseline, and returns its
+// verdict, grade and the outcome of each rule, see RunSSLMonitor.
+func gradeBaseline(baseline checkly.SecurityBaseline, state tls.ConnectionState) (string, string, []map[string]any) {
+	if baseline.Enabled != nil && !*baseline.Enabled {
+		return "", "", nil
+	}
+	leaf := state.PeerCertificates[0]
+	keyType, bits := keyType(leaf)
+	strength := rsaEquivalentBits(keyType, bits)
+	minVersion, _ := parseTLSVersion(baseline.MinTLSVersion.Value)
+	recommendedVersion, _ := parseTLSVersion(baseline.RecommendedTLSVersion.Value)
+	weakSignature := ""
+	signature := leaf.SignatureAlgorithm.String()
+	for _, c := range state.PeerCertificates {
+		if c != leaf && c.CheckSignatureFrom(c) == nil {
+			// The signatures of self-signed roots aren't verified.
+			continue
+		}
+		switch c.SignatureAlgorithm {
+		case x509.MD2WithRSA, x509.MD5WithRSA, x509.SHA1WithRSA, x509.DSAWithSHA1, x509.ECDSAWithSHA1:
+			weakSignature = c.SignatureAlgorithm.String()
+			signature = weakSignature
+		}
+	}
+	weakCipher := false
+	for _, suite := range tls.InsecureCipherSuites() {
+		weakCipher = weakCipher || suite.ID == state.CipherSuite
+	}
+	mustStaple := hasExtension(leaf, oidMustStaple)
+	sctPresent := len(state.SignedCertificateTimestamps) > 0 || hasExtension(leaf, oidSCTList)
+	version := tlsVersionName(state.Version)
+
+	rules := []baselineRule{
+		{name: "minTLSVersion", severity: baseline.MinTLSVersion.Severity, value: baseline.MinTLSVersion.Value,
+			actual: version, passed: state.Version >= minVersion},
+		{name: "minKeySizeBits", severity: baseline.MinKeySizeBits.Severity, value: baseline.MinKeySizeBits.Value,
+			actual: bits, passed: strength >= baseline.MinKeySizeBits.Value},
+		{name: "weakSignatureAlgorithm", severity: baseline.WeakSignatureAlgorithm.Severity,
+			actual: signature, passed: weakSignature == ""},
+		{name: "weakCipherSuite", severity: baseline.WeakCipherSuite.Severity,
+			actual: tls.CipherSuiteName(state.CipherSuite), passed: !weakCipher},
+		{name: "knownBadCA", severity: baseline.KnownBadCA.Severity, actual: issuerName(leaf), passed: true, skipped: true},
+		{name: "recommendedTLSVersion", severity: baseline.RecommendedTLSVersion.Severity, advisory: true,
+			value: baseline.RecommendedTLSVersion.Value, actual: version, passed: state.Version >= recommendedVersion},
+		{name: "recommendedKeySizeBits", severity: baseline.RecommendedKeySizeBits.Severity, advisory: true,
+			value: baseline.RecommendedKeySizeBits.Value, actual: bits, passed: strength >= baseline.RecommendedKeySizeBits.Value},
+		{name: "ocspMustStapleRespected", severity: baseline.OCSPMustStapleRespected.Severity, advisory: true,
+			actual: len(state.OCSPResponse) > 0, passed: !mustStaple || len(state.OCSPResponse) > 0},
+		{name: "sctPresent", severity: baseline.SCTPresent.Severity, advisory: true, actual: sctPresent, passed: sctPresent},
+	}
+
+	verdict := "PASS"
+	var enforceable, advisory int
+	list := make([]map[string]any, len(rules))
+	for i, r := range rules {
+		list[i] = map[string]any{"rule": r.name, "severity": r.severity, "actual": r.actual, "passed": r.passed}
+		if r.value != nil {
+			list[i]["value"] = r.value
+		}
+		if r.skipped {
+			list[i]["skipped"] = true
+		}
+		if r.passed || r.severity == severityIgnore {
+			continue
+		}
+		switch {
+		case r.severity == severityFail:
+			verdict = "FAIL"
+		case verdict == "PASS":
+			verdict = "WARN"
+		}
+		if r.advisory {
+			advisory++
+		} else {
+			enforceable++
+		}
+	}
+	grade := "A"
+	switch {
+	case enforceable > 1:
+		grade = "F"
+	case enforceable == 1:
+		grade = "C"
+	case advisory > 0:
+		grade = "B"
+	}
+	return verdict, grade, list
+}
+
+func hasExtension(c *x509.Certificate, oid asn1.ObjectIdentifier) bool {
+	for _, e := range c.Extensions {
+		if e.Id.Equal(oid) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,323 @@
+package dryrun_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"log"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	checkly "github.com/checkly/checkly-go-sdk"
+	"github.com/checkly/checkly-go-sdk/assert"
+	"github.com/checkly/checkly-go-sdk/dryrun"
+)
+
+// newCertificate returns a self-signed ECDSA P-256 certificate for
+// 127.0.0.1 and example.com valid until notAfter, in PEM format.
+func newCertificate(t *testing.T, notAfter time.Time) (certPEM, keyPEM []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(42),
+		Subject:               pkix.Name{CommonName: "example.com", Organization: []string{"Acme"}},
+		NotBefore:             notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		DNSNames:              []string{"example.com"},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+// tlsServer starts a TLS server with config, and returns it with its port.
+func tlsServer(t *testing.T, config *tls.Config) (*httptest.Server, int) {
+	t.Helper()
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Config.ErrorLog = log.New(io.Discard, "", 0)
+	server.TLS = config
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	u, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	port, err := strconv.Atoi(u.Port())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return server, port
+}
+
+func rootCAs(server *httptest.Server) dryrun.Option {
+	return dryrun.WithRootCAs(server.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs)
+}
+
+func sslMonitor(port int, assertions ...checkly.Assertion) checkly.SSLMonitor {
+	return checkly.SSLMonitor{
+		Name: "SSL monitor",
+		Request: checkly.SSLRequest{
+			SSLConfig:  checkly.SSLConfig{Hostname: "127.0.0.1", Port: port},
+			Assertions: assertions,
+		},
+	}
+}
+
+func TestRunSSLMonitor(t *testing.T) {
+	t.Parallel()
+	server, port := tlsServer(t, nil)
+	monitor := sslMonitor(port,
+		assert.Certificate("daysUntilExpiry").GreaterThan(14),
+		assert.Connection("tlsVersion").Equals("TLSv1.3"),
+		assert.JSONResponse("$.certificate.keySizeBits").GreaterThan(1024),
+		assert.JSONResponse("$.securityBaseline.verdict").Equals("PASS"),
+	)
+	result, err := dryrun.Run(context.Background(), &monitor, rootCAs(server))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ssl := result.SSLCheckResult
+	if result.HasFailures || result.HasErrors {
+		t.Fatalf("want the monitor to pass, got category %q, assertions %v", ssl.FailureCategory, ssl.Assertions)
+	}
+	if ssl.TLSVersion != "TLSv1.3" || ssl.CipherSuite == "" {
+		t.Errorf("unexpected connection %q %q", ssl.TLSVersion, ssl.CipherSuite)
+	}
+	if !*ssl.ChainTrusted || !*ssl.HostnameVerified {
+		t.Errorf("want a trusted chain and verified hostname, got %v %v", *ssl.ChainTrusted, *ssl.HostnameVerified)
+	}
+	if ssl.BaselineVerdict != "PASS" || ssl.BaselineGrade != "A" {
+		t.Errorf("want baseline PASS A, got %s %s", ssl.BaselineVerdict, ssl.BaselineGrade)
+	}
+	if ssl.Response.ResolvedIP != "127.0.0.1" || ssl.Response.Certificate["keyType"] != "RSA" {
+		t.Errorf("unexpected response %+v", ssl.Response)
+	}
+	if ssl.Response.DaysUntilExpiry != *ssl.DaysUntilExpiry || *ssl.DaysUntilExpiry < 365 {
+		t.Errorf("unexpected days until expiry %d", *ssl.DaysUntilExpiry)
+	}
+}
+
+func TestRunSSLMonitorBaseline(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	tls13, tls13Port := tlsServer(t, nil)
+	tls12, tls12Port := tlsServer(t, &tls.Config{MaxVersion: tls.VersionTLS12})
+	certPEM, keyPEM := newCertificate(t, time.Now().Add(90*24*time.Hour))
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecdsa, ecdsaPort := tlsServer(t, &tls.Config{Certificates: []tls.Certificate{cert}})
+
+	tests := []struct {
+		name     string
+		server   *httptest.Server
+		port     int
+		baseline *checkly.SecurityBaseline
+		verdict  string
+		grade    string
+		failure  string
+	}{
+		{"TLS 1.2 passes the default baseline", tls12, tls12Port, nil, "PASS", "A", ""},
+		{"minimum TLS version", tls12, tls12Port, &checkly.SecurityBaseline{
+			MinTLSVersion: &checkly.SSLBaselineTLSRule{Value: "TLSv1.3"},
+		}, "FAIL", "C", "baseline"},
+		{"minimum TLS version warning", tls12, tls12Port, &checkly.SecurityBaseline{
+			MinTLSVersion: &checkly.SSLBaselineTLSRule{Value: "TLS1.3", Severity: "warn"},
+		}, "WARN", "C", ""},
+		{"recommended TLS version", tls12, tls12Port, &checkly.SecurityBaseline{
+			RecommendedTLSVersion: &checkly.SSLBaselineTLSRule{Severity: "warn"},
+		}, "WARN", "B", ""},
+		{"minimum key size", tls13, tls13Port, &checkly.SecurityBaseline{
+			MinKeySizeBits:         &checkly.SSLBaselineKeySizeRule{Value: 4096},
+			RecommendedKeySizeBits: &checkly.SSLBaselineKeySizeRule{Value: 8192, Severity: "fail"},
+		}, "FAIL", "C", "baseline"},
+		{"several enforceable rules", tls12, tls12Port, &checkly.SecurityBaseline{
+			MinTLSVersion:  &checkly.SSLBaselineTLSRule{Value: "TLS1.3"},
+			MinKeySizeBits: &checkly.SSLBaselineKeySizeRule{Value: 4096},
+		}, "FAIL", "F", "baseline"},
+		{"equivalent EC key size", ecdsa, ecdsaPort, &checkly.SecurityBaseline{
+			RecommendedKeySizeBits: &checkly.SSLBaselineKeySizeRule{Severity: "fail"},
+		}, "PASS", "A", ""},
+		{"disabled baseline", tls12, tls12Port, &checkly.SecurityBaseline{
+			Enabled:       new(bool),
+			MinTLSVersion: &checkly.SSLBaselineTLSRule{Value: "TLS1.3"},
+		}, "", "", ""},
+	}
+	for _, tc := range tests {
+		monitor := sslMonitor(tc.port)
+		monitor.Request.SSLConfig.SecurityBaseline = tc.baseline
+		result, err := dryrun.RunSSLMonitor(ctx, monitor, rootCAs(tc.server))
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		ssl := result.SSLCheckResult
+		if ssl.BaselineVerdict != tc.verdict || ssl.BaselineGrade != tc.grade {
+			t.Errorf("%s: want baseline %q %q, got %q %q: %v", tc.name, tc.verdict, tc.grade,
+				ssl.BaselineVerdict, ssl.BaselineGrade, ssl.Response.SecurityBaseline["rules"])
+		}
+		if ssl.FailureCategory != tc.failure || result.HasFailures != (tc.failure != "") {
+			t.Errorf("%s: want failure %q, got %q", tc.name, tc.failure, ssl.FailureCategory)
+		}
+	}
+}
+
+func TestRunSSLMonitorFailures(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	server, port := tlsServer(t, nil)
+	certPEM, keyPEM := newCertificate(t, time.Now().Add(-48*time.Hour))
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expired, expiredPort := tlsServer(t, &tls.Config{Certificates: []tls.Certificate{cert}})
+
+	monitor := sslMonitor(port)
+	result, err := dryrun.RunSSLMonitor(ctx, monitor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := result.SSLCheckResult.FailureCategory; got != "untrusted" || !result.HasFailures {
+		t.Errorf("want an untrusted certificate, got %q", got)
+	}
+	monitor.Request.SSLConfig.SkipChainValidation = true
+	result, err = dryrun.RunSSLMonitor(ctx, monitor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.HasFailures {
+		t.Errorf("want the chain validation to be skipped, got %q", result.SSLCheckResult.FailureCategory)
+	}
+
+	monitor = sslMonitor(port)
+	serverName := "example.com"
+	monitor.Request.SSLConfig.ServerName = &serverName
+	result, err = dryrun.RunSSLMonitor(ctx, monitor, rootCAs(server))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.HasFailures || !*result.SSLCheckResult.HostnameVerified {
+		t.Errorf("want the server name to be verified, got %q", result.SSLCheckResult.FailureCategory)
+	}
+	serverName = "checklyhq.com"
+	result, err = dryrun.RunSSLMonitor(ctx, monitor, rootCAs(server))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := result.SSLCheckResult.FailureCategory; got != "hostname" {
+		t.Errorf("want a hostname mismatch, got %q", got)
+	}
+
+	monitor = sslMonitor(port, assert.Certificate("daysUntilExpiry").GreaterThan(1000000))
+	result, err = dryrun.RunSSLMonitor(ctx, monitor, rootCAs(server))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := result.SSLCheckResult.FailureCategory; got != "assertion" {
+		t.Errorf("want a failed assertion, got %q", got)
+	}
+
+	monitor = sslMonitor(port)
+	monitor.Request.SSLConfig.AlertDaysBeforeExpiry = 1000000
+	result, err = dryrun.RunSSLMonitor(ctx, monitor, rootCAs(server))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := result.SSLCheckResult.FailureCategory; got != "expiring" {
+		t.Errorf("want an expiring certificate, got %q", got)
+	}
+
+	result, err = dryrun.RunSSLMonitor(ctx, sslMonitor(expiredPort), rootCAs(expired))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := result.SSLCheckResult.FailureCategory; got != "expired" || *result.SSLCheckResult.DaysUntilExpiry >= 0 {
+		t.Errorf("want an expired certificate, got %q, %d days", got, *result.SSLCheckResult.DaysUntilExpiry)
+	}
+
+	l, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closed := l.Addr().(*net.TCPAddr).Port
+	l.Close()
+	result, err = dryrun.RunSSLMonitor(ctx, sslMonitor(closed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := result.SSLCheckResult.FailureCategory; got != "connection" || !result.HasErrors {
+		t.Errorf("want a connection error, got %q", got)
+	}
+
+	monitor = sslMonitor(port)
+	monitor.Request.SSLConfig.SecurityBaseline = &checkly.SecurityBaseline{
+		WeakCipherSuite: &checkly.SSLBaselineSeverityRule{Severity: "critical"},
+	}
+	if _, err := dryrun.RunSSLMonitor(ctx, monitor); err == nil {
+		t.Error("want an error for an invalid severity")
+	}
+}
+
+func TestRunSSLMonitorClientCertificate(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	certPEM, keyPEM := newCertificate(t, time.Now().Add(24*time.Hour))
+	clients := x509.NewCertPool()
+	clients.AppendCertsFromPEM(certPEM)
+	// Servers reject missing client certificates after the handshake of the
+	// client in TLS 1.3, so the handshake only fails in TLS 1.2.
+	server, port := tlsServer(t, &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  clients,
+		MaxVersion: tls.VersionTLS12,
+	})
+	certificate := checkly.ClientCertificate{Host: "127.0.0.1", Certificate: string(certPEM), PrivateKey: string(keyPEM)}
+
+	result, err := dryrun.RunSSLMonitor(ctx, sslMonitor(port), rootCAs(server))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := result.SSLCheckResult.FailureCategory; got != "handshake" {
+		t.Errorf("want a handshake error without client certificate, got %q", got)
+	}
+
+	for _, mode := range []string{"explicit", "auto"} {
+		monitor := sslMonitor(port)
+		monitor.Request.SSLConfig.ClientCertificateMode = mode
+		result, err = dryrun.RunSSLMonitor(ctx, monitor, rootCAs(server), dryrun.WithClientCertificate(certificate))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.HasFailures {
+			t.Errorf("%s: want the client certificate to be presented, got %q", mode, result.SSLCheckResult.FailureCategory)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package dryrun
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	checkly "github.com/checkly/checkly-go-sdk"
+	"github.com/checkly/checkly-go-sdk/assert"
+)
+
+// tcpIdleTimeout ends reading the reply to a TCP monitor once no more data
+// arrived for this long, for servers which keep the connection open.
+const tcpIdleTimeout = 250 * time.Millisecond
+
+// RunTCPMonitor connects to the host and port of a TCP monitor, writes its
+// data and evaluates its assertions on the reply.
+//
+// The reply is read until the server closes the connection, no more data
+// arrives for a moment or the timeout of the run expires, see WithTimeout.
+// Without data to write, the connection is closed as soon as it is
+// established. The response time is the time from connecting to receiving
+// the last byte of the reply, without the wait for more data. The check fails if an assertion fails, or the response
+// time exceeds MaxResponseTime, and is degraded if it exceeds
+// DegradedResponseTime.
+//
+// Check results have no field for the details of TCP monitors, which are in
+// the ApiCheckResult of the result: the "request", the "response" with the
+// "data" received and the "timingPhases", and the "assertions".
+func RunTCPMonitor(ctx context.Context, monitor checkly.TCPMonitor, opts ...Option) (*checkly.CheckResult, error) {
+	o := newOptions(opts)
+	request := monitor.Request
+	if request.Hostname == "" || request.Port == 0 {
+		return nil, fmt.Errorf("dryrun: the hostname and port of TCP monitors are required")
+	}
+	addr := net.JoinHostPort(request.Hostname, strconv.Itoa(int(request.Port)))
+	ctx, cancel := context.WithTimeout(ctx, o.timeout)
+	defer cancel()
+
+	var data []byte
+	var connected, stop time.Time
+	start := time.Now()
+	conn, err := new(net.Dialer).DialContext(ctx, ipNetwork(request.IPFamily), addr)
+	if err == nil {
+		connected = time.Now()
+		data, stop, err = exchange(ctx, conn, request.Data)
+		conn.Close()
+	}
+	if err != nil {
+		stop = time.Now()
+	}
+
+	result := newResult(monitor.ID, monitor.Name, start, stop)
+	tcpResult := checkly.ApiCheckResult{"request": map[string]any{
+		"hostname": request.Hostname,
+		"port":     request.Port,
+		"data":     request.Data,
+		"ipFamily": request.IPFamily,
+	}}
+	result.ApiCheckResult = &tcpResult
+	if err != nil {
+		result.HasErrors = true
+		result.HasFailures = true
+		tcpResult["requestError"] = err.Error()
+		return result, nil
+	}
+	assertions := assert.Evaluate(request.Assertions, assert.TCPSample(string(data), stop.Sub(start)))
+	grade(result, assertions, monitor.ShouldFail, monitor.DegradedResponseTime, monitor.MaxResponseTime)
+	tcpResult["response"] = map[string]any{
+		"data": string(data),
+		"timingPhases": map[string]any{
+			"connect": milliseconds(connected.Sub(start)),
+			"total":   milliseconds(stop.Sub(start)),
+		},
+	}
+	tcpResult["assertions"] = assertionResults(assertions)
+	return result, nil
+}
+
+// exchange writes data to conn and returns the reply and the time its last
+// byte arrived, or the server closed the connection without a reply. See
+// RunTCPMonitor.
+func exchange(ctx context.Context, conn net.Conn, data string) ([]byte, time.Time, error) {
+	if data == "" {
+		return nil, time.Now(), nil
+	}
+	deadline, _ := ctx.Deadline()
+	conn.SetDeadline(deadline)
+	if _, err := io.WriteString(conn, data); err != nil {
+		return nil, time.Time{}, err
+	}
+	var reply []byte
+	var last time.Time
+	buf := make([]byte, 4096)
+	for {
+		n, err := conn.Read(buf)
+		now := time.Now()
+		reply = append(reply, buf[:n]...)
+		if n > 0 {
+			last = now
+		}
+		switch {
+		case errors.Is(err, io.EOF):
+			if last.IsZero() {
+				last = now
+			}
+			return reply, last, nil
+		case errors.Is(err, os.ErrDeadlineExceeded):
+			if now.Before(deadline) {
+				// No more data arrived in time.
+				return reply, last, nil
+			}
+			return reply, last, fmt.Errorf("reading the reply: %w", err)
+		case err != nil:
+			return reply, last, err
+		}
+		if n > 0 {
+			conn.SetReadDeadline(earliest(deadline, now.Add(tcpIdleTimeout)))
+		}
+	}
+}
+
+func earliest(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,191 @@
+package dryrun_test
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	checkly "github.com/checkly/checkly-go-sdk"
+	"github.com/checkly/checkly-go-sdk/assert"
+	"github.com/checkly/checkly-go-sdk/dryrun"
+)
+
+// tcpServer serves handle on a loopback port, and returns the port.
+func tcpServer(t *testing.T, handle func(net.Conn)) uint16 {
+	t.Helper()
+	l, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				handle(conn)
+			}()
+		}
+	}()
+	return uint16(l.Addr().(*net.TCPAddr).Port)
+}
+
+func tcpMonitor(port uint16, data string, assertions ...checkly.Assertion) checkly.TCPMonitor {
+	return checkly.TCPMonitor{
+		Name: "TCP monitor",
+		Request: checkly.TCPRequest{
+			Hostname:   "127.0.0.1",
+			Port:       port,
+			Data:       data,
+			Assertions: assertions,
+		},
+	}
+}
+
+func tcpResponse(t *testing.T, result *checkly.CheckResult) map[string]any {
+	t.Helper()
+	response, ok := (*result.ApiCheckResult)["response"].(map[string]any)
+	if !ok {
+		t.Fatalf("no response in %v", *result.ApiCheckResult)
+	}
+	return response
+}
+
+func TestRunTCPMonitor(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	// The server replies to each line, and keeps the connection open.
+	open := tcpServer(t, func(conn net.Conn) {
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			conn.Write([]byte("PONG " + scanner.Text() + "\n"))
+		}
+	})
+	// The server greets, and closes the connection after the first line.
+	greeting := tcpServer(t, func(conn net.Conn) {
+		conn.Write([]byte("220 ready\r\n"))
+		bufio.NewReader(conn).ReadString('\n')
+	})
+
+	tests := []struct {
+		name     string
+		monitor  checkly.TCPMonitor
+		data     string
+		failures bool
+	}{
+		{
+			"open connection",
+			tcpMonitor(open, "PING 1\n", assert.ResponseData().Equals("PONG PING 1\n"), assert.ResponseTime().LessThan(5000)),
+			"PONG PING 1\n",
+			false,
+		},
+		{
+			"closed connection",
+			tcpMonitor(greeting, "EHLO\r\n", assert.ResponseData().Contains("220")),
+			"220 ready\r\n",
+			false,
+		},
+		{
+			"failed assertion",
+			tcpMonitor(open, "PING 2\n", checkly.Assertion{Source: "RESPONSE_DATA", Property: `PONG PING (\d)`, Comparison: "EQUALS", Target: "3"}),
+			"PONG PING 2\n",
+			true,
+		},
+		{
+			"connect only",
+			tcpMonitor(open, "", assert.ResponseTime().LessThan(5000)),
+			"",
+			false,
+		},
+	}
+	for _, tc := range tests {
+		result, err := dryrun.Run(ctx, &tc.monitor)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if result.HasErrors || result.HasFailures != tc.failures {
+			t.Errorf("%s: want failures %v, got %+v", tc.name, tc.failures, *result.ApiCheckResult)
+			continue
+		}
+		response := tcpResponse(t, result)
+		if response["data"] != tc.data {
+			t.Errorf("%s: want data %q, got %q", tc.name, tc.data, response["data"])
+		}
+		phases := response["timingPhases"].(map[string]any)
+		if phases["connect"].(float64) > phases["total"].(float64) {
+			t.Errorf("%s: connect time exceeds total time in %v", tc.name, phases)
+		}
+	}
+}
+
+func TestRunTCPMonitorResponseTime(t *testing.T) {
+	t.Parallel()
+	// The server replies at once and keeps the connection open, so reading
+	// ends with the idle timeout, which isn't part of the response time.
+	open := tcpServer(t, func(conn net.Conn) {
+		bufio.NewReader(conn).ReadString('\n')
+		conn.Write([]byte("pong"))
+		time.Sleep(time.Second)
+	})
+	monitor := tcpMonitor(open, "ping\n", assert.ResponseTime().LessThan(100))
+	monitor.MaxResponseTime = 100
+	result, err := dryrun.RunTCPMonitor(context.Background(), monitor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.HasFailures || result.OverMaxResponseTime || result.ResponseTime >= 100 {
+		t.Errorf("want a response time under 100ms, got %dms with %+v", result.ResponseTime, *result.ApiCheckResult)
+	}
+	total := tcpResponse(t, result)["timingPhases"].(map[string]any)["total"].(float64)
+	if total >= 100 {
+		t.Errorf("want a total time under 100ms, got %vms", total)
+	}
+}
+
+func TestRunTCPMonitorErrors(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	silent := tcpServer(t, func(conn net.Conn) {
+		time.Sleep(time.Second)
+	})
+	result, err := dryrun.RunTCPMonitor(ctx, tcpMonitor(silent, "PING\n"), dryrun.WithTimeout(100*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.HasErrors {
+		t.Error("want an error for a server which doesn't reply in time")
+	}
+
+	l, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closed := uint16(l.Addr().(*net.TCPAddr).Port)
+	l.Close()
+	result, err = dryrun.RunTCPMonitor(ctx, tcpMonitor(closed, "PING\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.HasErrors || !result.HasFailures {
+		t.Error("want an error for a closed port")
+	}
+
+	monitor := tcpMonitor(silent, "")
+	monitor.Request.IPFamily = "IPv6"
+	result, err = dryrun.RunTCPMonitor(ctx, monitor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.HasErrors {
+		t.Error("want an error connecting to an IPv4 address over IPv6")
+	}
+
+	if _, err := dryrun.RunTCPMonitor(ctx, tcpMonitor(0, "PING\n")); err == nil {
+		t.Error("want an error for a monitor without port")
+	}
+}
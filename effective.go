@@ -0,0 +1,363 @@
+package checkly
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Provenance is where the effective value of a setting of a check comes
+// from, see ResolveEffective.
+type Provenance string
+
+const (
+	// ProvenanceCheck is a setting of the check itself.
+	ProvenanceCheck Provenance = "check"
+	// ProvenanceGroup is a setting inherited from the group of the check.
+	ProvenanceGroup Provenance = "group"
+	// ProvenanceAccount is an environment variable of the account, or a
+	// default of the account, like its runtime or global alert settings.
+	ProvenanceAccount Provenance = "account"
+)
+
+// EffectiveCheck is the configuration a check runs with, see
+// ResolveEffective.
+type EffectiveCheck struct {
+	// Check is the check with the settings it inherits applied.
+	Check Check
+	// Sources are the provenances of the settings, by their path with the
+	// JSON names of the fields, like "runtimeId", "environmentVariables.KEY",
+	// "request.headers.Accept" or "request.assertions.0".
+	Sources map[string]Provenance
+}
+
+// String lists the provenances of the settings as "path: provenance" lines,
+// sorted by path.
+func (e EffectiveCheck) String() string {
+	paths := make([]string, 0, len(e.Sources))
+	for path := range e.Sources {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	var b strings.Builder
+	for _, path := range paths {
+		fmt.Fprintf(&b, "%s: %s\n", path, e.Sources[path])
+	}
+	return b.String()
+}
+
+// ResolveEffective returns the configuration check runs with as a check of
+// group, with the environment variables accountVars of the account, and the
+// provenance of each setting it inherits. A nil group resolves a check which
+// isn't in a group; the group type can't be inferred from nil, so it is given
+// explicitly:
+//
+//	effective := checkly.ResolveEffective[checkly.GroupV2](check, nil, accountVars)
+//
+// The settings the group defines override those of the check: its locations
+// and private locations, run parallel, retry strategy and alert channel
+// subscriptions, and its alert settings, which are the global ones of the
+// account if the group uses those. A deactivated group deactivates the check
+// and a muted group mutes it. The runtime and the setup and teardown scripts
+// or snippets of the check take precedence over those of the group, and
+// environment variables of the check over those of the group and then the
+// account. The API check defaults of the group are added to the request of
+// the check: headers and query parameters the check doesn't set, the
+// assertions of the group after those of the check, and basic auth if the
+// check has none. The base URL of the group is added to its environment
+// variables as GROUP_BASE_URL, which requests use as {{GROUP_BASE_URL}}.
+//
+// A nil runtime or retry strategy of the effective check, or global alert
+// settings, are the defaults of the account.
+func ResolveEffective[G Group | GroupV2](check Check, group *G, accountVars []EnvironmentVariable) EffectiveCheck {
+	r := resolver{check: check, sources: map[string]Provenance{}}
+	var g *groupSettings
+	switch group := any(group).(type) {
+	case *Group:
+		if group != nil {
+			g = groupSettingsOf(*group)
+		}
+	case *GroupV2:
+		if group != nil {
+			g = groupV2SettingsOf(*group)
+		}
+	}
+	r.resolve(g, accountVars)
+	return EffectiveCheck{Check: r.check, Sources: r.sources}
+}
+
+// groupSettings are the settings checks inherit from Group and GroupV2, with
+// nil for those the group doesn't set.
+type groupSettings struct {
+	activated                 bool
+	muted                     bool
+	runParallel               *bool
+	locations                 []string
+	privateLocations          *[]string
+	runtimeID                 *string
+	environmentVariables      []EnvironmentVariable
+	useGlobalAlertSettings    *bool
+	alertSettings             *AlertSettings
+	alertChannelSubscriptions []AlertChannelSubscription
+	retryStrategy             *RetryStrategy
+	setupSnippetID            int64
+	tearDownSnippetID         int64
+	localSetupScript          string
+	localTearDownScript       string
+	apiCheckDefaults          APICheckDefaults
+}
+
+func groupSettingsOf(g Group) *groupSettings {
+	return &groupSettings{
+		activated:                 g.Activated,
+		muted:                     g.Muted,
+		runParallel:               &g.RunParallel,
+		locations:                 g.Locations,
+		privateLocations:          g.PrivateLocations,
+		runtimeID:                 g.RuntimeID,
+		environmentVariables:      g.EnvironmentVariables,
+		useGlobalAlertSettings:    &g.UseGlobalAlertSettings,
+		alertSettings:             &g.AlertSettings,
+		alertChannelSubscriptions: g.AlertChannelSubscriptions,
+		retryStrategy:             g.RetryStrategy,
+		setupSnippetID:            g.SetupSnippetID,
+		tearDownSnippetID:         g.TearDownSnippetID,
+		localSetupScript:          g.LocalSetupScript,
+		localTearDownScript:       g.LocalTearDownScript,
+		apiCheckDefaults:          g.APICheckDefaults,
+	}
+}
+
+func groupV2SettingsOf(g GroupV2) *groupSettings {
+	s := &groupSettings{
+		activated:                 g.Activated,
+		muted:                     g.Muted,
+		runParallel:               g.RunParallel,
+		locations:                 g.Locations,
+		privateLocations:          g.PrivateLocations,
+		runtimeID:                 g.RuntimeID,
+		environmentVariables:      g.EnvironmentVariables,
+		useGlobalAlertSettings:    g.UseGlobalAlertSettings,
+		alertSettings:             g.AlertSettings,
+		alertChannelSubscriptions: g.AlertChannelSubscriptions,
+		retryStrategy:             g.RetryStrategy,
+		apiCheckDefaults:          g.APICheckDefaults,
+	}
+	if g.SetupSnippetID != nil {
+		s.setupSnippetID = *g.SetupSnippetID
+	}
+	if g.TearDownSnippetID != nil {
+		s.tearDownSnippetID = *g.TearDownSnippetID
+	}
+	if g.LocalSetupScript != nil {
+		s.localSetupScript = *g.LocalSetupScript
+	}
+	if g.LocalTearDownScript != nil {
+		s.localTearDownScript = *g.LocalTearDownScript
+	}
+	return s
+}
+
+// groupBaseURL is the environment variable holding the base URL of the API
+// check defaults of a group.
+const groupBaseURL = "GROUP_BASE_URL"
+
+// resolver applies the settings of a group and account to check, recording
+// their provenance in sources.
+type resolver struct {
+	check   Check
+	sources map[string]Provenance
+}
+
+func (r *resolver) resolve(g *groupSettings, accountVars []EnvironmentVariable) {
+	if g == nil {
+		g = &groupSettings{activated: true}
+	}
+	c := &r.check
+
+	r.set(ProvenanceCheck, "activated", "muted", "runParallel")
+	if !g.activated {
+		c.Activated = false
+		r.set(ProvenanceGroup, "activated")
+	}
+	if g.muted {
+		c.Muted = true
+		r.set(ProvenanceGroup, "muted")
+	}
+	if g.runParallel != nil {
+		c.RunParallel = *g.runParallel
+		r.set(ProvenanceGroup, "runParallel")
+	}
+
+	r.set(ProvenanceCheck, "locations", "privateLocations")
+	if len(g.locations) > 0 || (g.privateLocations != nil && len(*g.privateLocations) > 0) {
+		c.Locations, c.PrivateLocations = g.locations, g.privateLocations
+		r.set(ProvenanceGroup, "locations", "privateLocations")
+	}
+
+	switch {
+	case c.RuntimeID != nil:
+		r.set(ProvenanceCheck, "runtimeId")
+	case g.runtimeID != nil:
+		c.RuntimeID = g.runtimeID
+		r.set(ProvenanceGroup, "runtimeId")
+	default:
+		r.set(ProvenanceAccount, "runtimeId")
+	}
+
+	switch {
+	case g.retryStrategy != nil:
+		c.RetryStrategy = g.retryStrategy
+		r.set(ProvenanceGroup, "retryStrategy")
+	case c.RetryStrategy != nil:
+		r.set(ProvenanceCheck, "retryStrategy")
+	default:
+		r.set(ProvenanceAccount, "retryStrategy")
+	}
+
+	r.resolveAlerts(g)
+	r.resolveScripts(g)
+	groupVars := g.environmentVariables
+	if g.apiCheckDefaults.BaseURL != "" {
+		groupVars = append(groupVars[:len(groupVars):len(groupVars)],
+			EnvironmentVariable{Key: groupBaseURL, Value: g.apiCheckDefaults.BaseURL})
+	}
+	r.resolveVariables(groupVars, accountVars)
+	r.resolveRequest(g.apiCheckDefaults)
+}
+
+// resolveAlerts applies the alert settings and alert channel subscriptions
+// of the group.
+func (r *resolver) resolveAlerts(g *groupSettings) {
+	c := &r.check
+	from := ProvenanceCheck
+	switch {
+	case g.useGlobalAlertSettings != nil && *g.useGlobalAlertSettings:
+		c.UseGlobalAlertSettings = true
+		from = ProvenanceGroup
+	case g.useGlobalAlertSettings != nil || g.alertSettings != nil:
+		c.UseGlobalAlertSettings = false
+		if g.alertSettings != nil {
+			c.AlertSettings = *g.alertSettings
+		}
+		from = ProvenanceGroup
+	}
+	r.set(from, "useGlobalAlertSettings")
+	if c.UseGlobalAlertSettings {
+		r.set(ProvenanceAccount, "alertSettings")
+	} else {
+		r.set(from, "alertSettings")
+	}
+
+	r.set(ProvenanceCheck, "alertChannelSubscriptions")
+	if len(g.alertChannelSubscriptions) > 0 {
+		c.AlertChannelSubscriptions = g.alertChannelSubscriptions
+		r.set(ProvenanceGroup, "alertChannelSubscriptions")
+	}
+}
+
+// resolveScripts applies the setup and teardown of the group, for checks
+// which have none. A setup or teardown is either a snippet or a script.
+func (r *resolver) resolveScripts(g *groupSettings) {
+	c := &r.check
+	from := ProvenanceCheck
+	if c.SetupSnippetID == 0 && c.LocalSetupScript == "" && (g.setupSnippetID != 0 || g.localSetupScript != "") {
+		c.SetupSnippetID, c.LocalSetupScript = g.setupSnippetID, g.localSetupScript
+		from = ProvenanceGroup
+	}
+	r.set(from, "setupSnippetId", "localSetupScript")
+
+	from = ProvenanceCheck
+	if c.TearDownSnippetID == 0 && c.LocalTearDownScript == "" && (g.tearDownSnippetID != 0 || g.localTearDownScript != "") {
+		c.TearDownSnippetID, c.LocalTearDownScript = g.tearDownSnippetID, g.localTearDownScript
+		from = ProvenanceGroup
+	}
+	r.set(from, "tearDownSnippetId", "localTearDownScript")
+}
+
+// resolveVariables merges the environment variables of the account, the
+// group and the check, in this order, without those overridden by a later
+// one with the same key.
+func (r *resolver) resolveVariables(group, account []EnvironmentVariable) {
+	layers := []struct {
+		from      Provenance
+		variables []EnvironmentVariable
+	}{
+		{ProvenanceAccount, account},
+		{ProvenanceGroup, group},
+		{ProvenanceCheck, r.check.EnvironmentVariables},
+	}
+	index := map[string]int{}
+	var merged []EnvironmentVariable
+	for _, layer := range layers {
+		for _, v := range layer.variables {
+			if i, ok := index[v.Key]; ok {
+				merged[i] = v
+			} else {
+				index[v.Key] = len(merged)
+				merged = append(merged, v)
+			}
+			r.set(layer.from, "environmentVariables."+v.Key)
+		}
+	}
+	r.check.EnvironmentVariables = merged
+}
+
+// resolveRequest adds the API check defaults of the group to the request of
+// the check.
+func (r *resolver) resolveRequest(defaults APICheckDefaults) {
+	request := &r.check.Request
+	request.Headers = r.mergeKeyValues("request.headers.", defaults.Headers, request.Headers, http.CanonicalHeaderKey)
+	request.QueryParameters = r.mergeKeyValues("request.queryParameters.", defaults.QueryParameters, request.QueryParameters, nil)
+
+	for i := range request.Assertions {
+		r.set(ProvenanceCheck, fmt.Sprintf("request.assertions.%d", i))
+	}
+	if len(defaults.Assertions) > 0 {
+		for i := range defaults.Assertions {
+			r.set(ProvenanceGroup, fmt.Sprintf("request.assertions.%d", len(request.Assertions)+i))
+		}
+		assertions := append([]Assertion{}, request.Assertions...)
+		request.Assertions = append(assertions, defaults.Assertions...)
+	}
+
+	switch {
+	case request.BasicAuth != nil && request.BasicAuth.Username != "":
+		r.set(ProvenanceCheck, "request.basicAuth")
+	case defaults.BasicAuth.Username != "":
+		auth := defaults.BasicAuth
+		request.BasicAuth = &auth
+		r.set(ProvenanceGroup, "request.basicAuth")
+	}
+}
+
+// mergeKeyValues returns defaults and values, without the defaults whose key
+// is also in values. Keys are compared in the form canonical returns, if
+// any.
+func (r *resolver) mergeKeyValues(prefix string, defaults, values []KeyValue, canonical func(string) string) []KeyValue {
+	if canonical == nil {
+		canonical = func(s string) string { return s }
+	}
+	keys := map[string]bool{}
+	for _, kv := range values {
+		keys[canonical(kv.Key)] = true
+	}
+	var merged []KeyValue
+	for _, kv := range defaults {
+		if !keys[canonical(kv.Key)] {
+			merged = append(merged, kv)
+			r.set(ProvenanceGroup, prefix+canonical(kv.Key))
+		}
+	}
+	for _, kv := range values {
+		r.set(ProvenanceCheck, prefix+canonical(kv.Key))
+	}
+	return append(merged, values...)
+}
+
+func (r *resolver) set(from Provenance, paths ...string) {
+	for _, path := range paths {
+		r.sources[path] = from
+	}
+}
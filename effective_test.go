@@ -0,0 +1,197 @@
+package checkly_test
+
+import (
+	"strings"
+	"testing"
+
+	checkly "github.com/checkly/checkly-go-sdk"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestResolveEffective(t *testing.T) {
+	t.Parallel()
+	runtime, groupRuntime := "2023.09", "2024.02"
+	yes := true
+	check := checkly.Check{
+		Name:                   "API check",
+		Type:                   checkly.TypeAPI,
+		Activated:              true,
+		Locations:              []string{"eu-west-1"},
+		RuntimeID:              &runtime,
+		UseGlobalAlertSettings: true,
+		LocalSetupScript:       "setup()",
+		RetryStrategy:          &checkly.RetryStrategy{Type: "LINEAR", MaxRetries: 1},
+		EnvironmentVariables: []checkly.EnvironmentVariable{
+			{Key: "TOKEN", Value: "check"},
+		},
+		Request: checkly.Request{
+			Method: "GET",
+			URL:    "{{GROUP_BASE_URL}}/users",
+			Headers: []checkly.KeyValue{
+				{Key: "accept", Value: "text/plain"},
+			},
+			Assertions: []checkly.Assertion{
+				{Source: checkly.StatusCode, Comparison: checkly.Equals, Target: "200"},
+			},
+		},
+	}
+	group := checkly.GroupV2{
+		Name:                   "Group",
+		Activated:              true,
+		Muted:                  true,
+		RunParallel:            &yes,
+		Locations:              []string{"us-east-1", "ap-south-1"},
+		RuntimeID:              &groupRuntime,
+		UseGlobalAlertSettings: new(bool),
+		AlertSettings:          &checkly.AlertSettings{EscalationType: checkly.RunBased},
+		RetryStrategy:          &checkly.RetryStrategy{Type: "FIXED", MaxRetries: 2},
+		TearDownSnippetID:      new(int64),
+		EnvironmentVariables: []checkly.EnvironmentVariable{
+			{Key: "TOKEN", Value: "group"},
+			{Key: "REGION", Value: "us"},
+		},
+		APICheckDefaults: checkly.APICheckDefaults{
+			BaseURL: "https://api.example.com",
+			Headers: []checkly.KeyValue{
+				{Key: "Accept", Value: "application/json"},
+				{Key: "X-Team", Value: "core"},
+			},
+			QueryParameters: []checkly.KeyValue{{Key: "page", Value: "1"}},
+			Assertions: []checkly.Assertion{
+				{Source: checkly.ResponseTime, Comparison: checkly.LessThan, Target: "500"},
+			},
+			BasicAuth: checkly.BasicAuth{Username: "user", Password: "{{PASSWORD}}"},
+		},
+	}
+	*group.TearDownSnippetID = 42
+	account := []checkly.EnvironmentVariable{
+		{Key: "TOKEN", Value: "account"},
+		{Key: "PASSWORD", Value: "secret", Secret: true},
+	}
+
+	got := checkly.ResolveEffective(check, &group, account)
+
+	want := check
+	want.Muted = true
+	want.RunParallel = true
+	want.Locations = group.Locations
+	want.RuntimeID = &runtime
+	want.UseGlobalAlertSettings = false
+	want.AlertSettings = *group.AlertSettings
+	want.RetryStrategy = group.RetryStrategy
+	want.TearDownSnippetID = 42
+	want.EnvironmentVariables = []checkly.EnvironmentVariable{
+		{Key: "TOKEN", Value: "check"},
+		{Key: "PASSWORD", Value: "secret", Secret: true},
+		{Key: "REGION", Value: "us"},
+		{Key: "GROUP_BASE_URL", Value: "https://api.example.com"},
+	}
+	want.Request.Headers = []checkly.KeyValue{
+		{Key: "X-Team", Value: "core"},
+		{Key: "accept", Value: "text/plain"},
+	}
+	want.Request.QueryParameters = []checkly.KeyValue{{Key: "page", Value: "1"}}
+	want.Request.Assertions = append(check.Request.Assertions, group.APICheckDefaults.Assertions...)
+	want.Request.BasicAuth = &checkly.BasicAuth{Username: "user", Password: "{{PASSWORD}}"}
+	if diff := cmp.Diff(want, got.Check); diff != "" {
+		t.Errorf("unexpected effective check (-want +got):\n%s", diff)
+	}
+
+	sources := map[string]checkly.Provenance{
+		"activated":                           checkly.ProvenanceCheck,
+		"muted":                               checkly.ProvenanceGroup,
+		"runParallel":                         checkly.ProvenanceGroup,
+		"locations":                           checkly.ProvenanceGroup,
+		"privateLocations":                    checkly.ProvenanceGroup,
+		"runtimeId":                           checkly.ProvenanceCheck,
+		"retryStrategy":                       checkly.ProvenanceGroup,
+		"useGlobalAlertSettings":              checkly.ProvenanceGroup,
+		"alertSettings":                       checkly.ProvenanceGroup,
+		"alertChannelSubscriptions":           checkly.ProvenanceCheck,
+		"setupSnippetId":                      checkly.ProvenanceCheck,
+		"localSetupScript":                    checkly.ProvenanceCheck,
+		"tearDownSnippetId":                   checkly.ProvenanceGroup,
+		"localTearDownScript":                 checkly.ProvenanceGroup,
+		"environmentVariables.TOKEN":          checkly.ProvenanceCheck,
+		"environmentVariables.PASSWORD":       checkly.ProvenanceAccount,
+		"environmentVariables.REGION":         checkly.ProvenanceGroup,
+		"environmentVariables.GROUP_BASE_URL": checkly.ProvenanceGroup,
+		"request.headers.Accept":              checkly.ProvenanceCheck,
+		"request.headers.X-Team":              checkly.ProvenanceGroup,
+		"request.queryParameters.page":        checkly.ProvenanceGroup,
+		"request.assertions.0":                checkly.ProvenanceCheck,
+		"request.assertions.1":                checkly.ProvenanceGroup,
+		"request.basicAuth":                   checkly.ProvenanceGroup,
+	}
+	if diff := cmp.Diff(sources, got.Sources); diff != "" {
+		t.Errorf("unexpected sources (-want +got):\n%s", diff)
+	}
+	if s := got.String(); !strings.HasPrefix(s, "activated: check\nalertChannelSubscriptions: check\n") {
+		t.Errorf("want the sources sorted by path, got %q", s)
+	}
+}
+
+func TestResolveEffectiveDefaults(t *testing.T) {
+	t.Parallel()
+	check := checkly.Check{
+		Activated:              true,
+		Locations:              []string{"eu-west-1"},
+		UseGlobalAlertSettings: true,
+		Request: checkly.Request{
+			Assertions: []checkly.Assertion{
+				{Source: checkly.StatusCode, Comparison: checkly.Equals, Target: "200"},
+			},
+		},
+	}
+
+	t.Run("without group", func(t *testing.T) {
+		got := checkly.ResolveEffective[checkly.GroupV2](check, nil, nil)
+		if diff := cmp.Diff(check, got.Check); diff != "" {
+			t.Errorf("want the check unchanged (-want +got):\n%s", diff)
+		}
+		for path, want := range map[string]checkly.Provenance{
+			"locations":     checkly.ProvenanceCheck,
+			"runtimeId":     checkly.ProvenanceAccount,
+			"retryStrategy": checkly.ProvenanceAccount,
+			"alertSettings": checkly.ProvenanceAccount,
+		} {
+			if got.Sources[path] != want {
+				t.Errorf("want %s from %s, got %q", path, want, got.Sources[path])
+			}
+		}
+	})
+
+	t.Run("legacy group", func(t *testing.T) {
+		group := checkly.Group{
+			Activated:              false,
+			UseGlobalAlertSettings: false,
+			AlertSettings:          checkly.AlertSettings{EscalationType: checkly.TimeBased},
+		}
+		got := checkly.ResolveEffective(check, &group, nil)
+		if got.Check.Activated || got.Sources["activated"] != checkly.ProvenanceGroup {
+			t.Errorf("want the deactivated group to deactivate the check, got %v from %s",
+				got.Check.Activated, got.Sources["activated"])
+		}
+		if got.Check.UseGlobalAlertSettings || got.Check.AlertSettings.EscalationType != checkly.TimeBased ||
+			got.Sources["alertSettings"] != checkly.ProvenanceGroup {
+			t.Errorf("want the alert settings of the group, got %+v from %s",
+				got.Check.AlertSettings, got.Sources["alertSettings"])
+		}
+		if got.Check.RunParallel || got.Sources["runParallel"] != checkly.ProvenanceGroup {
+			t.Errorf("want run parallel from the group, got %s", got.Sources["runParallel"])
+		}
+		if !cmp.Equal(got.Check.Locations, check.Locations) || got.Sources["locations"] != checkly.ProvenanceCheck {
+			t.Errorf("want the locations of the check for a group without any, got %v", got.Check.Locations)
+		}
+	})
+
+	t.Run("group using global alert settings", func(t *testing.T) {
+		yes := true
+		check := check
+		check.UseGlobalAlertSettings = false
+		got := checkly.ResolveEffective(check, &checkly.GroupV2{Activated: true, UseGlobalAlertSettings: &yes}, nil)
+		if !got.Check.UseGlobalAlertSettings || got.Sources["alertSettings"] != checkly.ProvenanceAccount {
+			t.Errorf("want the global alert settings of the account, got %s", got.Sources["alertSettings"])
+		}
+	})
+}
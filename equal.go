@@ -0,0 +1,483 @@
+package checkly
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// FieldChange is a difference between two values of a resource type. Diff
+// reports the Go path of the field, for example
+// "Request.Assertions[0].Target", with its values in the first and second
+// resource as Old and New. The plans of the config package report the JSON
+// path of the field, for example "request.assertions[0].target", with its
+// JSON values in the account and in the desired state.
+type FieldChange struct {
+	Path string
+	Old  any
+	New  any
+}
+
+// String describes the change as "path: old => new".
+func (c FieldChange) String() string {
+	return fmt.Sprintf("%s: %v => %v", c.Path, c.Old, c.New)
+}
+
+// Equal reports whether a and b describe the same resource configuration,
+// see Diff.
+func Equal[T any](a, b T) bool {
+	return len(Diff(a, b)) == 0
+}
+
+// Diff returns the differences between two values of a resource type, like a
+// desired check and the check returned by the API, in field order. T may be
+// a struct, a pointer to one, or an interface like Monitor.
+//
+// Fields assigned by the API, like IDs, creation dates, ping tokens and
+// dashboard keys, are ignored, as are fields like frequency offsets and
+// private keys when one of the values doesn't have them. Tags, locations and
+// private locations are compared regardless of their order, and nil slices,
+// maps and pointers to them equal empty ones. Unset fields equal the defaults
+// the API gives them: a nil RetryStrategy is a FALLBACK one, a nil or zero
+// AlertSettings has the run-based escalation, reminders and parallel run
+// threshold the API uses, nil dashboard ShowHeader, ShowP95 and ShowP99 flags
+// are true and DNS requests use UDP. Settings which don't apply are ignored,
+// like the alert settings of a check using the global ones or the backoff of
+// a FALLBACK retry strategy.
+func Diff[T any](a, b T) []FieldChange {
+	d := differ{}
+	va, vb := reflect.ValueOf(&a).Elem(), reflect.ValueOf(&b).Elem()
+	d.diff("", "", va, vb, true)
+	return d.changes
+}
+
+// serverFields are the fields set by the API, by the name of the type holding
+// them. The fields listed for "" apply to the compared resources themselves,
+// as nested IDs reference other resources.
+var serverFields = map[string][]string{
+	"":                {"ID", "CreatedAt", "UpdatedAt"},
+	"Heartbeat":       {"PingToken"},
+	"Dashboard":       {"DashboardID", "Keys"},
+	"PrivateLocation": {"Keys", "LastSeen", "AgentCount"},
+	"TriggerCheck":    {"Token", "URL", "CalledAt"},
+	"TriggerGroup":    {"Token", "URL", "CalledAt"},
+}
+
+// optionalFields are the fields the API assigns when they are unset or
+// doesn't return, which are only compared when both values have them, by the
+// name of the type holding them. The fields listed for "" apply to all types.
+var optionalFields = map[string][]string{
+	"":                  {"FrequencyOffset"},
+	"ClientCertificate": {"PrivateKey", "Passphrase"},
+}
+
+// unorderedFields are the names of the string slice fields whose order
+// doesn't matter.
+var unorderedFields = []string{"Tags", "Locations", "PrivateLocations", "OnlyOn"}
+
+// fieldDefaults are the values the API gives unset fields, by the name of the
+// type holding them. Pointer fields are unset when nil, others when zero. The
+// fields listed for "" apply to all types.
+var fieldDefaults = map[string]map[string]any{
+	"":           {"PrivateLocations": []string{}},
+	"Dashboard":  {"ShowHeader": true, "ShowP95": true, "ShowP99": true},
+	"DNSRequest": {"Protocol": "UDP"},
+}
+
+var (
+	retryStrategyType = reflect.TypeOf(RetryStrategy{})
+	alertSettingsType = reflect.TypeOf(AlertSettings{})
+	timeType          = reflect.TypeOf(time.Time{})
+)
+
+type differ struct {
+	changes []FieldChange
+}
+
+func (d *differ) add(path string, a, b reflect.Value) {
+	d.changes = append(d.changes, FieldChange{Path: path, Old: valueOf(a), New: valueOf(b)})
+}
+
+// valueOf returns the value held by v, or nil if v is invalid.
+func valueOf(v reflect.Value) any {
+	if !v.IsValid() || !v.CanInterface() {
+		return nil
+	}
+	return v.Interface()
+}
+
+// diff compares a and b, which have the same type. field is the name of the
+// struct field holding them, if any, and top reports whether they are the
+// compared resources themselves.
+func (d *differ) diff(path, field string, a, b reflect.Value, top bool) {
+	switch a.Kind() {
+	case reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			if a.IsNil() != b.IsNil() {
+				d.add(path, a, b)
+			}
+			return
+		}
+		if a.Elem().Type() != b.Elem().Type() {
+			d.add(path, a, b)
+			return
+		}
+		d.diff(path, field, a.Elem(), b.Elem(), top)
+
+	case reflect.Pointer:
+		if a.IsNil() && b.IsNil() {
+			return
+		}
+		ea, eb := pointerElem(a), pointerElem(b)
+		if !ea.IsValid() || !eb.IsValid() {
+			if !isEmptyValue(ea) || !isEmptyValue(eb) {
+				d.add(path, a, b)
+			}
+			return
+		}
+		d.diff(path, field, ea, eb, top)
+
+	case reflect.Struct:
+		if a.Type() == timeType {
+			if !a.Interface().(time.Time).Equal(b.Interface().(time.Time)) {
+				d.add(path, a, b)
+			}
+			return
+		}
+		switch a.Type() {
+		case retryStrategyType:
+			a = reflect.ValueOf(canonicalRetryStrategy(a.Interface().(RetryStrategy)))
+			b = reflect.ValueOf(canonicalRetryStrategy(b.Interface().(RetryStrategy)))
+		case alertSettingsType:
+			a = reflect.ValueOf(canonicalAlertSettings(a.Interface().(AlertSettings)))
+			b = reflect.ValueOf(canonicalAlertSettings(b.Interface().(AlertSettings)))
+		}
+		d.diffStruct(path, a, b, top)
+
+	case reflect.Slice:
+		if a.Len() == 0 && b.Len() == 0 {
+			return
+		}
+		if a.Type().Elem().Kind() == reflect.String && contains(unorderedFields, field) {
+			if !reflect.DeepEqual(sortedStrings(a), sortedStrings(b)) {
+				d.add(path, a, b)
+			}
+			return
+		}
+		if a.Len() != b.Len() {
+			d.add(path, a, b)
+			return
+		}
+		for i := 0; i < a.Len(); i++ {
+			d.diff(fmt.Sprintf("%s[%d]", path, i), "", a.Index(i), b.Index(i), false)
+		}
+
+	case reflect.Map:
+		if a.Len() == 0 && b.Len() == 0 {
+			return
+		}
+		keys := map[string]reflect.Value{}
+		for _, k := range append(a.MapKeys(), b.MapKeys()...) {
+			keys[fmt.Sprint(k.Interface())] = k
+		}
+		names := make([]string, 0, len(keys))
+		for name := range keys {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			ea, eb := a.MapIndex(keys[name]), b.MapIndex(keys[name])
+			elemPath := fmt.Sprintf("%s[%s]", path, name)
+			if !ea.IsValid() || !eb.IsValid() {
+				if !isEmptyValue(ea) || !isEmptyValue(eb) {
+					d.add(elemPath, ea, eb)
+				}
+				continue
+			}
+			d.diff(elemPath, "", ea, eb, false)
+		}
+
+	default:
+		if a.CanInterface() && a.Interface() != b.Interface() {
+			d.add(path, a, b)
+		}
+	}
+}
+
+// pointerElem returns the value p points to, or the default the API gives it
+// if p is nil. It returns an invalid Value if p is nil and there is no
+// default.
+func pointerElem(p reflect.Value) reflect.Value {
+	if !p.IsNil() {
+		return p.Elem()
+	}
+	switch p.Type().Elem() {
+	case retryStrategyType, alertSettingsType:
+		return reflect.Zero(p.Type().Elem())
+	}
+	return reflect.Value{}
+}
+
+func (d *differ) diffStruct(path string, a, b reflect.Value, top bool) {
+	t := a.Type()
+	typeName := t.Name()
+	skip := map[string]bool{}
+	for _, name := range serverFields[typeName] {
+		skip[name] = true
+	}
+	if top {
+		for _, name := range serverFields[""] {
+			skip[name] = true
+		}
+	}
+	for _, name := range append(optionalFields[""], optionalFields[typeName]...) {
+		fa, fb := a.FieldByName(name), b.FieldByName(name)
+		if fa.IsValid() && (fa.IsZero() || fb.IsZero()) {
+			skip[name] = true
+		}
+	}
+	if typeName == "EnvironmentVariable" && (a.FieldByName("Secret").Bool() || b.FieldByName("Secret").Bool()) {
+		// The API doesn't return the values of secret variables.
+		if a.FieldByName("Value").IsZero() || b.FieldByName("Value").IsZero() {
+			skip["Value"] = true
+		}
+	}
+	if isTrue(a.FieldByName("UseGlobalAlertSettings")) && isTrue(b.FieldByName("UseGlobalAlertSettings")) {
+		skip["AlertSettings"] = true
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() || skip[f.Name] {
+			continue
+		}
+		fa, fb := a.Field(i), b.Field(i)
+		if def, ok := fieldDefault(typeName, f); ok {
+			fa, fb = withDefault(fa, def), withDefault(fb, def)
+		}
+		fieldPath := f.Name
+		if path != "" {
+			fieldPath = path + "." + f.Name
+		}
+		if f.Anonymous {
+			// Fields of embedded structs are promoted.
+			fieldPath = path
+		}
+		d.diff(fieldPath, f.Name, fa, fb, false)
+	}
+}
+
+// fieldDefault returns the value the API gives the field f of the type named
+// typeName when it is unset, if any.
+func fieldDefault(typeName string, f reflect.StructField) (any, bool) {
+	if def, ok := fieldDefaults[typeName][f.Name]; ok {
+		return def, true
+	}
+	def, ok := fieldDefaults[""][f.Name]
+	if ok && reflect.TypeOf(def).ConvertibleTo(indirectType(f.Type)) {
+		return def, true
+	}
+	return nil, false
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Pointer {
+		return t.Elem()
+	}
+	return t
+}
+
+// WithDefaults returns a copy of v, which may be a resource or a struct or
+// slice holding them, with its unset fields set to the values the API gives
+// them, the defaults Diff compares unset fields with. Retry strategies and
+// alert settings are set to their canonical form, without the settings which
+// don't apply, and the alert settings of resources using the global ones to
+// the defaults.
+func WithDefaults[T any](v T) T {
+	var result T
+	if value := withDefaults(reflect.ValueOf(&v).Elem()); value.IsValid() {
+		reflect.ValueOf(&result).Elem().Set(value)
+	}
+	return result
+}
+
+// withDefaults returns a copy of v with the defaults of its unset fields set,
+// see WithDefaults.
+func withDefaults(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		result := reflect.New(v.Type()).Elem()
+		result.Set(withDefaults(v.Elem()))
+		return result
+
+	case reflect.Pointer:
+		if v.IsNil() {
+			if elem := pointerElem(v); elem.IsValid() {
+				p := reflect.New(elem.Type())
+				p.Elem().Set(withDefaults(elem))
+				return p
+			}
+			return v
+		}
+		p := reflect.New(v.Type().Elem())
+		p.Elem().Set(withDefaults(v.Elem()))
+		return p
+
+	case reflect.Struct:
+		switch v.Type() {
+		case timeType:
+			return v
+		case retryStrategyType:
+			return reflect.ValueOf(canonicalRetryStrategy(v.Interface().(RetryStrategy)))
+		case alertSettingsType:
+			return reflect.ValueOf(canonicalAlertSettings(v.Interface().(AlertSettings)))
+		}
+		t := v.Type()
+		result := reflect.New(t).Elem()
+		result.Set(v)
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			field := result.Field(i)
+			if isTrue(v.FieldByName("UseGlobalAlertSettings")) && f.Name == "AlertSettings" {
+				// The alert settings don't apply.
+				field.Set(reflect.Zero(f.Type))
+			}
+			if def, ok := fieldDefault(t.Name(), f); ok {
+				field.Set(withDefault(field, def))
+			}
+			field.Set(withDefaults(field))
+		}
+		return result
+
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		result := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			result.Index(i).Set(withDefaults(v.Index(i)))
+		}
+		return result
+
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		result := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			result.SetMapIndex(iter.Key(), withDefaults(iter.Value()))
+		}
+		return result
+	}
+	return v
+}
+
+// withDefault returns def, or a pointer to it, if v is unset.
+func withDefault(v reflect.Value, def any) reflect.Value {
+	if v.Kind() != reflect.Pointer {
+		if v.IsZero() {
+			return reflect.ValueOf(def).Convert(v.Type())
+		}
+		return v
+	}
+	if !v.IsNil() {
+		return v
+	}
+	p := reflect.New(v.Type().Elem())
+	p.Elem().Set(reflect.ValueOf(def).Convert(v.Type().Elem()))
+	return p
+}
+
+// canonicalRetryStrategy returns s with its defaults set and the fields which
+// don't apply to its type cleared.
+func canonicalRetryStrategy(s RetryStrategy) RetryStrategy {
+	switch s.Type {
+	case "", "FALLBACK":
+		return RetryStrategy{Type: "FALLBACK"}
+	case "NO_RETRIES":
+		return RetryStrategy{Type: "NO_RETRIES"}
+	case "SINGLE_RETRY":
+		s.MaxRetries, s.MaxDurationSeconds = 0, 0
+	}
+	return s
+}
+
+// canonicalAlertSettings returns s with the defaults of the API set and the
+// settings which don't apply to its escalation type cleared.
+func canonicalAlertSettings(s AlertSettings) AlertSettings {
+	// The API no longer returns the deprecated SSL certificate settings.
+	s.SSLCertificates = SSLCertificates{}
+	if s.EscalationType == "" {
+		s.EscalationType = RunBased
+	}
+	switch s.EscalationType {
+	case RunBased:
+		s.TimeBasedEscalation = TimeBasedEscalation{}
+		if s.RunBasedEscalation.FailedRunThreshold == 0 {
+			s.RunBasedEscalation.FailedRunThreshold = 1
+		}
+	case TimeBased:
+		s.RunBasedEscalation = RunBasedEscalation{}
+		if s.TimeBasedEscalation.MinutesFailingThreshold == 0 {
+			s.TimeBasedEscalation.MinutesFailingThreshold = 5
+		}
+	}
+	if s.Reminders.Interval == 0 || s.Reminders.Amount == 0 {
+		s.Reminders.Interval = 5
+	}
+	if s.ParallelRunFailureThreshold.Percentage == 0 || !s.ParallelRunFailureThreshold.Enabled {
+		s.ParallelRunFailureThreshold.Percentage = 10
+	}
+	return s
+}
+
+// isEmptyValue reports whether v is invalid, or an empty slice or map or a
+// pointer to one.
+func isEmptyValue(v reflect.Value) bool {
+	if !v.IsValid() {
+		return true
+	}
+	switch v.Kind() {
+	case reflect.Pointer:
+		return v.IsNil() || isEmptyValue(v.Elem())
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	}
+	return false
+}
+
+// isTrue reports whether v is a true bool or a pointer to one.
+func isTrue(v reflect.Value) bool {
+	if !v.IsValid() {
+		return false
+	}
+	if v.Kind() == reflect.Pointer {
+		return !v.IsNil() && isTrue(v.Elem())
+	}
+	return v.Kind() == reflect.Bool && v.Bool()
+}
+
+func sortedStrings(v reflect.Value) []string {
+	result := make([]string, v.Len())
+	for i := range result {
+		result[i] = v.Index(i).String()
+	}
+	sort.Strings(result)
+	return result
+}
+
+func contains(items []string, item string) bool {
+	for _, i := range items {
+		if i == item {
+			return true
+		}
+	}
+	return false
+}
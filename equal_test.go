@@ -0,0 +1,197 @@
+package checkly_test
+
+import (
+	"os"
+	"testing"
+
+	checkly "github.com/checkly/checkly-go-sdk"
+)
+
+func TestEqualIgnoresServerFieldsAndDefaults(t *testing.T) {
+	t.Parallel()
+	data, err := os.ReadFile("fixtures/GetDNSMonitor.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := checkly.DecodeMonitor(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &checkly.DNSMonitor{
+		Name:                 "DNS Monitor #1",
+		Frequency:            10,
+		Locations:            []string{"us-east-1"},
+		DegradedResponseTime: 500,
+		MaxResponseTime:      1000,
+		Tags:                 []string{"tag-1"},
+		Request: checkly.DNSRequest{
+			RecordType: "A",
+			Query:      "welcome.checklyhq.com",
+			Assertions: []checkly.Assertion{
+				{Source: "RESPONSE_CODE", Comparison: checkly.Equals, Target: "NOERROR"},
+			},
+		},
+	}
+	if diff := checkly.Diff[checkly.Monitor](want, got); len(diff) > 0 {
+		t.Errorf("want the monitors to be equal, got %v", diff)
+	}
+
+	want.Request.Assertions[0].Target = "NXDOMAIN"
+	want.AlertSettings = &checkly.AlertSettings{Reminders: checkly.Reminders{Amount: 1}}
+	diff := checkly.Diff[checkly.Monitor](want, got)
+	if len(diff) != 2 || diff[0].Path != "AlertSettings.Reminders.Amount" || diff[1].Path != "Request.Assertions[0].Target" {
+		t.Fatalf("want the reminders and assertion target to differ, got %v", diff)
+	}
+	if diff[1].Old != "NXDOMAIN" || diff[1].New != "NOERROR" {
+		t.Errorf("want the target to change from NXDOMAIN to NOERROR, got %v", diff[1])
+	}
+	if checkly.Equal[checkly.Monitor](want, &checkly.TCPMonitor{}) {
+		t.Error("want monitors of different types to differ")
+	}
+}
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+	yes, no := true, false
+	tests := []struct {
+		name  string
+		a, b  any
+		paths []string
+	}{
+		{
+			name: "unordered tags and locations",
+			a:    checkly.URLMonitor{Tags: []string{"a", "b"}, Locations: []string{"eu-west-1", "us-east-1"}},
+			b:    checkly.URLMonitor{Tags: []string{"b", "a"}, Locations: []string{"us-east-1", "eu-west-1"}},
+		},
+		{
+			name:  "changed tags",
+			a:     checkly.URLMonitor{Tags: []string{"a", "b"}},
+			b:     checkly.URLMonitor{Tags: []string{"a", "c"}},
+			paths: []string{"Tags"},
+		},
+		{
+			name: "nil and empty private locations",
+			a:    checkly.URLMonitor{PrivateLocations: nil},
+			b:    checkly.URLMonitor{PrivateLocations: &[]string{}},
+		},
+		{
+			name: "nil and fallback retry strategy",
+			a:    checkly.URLMonitor{},
+			b:    checkly.URLMonitor{RetryStrategy: &checkly.RetryStrategy{Type: "FALLBACK", MaxRetries: 2}},
+		},
+		{
+			name:  "nil and no retries",
+			a:     checkly.URLMonitor{},
+			b:     checkly.URLMonitor{RetryStrategy: &checkly.RetryStrategy{Type: "NO_RETRIES"}},
+			paths: []string{"RetryStrategy.Type"},
+		},
+		{
+			name: "single retry ignores max retries",
+			a:    checkly.URLMonitor{RetryStrategy: &checkly.RetryStrategy{Type: "SINGLE_RETRY", BaseBackoffSeconds: 30}},
+			b:    checkly.URLMonitor{RetryStrategy: &checkly.RetryStrategy{Type: "SINGLE_RETRY", BaseBackoffSeconds: 30, MaxRetries: 1}},
+		},
+		{
+			name: "default alert settings",
+			a:    checkly.Check{},
+			b: checkly.Check{AlertSettings: checkly.AlertSettings{
+				EscalationType:              checkly.RunBased,
+				RunBasedEscalation:          checkly.RunBasedEscalation{FailedRunThreshold: 1},
+				TimeBasedEscalation:         checkly.TimeBasedEscalation{MinutesFailingThreshold: 5},
+				Reminders:                   checkly.Reminders{Interval: 5},
+				ParallelRunFailureThreshold: checkly.ParallelRunFailureThreshold{Percentage: 10},
+			}},
+		},
+		{
+			name: "global alert settings",
+			a:    checkly.Check{UseGlobalAlertSettings: true},
+			b:    checkly.Check{UseGlobalAlertSettings: true, AlertSettings: checkly.AlertSettings{EscalationType: checkly.TimeBased}},
+		},
+		{
+			name:  "changed alert settings",
+			a:     checkly.Check{},
+			b:     checkly.Check{AlertSettings: checkly.AlertSettings{Reminders: checkly.Reminders{Amount: 2}}},
+			paths: []string{"AlertSettings.Reminders.Amount"},
+		},
+		{
+			name: "server fields",
+			a:    checkly.Dashboard{CustomUrl: "status"},
+			b:    checkly.Dashboard{ID: 1, DashboardID: "abc", CustomUrl: "status", CreatedAt: "2024-01-01", ShowP95: &yes},
+		},
+		{
+			name:  "dashboard defaults",
+			a:     checkly.Dashboard{},
+			b:     checkly.Dashboard{ShowP99: &no},
+			paths: []string{"ShowP99"},
+		},
+		{
+			name: "secret variable value",
+			a:    checkly.EnvironmentVariable{Key: "TOKEN", Value: "secret", Secret: true},
+			b:    checkly.EnvironmentVariable{Key: "TOKEN", Secret: true},
+		},
+		{
+			name: "client certificate private key",
+			a:    &checkly.ClientCertificate{Host: "example.com", PrivateKey: "key", Passphrase: "pass"},
+			b:    &checkly.ClientCertificate{ID: "1", Host: "example.com"},
+		},
+		{
+			name:  "alert channel config",
+			a:     checkly.AlertChannel{Type: checkly.AlertTypeEmail, Email: &checkly.AlertChannelEmail{Address: "a@example.com"}},
+			b:     checkly.AlertChannel{Type: checkly.AlertTypeEmail, Email: &checkly.AlertChannelEmail{Address: "b@example.com"}},
+			paths: []string{"Email.Address"},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			diff := checkly.Diff(tc.a, tc.b)
+			var paths []string
+			for _, c := range diff {
+				paths = append(paths, c.Path)
+			}
+			if len(paths) != len(tc.paths) {
+				t.Fatalf("want differences in %q, got %v", tc.paths, diff)
+			}
+			for i := range paths {
+				if paths[i] != tc.paths[i] {
+					t.Errorf("want differences in %q, got %v", tc.paths, diff)
+				}
+			}
+			if equal := checkly.Equal(tc.a, tc.b); equal != (len(tc.paths) == 0) {
+				t.Errorf("want Equal to be %t, got %t", len(tc.paths) == 0, equal)
+			}
+		})
+	}
+}
+
+func TestWithDefaults(t *testing.T) {
+	t.Parallel()
+	yes := true
+	group := checkly.GroupV2{
+		Name:                   "Group",
+		UseGlobalAlertSettings: &yes,
+		AlertSettings:          &checkly.AlertSettings{EscalationType: checkly.TimeBased},
+	}
+	got := checkly.WithDefaults(group)
+	if got.RetryStrategy == nil || got.RetryStrategy.Type != "FALLBACK" {
+		t.Errorf("want a FALLBACK retry strategy, got %+v", got.RetryStrategy)
+	}
+	if got.PrivateLocations == nil || len(*got.PrivateLocations) != 0 {
+		t.Errorf("want no private locations, got %v", got.PrivateLocations)
+	}
+	if got.AlertSettings == nil || got.AlertSettings.EscalationType != checkly.RunBased {
+		t.Errorf("want the default alert settings for global ones, got %+v", got.AlertSettings)
+	}
+	if group.RetryStrategy != nil || group.AlertSettings.EscalationType != checkly.TimeBased {
+		t.Error("want the group unchanged")
+	}
+	if !checkly.Equal(group, got) {
+		t.Errorf("want the group to equal its defaults, got %v", checkly.Diff(group, got))
+	}
+
+	var monitor checkly.Monitor = &checkly.DNSMonitor{Name: "DNS"}
+	dns := checkly.WithDefaults(monitor).(*checkly.DNSMonitor)
+	if dns.Request.Protocol != "UDP" || monitor.(*checkly.DNSMonitor).Request.Protocol != "" {
+		t.Errorf("want a copy of the monitor using UDP, got %q", dns.Request.Protocol)
+	}
+}
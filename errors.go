@@ -0,0 +1,130 @@
+package checkly
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// APIError is returned by the client whenever the Checkly API replies with a
+// non-2xx status code. Use errors.As to inspect it, or one of the IsNotFound,
+// IsConflict and IsValidationError helpers to classify it.
+type APIError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+
+	// Method is the HTTP method of the failed request.
+	Method string
+
+	// Path is the path of the failed request, e.g. "/v1/checks/<id>".
+	Path string
+
+	// Reason is the short error description sent by the API, e.g.
+	// "Bad Request" or "Not Found".
+	Reason string
+
+	// Message is the human readable error message sent by the API.
+	Message string
+
+	// Validation holds the fields that failed validation, if the API
+	// reported any.
+	Validation *APIErrorValidation
+
+	// Body is the raw response body.
+	Body string
+
+	// Header holds the response headers, e.g. the rate limit headers.
+	Header http.Header
+}
+
+// APIErrorValidation describes which parts of a request failed validation.
+type APIErrorValidation struct {
+	// Source is the part of the request that failed, e.g. "payload" or
+	// "query".
+	Source string `json:"source"`
+
+	// Keys are the paths of the offending fields, e.g. "frequency" or
+	// "request.assertions.0.target".
+	Keys []string `json:"keys"`
+}
+
+// apiErrorBody is the error payload returned by the Checkly API.
+type apiErrorBody struct {
+	Error      string              `json:"error"`
+	Message    string              `json:"message"`
+	Validation *APIErrorValidation `json:"validation"`
+}
+
+func newAPIError(
+	method string,
+	path string,
+	statusCode int,
+	header http.Header,
+	body string,
+) *APIError {
+	apiErr := &APIError{
+		StatusCode: statusCode,
+		Method:     method,
+		Path:       path,
+		Body:       body,
+		Header:     header,
+	}
+	// The body is not guaranteed to be JSON (e.g. errors from a proxy), in
+	// which case only the raw body is available.
+	var payload apiErrorBody
+	if err := json.Unmarshal([]byte(body), &payload); err == nil {
+		apiErr.Reason = payload.Error
+		apiErr.Message = payload.Message
+		apiErr.Validation = payload.Validation
+	}
+	if apiErr.Reason == "" {
+		apiErr.Reason = http.StatusText(statusCode)
+	}
+	return apiErr
+}
+
+// Error implements the error interface. The message format is kept identical
+// to the one returned by earlier versions of the SDK.
+func (e *APIError) Error() string {
+	return fmt.Sprintf("unexpected response status %d: %q", e.StatusCode, e.Body)
+}
+
+// IsNotFound reports whether err is an APIError for a resource that does not
+// exist (HTTP 404).
+func IsNotFound(err error) bool {
+	return hasStatusCode(err, http.StatusNotFound)
+}
+
+// IsConflict reports whether err is an APIError caused by a conflict with the
+// current state of a resource (HTTP 409).
+func IsConflict(err error) bool {
+	return hasStatusCode(err, http.StatusConflict)
+}
+
+// IsValidationError reports whether err is an APIError caused by an invalid
+// request payload: either an HTTP 422, or an HTTP 400 carrying validation
+// details. It also reports true for the ValidationErrors returned by client
+// side validation, see WithValidation.
+func IsValidationError(err error) bool {
+	if isValidationErrors(err) {
+		return true
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.StatusCode {
+	case http.StatusUnprocessableEntity:
+		return true
+	case http.StatusBadRequest:
+		return apiErr.Validation != nil
+	default:
+		return false
+	}
+}
+
+func hasStatusCode(err error, statusCode int) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == statusCode
+}
@@ -0,0 +1,135 @@
+package checkly_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	checkly "github.com/checkly/checkly-go-sdk"
+)
+
+func TestAPIErrorValidation(t *testing.T) {
+	t.Parallel()
+	ts := cannedResponseServer(t,
+		http.MethodPost,
+		"/v1/checks/api?autoAssignAlerts=false",
+		validateAnything,
+		http.StatusBadRequest,
+		"BadRequest.json",
+	)
+	defer ts.Close()
+	client := checkly.NewClient(ts.URL, "dummy-key", ts.Client(), nil)
+	_, err := client.CreateCheck(context.Background(), checkly.Check{
+		Type: checkly.TypeAPI,
+	})
+	var apiErr *checkly.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("want *checkly.APIError, got %T: %v", err, err)
+	}
+	if apiErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("want status %d, got %d", http.StatusBadRequest, apiErr.StatusCode)
+	}
+	if apiErr.Method != http.MethodPost {
+		t.Errorf("want method %q, got %q", http.MethodPost, apiErr.Method)
+	}
+	if apiErr.Path != "/v1/checks/api" {
+		t.Errorf("want path %q, got %q", "/v1/checks/api", apiErr.Path)
+	}
+	if apiErr.Reason != "Bad Request" {
+		t.Errorf("want reason %q, got %q", "Bad Request", apiErr.Reason)
+	}
+	wantValidation := &checkly.APIErrorValidation{
+		Source: "payload",
+		Keys:   []string{"frequency"},
+	}
+	if !cmp.Equal(wantValidation, apiErr.Validation) {
+		t.Error(cmp.Diff(wantValidation, apiErr.Validation))
+	}
+	if apiErr.Header.Get("Content-Type") == "" {
+		t.Error("want response headers to be set on the error")
+	}
+	if !checkly.IsValidationError(err) {
+		t.Error("want IsValidationError to be true")
+	}
+	if checkly.IsNotFound(err) || checkly.IsConflict(err) {
+		t.Error("want IsNotFound and IsConflict to be false")
+	}
+}
+
+func TestAPIErrorNotFound(t *testing.T) {
+	t.Parallel()
+	ts := cannedResponseServer(t,
+		http.MethodGet,
+		fmt.Sprintf("/v1/checks/%s", wantCheckID),
+		validateEmptyBody,
+		http.StatusNotFound,
+		"NotFound.json",
+	)
+	defer ts.Close()
+	client := checkly.NewClient(ts.URL, "dummy-key", ts.Client(), nil)
+	_, err := client.GetURLMonitor(context.Background(), wantCheckID)
+	if !checkly.IsNotFound(err) {
+		t.Fatalf("want IsNotFound to be true, got %v", err)
+	}
+	// The helpers must see through wrapped errors.
+	wrapped := fmt.Errorf("syncing monitor: %w", err)
+	if !checkly.IsNotFound(wrapped) {
+		t.Error("want IsNotFound to be true for a wrapped error")
+	}
+	if checkly.IsValidationError(err) {
+		t.Error("want IsValidationError to be false")
+	}
+}
+
+func TestAPIErrorConflict(t *testing.T) {
+	t.Parallel()
+	ts := cannedResponseServer(t,
+		http.MethodPost,
+		"/v1/variables",
+		validateAnything,
+		http.StatusConflict,
+		"Conflict.json",
+	)
+	defer ts.Close()
+	client := checkly.NewClient(ts.URL, "dummy-key", ts.Client(), nil)
+	_, err := client.CreateEnvironmentVariable(context.Background(), testEnvVariable)
+	if !checkly.IsConflict(err) {
+		t.Fatalf("want IsConflict to be true, got %v", err)
+	}
+	var apiErr *checkly.APIError
+	errors.As(err, &apiErr)
+	if apiErr.Message != "A variable with this key already exists" {
+		t.Errorf("unexpected message %q", apiErr.Message)
+	}
+}
+
+func TestAPIErrorDelete(t *testing.T) {
+	t.Parallel()
+	ts := cannedResponseServer(t,
+		http.MethodDelete,
+		fmt.Sprintf("/v1/check-groups/%d", wantGroupID),
+		validateEmptyBody,
+		http.StatusNotFound,
+		"NotFound.json",
+	)
+	defer ts.Close()
+	client := checkly.NewClient(ts.URL, "dummy-key", ts.Client(), nil)
+	err := client.DeleteGroup(context.Background(), wantGroupID)
+	if !checkly.IsNotFound(err) {
+		t.Fatalf("want IsNotFound to be true, got %v", err)
+	}
+}
+
+func TestIsNotFoundNonAPIError(t *testing.T) {
+	t.Parallel()
+	if checkly.IsNotFound(nil) {
+		t.Error("want IsNotFound(nil) to be false")
+	}
+	if checkly.IsNotFound(errors.New("not found")) {
+		t.Error("want IsNotFound to be false for a plain error")
+	}
+}
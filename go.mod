@@ -2,4 +2,7 @@ module github.com/checkly/checkly-go-sdk
 
 go 1.21
 
-require github.com/google/go-cmp v0.7.0
+require (
+	github.com/google/go-cmp v0.7.0
+	gopkg.in/yaml.v3 v3.0.1
+)
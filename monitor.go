@@ -0,0 +1,704 @@
+package checkly
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Monitor is implemented by every check and monitor type returned by the
+// API: *Check (API and browser checks), *MultiStepCheck, *PlaywrightCheck,
+// *HeartbeatMonitor, *TCPMonitor, *URLMonitor, *DNSMonitor, *ICMPMonitor,
+// *GRPCMonitor, *TracerouteMonitor and *SSLMonitor. Check types this version
+// of the SDK does not know about are returned as *UnknownMonitor.
+//
+// The interface is sealed, so a type switch over the types above is
+// exhaustive:
+//
+//	switch m := monitor.(type) {
+//	case *checkly.Check:
+//		fmt.Println(m.Request.URL)
+//	case *checkly.TCPMonitor:
+//		fmt.Println(m.Request.Hostname)
+//	}
+type Monitor interface {
+	// CheckType returns the type of the check, for example TypeAPI or
+	// TypeTCP.
+	CheckType() string
+
+	// GetID returns the ID of the check, empty for checks not created yet.
+	GetID() string
+
+	GetName() string
+	SetName(name string)
+
+	GetTags() []string
+	SetTags(tags []string)
+
+	// GetLocations returns the public locations the check runs from. It
+	// returns nil for heartbeat monitors, which have no locations, and
+	// SetLocations has no effect on them.
+	GetLocations() []string
+	SetLocations(locations []string)
+
+	// GetFrequency returns the check frequency in minutes. It returns 0 for
+	// heartbeat monitors, which are not scheduled, and SetFrequency has no
+	// effect on them.
+	GetFrequency() int
+	SetFrequency(frequency int)
+
+	IsActivated() bool
+	SetActivated(activated bool)
+
+	IsMuted() bool
+	SetMuted(muted bool)
+
+	// GetGroupID returns the ID of the group the check belongs to, or 0. It
+	// returns 0 for heartbeat monitors, which cannot be grouped, and
+	// SetGroupID has no effect on them.
+	GetGroupID() int64
+	SetGroupID(ID int64)
+
+	// GetAlertSettings returns the alert settings of the check, or nil if
+	// it has none. Changes made through the returned pointer are not
+	// guaranteed to apply to the check, use SetAlertSettings instead.
+	GetAlertSettings() *AlertSettings
+	// SetAlertSettings sets the alert settings of the check. Setting nil
+	// resets them to the zero value for check types which always have alert
+	// settings.
+	SetAlertSettings(settings *AlertSettings)
+
+	// Validate checks the check for mistakes the API would reject, like a
+	// frequency it doesn't allow or an assertion source the check type
+	// doesn't support. It returns a ValidationErrors listing every invalid
+	// field, or nil.
+	Validate() error
+
+	isMonitor()
+}
+
+// UnknownMonitor holds a check of a type this version of the SDK cannot
+// decode.
+type UnknownMonitor struct {
+	ID   string
+	Name string
+	Type string
+	// Raw is the check as returned by the API.
+	Raw json.RawMessage
+}
+
+// CheckType returns the type of c, TypeAPI or TypeBrowser.
+func (c *Check) CheckType() string { return c.Type }
+
+// CheckType returns TypeMultiStep.
+func (c *MultiStepCheck) CheckType() string { return TypeMultiStep }
+
+// CheckType returns TypePlaywright.
+func (c *PlaywrightCheck) CheckType() string { return TypePlaywright }
+
+// CheckType returns TypeHeartbeat.
+func (m *HeartbeatMonitor) CheckType() string { return TypeHeartbeat }
+
+// CheckType returns TypeTCP.
+func (m *TCPMonitor) CheckType() string { return TypeTCP }
+
+// CheckType returns TypeURL.
+func (m *URLMonitor) CheckType() string { return TypeURL }
+
+// CheckType returns TypeDNS.
+func (m *DNSMonitor) CheckType() string { return TypeDNS }
+
+// CheckType returns TypeICMP.
+func (m *ICMPMonitor) CheckType() string { return TypeICMP }
+
+// CheckType returns TypeGRPC.
+func (m *GRPCMonitor) CheckType() string { return TypeGRPC }
+
+// CheckType returns TypeTraceroute.
+func (m *TracerouteMonitor) CheckType() string { return TypeTraceroute }
+
+// CheckType returns TypeSSL.
+func (m *SSLMonitor) CheckType() string { return TypeSSL }
+
+// CheckType returns the check type reported by the API.
+func (m *UnknownMonitor) CheckType() string { return m.Type }
+
+// The accessors below implement Monitor for each check type, see the
+// interface for their documentation.
+
+func (c *Check) GetID() string                    { return c.ID }
+func (c *Check) GetName() string                  { return c.Name }
+func (c *Check) SetName(name string)              { c.Name = name }
+func (c *Check) GetTags() []string                { return c.Tags }
+func (c *Check) SetTags(tags []string)            { c.Tags = tags }
+func (c *Check) GetLocations() []string           { return c.Locations }
+func (c *Check) SetLocations(locations []string)  { c.Locations = locations }
+func (c *Check) GetFrequency() int                { return c.Frequency }
+func (c *Check) SetFrequency(frequency int)       { c.Frequency = frequency }
+func (c *Check) IsActivated() bool                { return c.Activated }
+func (c *Check) SetActivated(activated bool)      { c.Activated = activated }
+func (c *Check) IsMuted() bool                    { return c.Muted }
+func (c *Check) SetMuted(muted bool)              { c.Muted = muted }
+func (c *Check) GetGroupID() int64                { return c.GroupID }
+func (c *Check) SetGroupID(ID int64)              { c.GroupID = ID }
+func (c *Check) GetAlertSettings() *AlertSettings { return &c.AlertSettings }
+func (c *Check) SetAlertSettings(settings *AlertSettings) {
+	c.AlertSettings = AlertSettings{}
+	if settings != nil {
+		c.AlertSettings = *settings
+	}
+}
+
+func (c *MultiStepCheck) GetID() string                    { return c.ID }
+func (c *MultiStepCheck) GetName() string                  { return c.Name }
+func (c *MultiStepCheck) SetName(name string)              { c.Name = name }
+func (c *MultiStepCheck) GetTags() []string                { return c.Tags }
+func (c *MultiStepCheck) SetTags(tags []string)            { c.Tags = tags }
+func (c *MultiStepCheck) GetLocations() []string           { return c.Locations }
+func (c *MultiStepCheck) SetLocations(locations []string)  { c.Locations = locations }
+func (c *MultiStepCheck) GetFrequency() int                { return c.Frequency }
+func (c *MultiStepCheck) SetFrequency(frequency int)       { c.Frequency = frequency }
+func (c *MultiStepCheck) IsActivated() bool                { return c.Activated }
+func (c *MultiStepCheck) SetActivated(activated bool)      { c.Activated = activated }
+func (c *MultiStepCheck) IsMuted() bool                    { return c.Muted }
+func (c *MultiStepCheck) SetMuted(muted bool)              { c.Muted = muted }
+func (c *MultiStepCheck) GetGroupID() int64                { return c.GroupID }
+func (c *MultiStepCheck) SetGroupID(ID int64)              { c.GroupID = ID }
+func (c *MultiStepCheck) GetAlertSettings() *AlertSettings { return &c.AlertSettings }
+func (c *MultiStepCheck) SetAlertSettings(settings *AlertSettings) {
+	c.AlertSettings = AlertSettings{}
+	if settings != nil {
+		c.AlertSettings = *settings
+	}
+}
+
+func (c *PlaywrightCheck) GetID() string                            { return c.ID }
+func (c *PlaywrightCheck) GetName() string                          { return c.Name }
+func (c *PlaywrightCheck) SetName(name string)                      { c.Name = name }
+func (c *PlaywrightCheck) GetTags() []string                        { return c.Tags }
+func (c *PlaywrightCheck) SetTags(tags []string)                    { c.Tags = tags }
+func (c *PlaywrightCheck) GetLocations() []string                   { return c.Locations }
+func (c *PlaywrightCheck) SetLocations(locations []string)          { c.Locations = locations }
+func (c *PlaywrightCheck) GetFrequency() int                        { return c.Frequency }
+func (c *PlaywrightCheck) SetFrequency(frequency int)               { c.Frequency = frequency }
+func (c *PlaywrightCheck) IsActivated() bool                        { return c.Activated }
+func (c *PlaywrightCheck) SetActivated(activated bool)              { c.Activated = activated }
+func (c *PlaywrightCheck) IsMuted() bool                            { return c.Muted }
+func (c *PlaywrightCheck) SetMuted(muted bool)                      { c.Muted = muted }
+func (c *PlaywrightCheck) GetGroupID() int64                        { return c.GroupID }
+func (c *PlaywrightCheck) SetGroupID(ID int64)                      { c.GroupID = ID }
+func (c *PlaywrightCheck) GetAlertSettings() *AlertSettings         { return c.AlertSettings }
+func (c *PlaywrightCheck) SetAlertSettings(settings *AlertSettings) { c.AlertSettings = settings }
+
+func (m *HeartbeatMonitor) GetID() string                    { return m.ID }
+func (m *HeartbeatMonitor) GetName() string                  { return m.Name }
+func (m *HeartbeatMonitor) SetName(name string)              { m.Name = name }
+func (m *HeartbeatMonitor) GetTags() []string                { return m.Tags }
+func (m *HeartbeatMonitor) SetTags(tags []string)            { m.Tags = tags }
+func (m *HeartbeatMonitor) GetLocations() []string           { return nil }
+func (m *HeartbeatMonitor) SetLocations([]string)            {}
+func (m *HeartbeatMonitor) GetFrequency() int                { return 0 }
+func (m *HeartbeatMonitor) SetFrequency(int)                 {}
+func (m *HeartbeatMonitor) IsActivated() bool                { return m.Activated }
+func (m *HeartbeatMonitor) SetActivated(activated bool)      { m.Activated = activated }
+func (m *HeartbeatMonitor) IsMuted() bool                    { return m.Muted }
+func (m *HeartbeatMonitor) SetMuted(muted bool)              { m.Muted = muted }
+func (m *HeartbeatMonitor) GetGroupID() int64                { return 0 }
+func (m *HeartbeatMonitor) SetGroupID(int64)                 {}
+func (m *HeartbeatMonitor) GetAlertSettings() *AlertSettings { return &m.AlertSettings }
+func (m *HeartbeatMonitor) SetAlertSettings(settings *AlertSettings) {
+	m.AlertSettings = AlertSettings{}
+	if settings != nil {
+		m.AlertSettings = *settings
+	}
+}
+
+func (m *TCPMonitor) GetID() string                            { return m.ID }
+func (m *TCPMonitor) GetName() string                          { return m.Name }
+func (m *TCPMonitor) SetName(name string)                      { m.Name = name }
+func (m *TCPMonitor) GetTags() []string                        { return m.Tags }
+func (m *TCPMonitor) SetTags(tags []string)                    { m.Tags = tags }
+func (m *TCPMonitor) GetLocations() []string                   { return m.Locations }
+func (m *TCPMonitor) SetLocations(locations []string)          { m.Locations = locations }
+func (m *TCPMonitor) GetFrequency() int                        { return m.Frequency }
+func (m *TCPMonitor) SetFrequency(frequency int)               { m.Frequency = frequency }
+func (m *TCPMonitor) IsActivated() bool                        { return m.Activated }
+func (m *TCPMonitor) SetActivated(activated bool)              { m.Activated = activated }
+func (m *TCPMonitor) IsMuted() bool                            { return m.Muted }
+func (m *TCPMonitor) SetMuted(muted bool)                      { m.Muted = muted }
+func (m *TCPMonitor) GetGroupID() int64                        { return m.GroupID }
+func (m *TCPMonitor) SetGroupID(ID int64)                      { m.GroupID = ID }
+func (m *TCPMonitor) GetAlertSettings() *AlertSettings         { return m.AlertSettings }
+func (m *TCPMonitor) SetAlertSettings(settings *AlertSettings) { m.AlertSettings = settings }
+
+func (m *URLMonitor) GetID() string                            { return m.ID }
+func (m *URLMonitor) GetName() string                          { return m.Name }
+func (m *URLMonitor) SetName(name string)                      { m.Name = name }
+func (m *URLMonitor) GetTags() []string                        { return m.Tags }
+func (m *URLMonitor) SetTags(tags []string)                    { m.Tags = tags }
+func (m *URLMonitor) GetLocations() []string                   { return m.Locations }
+func (m *URLMonitor) SetLocations(locations []string)          { m.Locations = locations }
+func (m *URLMonitor) GetFrequency() int                        { return m.Frequency }
+func (m *URLMonitor) SetFrequency(frequency int)               { m.Frequency = frequency }
+func (m *URLMonitor) IsActivated() bool                        { return m.Activated }
+func (m *URLMonitor) SetActivated(activated bool)              { m.Activated = activated }
+func (m *URLMonitor) IsMuted() bool                            { return m.Muted }
+func (m *URLMonitor) SetMuted(muted bool)                      { m.Muted = muted }
+func (m *URLMonitor) GetGroupID() int64                        { return m.GroupID }
+func (m *URLMonitor) SetGroupID(ID int64)                      { m.GroupID = ID }
+func (m *URLMonitor) GetAlertSettings() *AlertSettings         { return m.AlertSettings }
+func (m *URLMonitor) SetAlertSettings(settings *AlertSettings) { m.AlertSettings = settings }
+
+func (m *DNSMonitor) GetID() string                            { return m.ID }
+func (m *DNSMonitor) GetName() string                          { return m.Name }
+func (m *DNSMonitor) SetName(name string)                      { m.Name = name }
+func (m *DNSMonitor) GetTags() []string                        { return m.Tags }
+func (m *DNSMonitor) SetTags(tags []string)                    { m.Tags = tags }
+func (m *DNSMonitor) GetLocations() []string                   { return m.Locations }
+func (m *DNSMonitor) SetLocations(locations []string)          { m.Locations = locations }
+func (m *DNSMonitor) GetFrequency() int                        { return m.Frequency }
+func (m *DNSMonitor) SetFrequency(frequency int)               { m.Frequency = frequency }
+func (m *DNSMonitor) IsActivated() bool                        { return m.Activated }
+func (m *DNSMonitor) SetActivated(activated bool)              { m.Activated = activated }
+func (m *DNSMonitor) IsMuted() bool                            { return m.Muted }
+func (m *DNSMonitor) SetMuted(muted bool)                      { m.Muted = muted }
+func (m *DNSMonitor) GetGroupID() int64                        { return m.GroupID }
+func (m *DNSMonitor) SetGroupID(ID int64)                      { m.GroupID = ID }
+func (m *DNSMonitor) GetAlertSettings() *AlertSettings         { return m.AlertSettings }
+func (m *DNSMonitor) SetAlertSettings(settings *AlertSettings) { m.AlertSettings = settings }
+
+func (m *ICMPMonitor) GetID() string                            { return m.ID }
+func (m *ICMPMonitor) GetName() string                          { return m.Name }
+func (m *ICMPMonitor) SetName(name string)                      { m.Name = name }
+func (m *ICMPMonitor) GetTags() []string                        { return m.Tags }
+func (m *ICMPMonitor) SetTags(tags []string)                    { m.Tags = tags }
+func (m *ICMPMonitor) GetLocations() []string                   { return m.Locations }
+func (m *ICMPMonitor) SetLocations(locations []string)          { m.Locations = locations }
+func (m *ICMPMonitor) GetFrequency() int                        { return m.Frequency }
+func (m *ICMPMonitor) SetFrequency(frequency int)               { m.Frequency = frequency }
+func (m *ICMPMonitor) IsActivated() bool                        { return m.Activated }
+func (m *ICMPMonitor) SetActivated(activated bool)              { m.Activated = activated }
+func (m *ICMPMonitor) IsMuted() bool                            { return m.Muted }
+func (m *ICMPMonitor) SetMuted(muted bool)                      { m.Muted = muted }
+func (m *ICMPMonitor) GetGroupID() int64                        { return m.GroupID }
+func (m *ICMPMonitor) SetGroupID(ID int64)                      { m.GroupID = ID }
+func (m *ICMPMonitor) GetAlertSettings() *AlertSettings         { return m.AlertSettings }
+func (m *ICMPMonitor) SetAlertSettings(settings *AlertSettings) { m.AlertSettings = settings }
+
+func (m *GRPCMonitor) GetID() string                            { return m.ID }
+func (m *GRPCMonitor) GetName() string                          { return m.Name }
+func (m *GRPCMonitor) SetName(name string)                      { m.Name = name }
+func (m *GRPCMonitor) GetTags() []string                        { return m.Tags }
+func (m *GRPCMonitor) SetTags(tags []string)                    { m.Tags = tags }
+func (m *GRPCMonitor) GetLocations() []string                   { return m.Locations }
+func (m *GRPCMonitor) SetLocations(locations []string)          { m.Locations = locations }
+func (m *GRPCMonitor) GetFrequency() int                        { return m.Frequency }
+func (m *GRPCMonitor) SetFrequency(frequency int)               { m.Frequency = frequency }
+func (m *GRPCMonitor) IsActivated() bool                        { return m.Activated }
+func (m *GRPCMonitor) SetActivated(activated bool)              { m.Activated = activated }
+func (m *GRPCMonitor) IsMuted() bool                            { return m.Muted }
+func (m *GRPCMonitor) SetMuted(muted bool)                      { m.Muted = muted }
+func (m *GRPCMonitor) GetGroupID() int64                        { return m.GroupID }
+func (m *GRPCMonitor) SetGroupID(ID int64)                      { m.GroupID = ID }
+func (m *GRPCMonitor) GetAlertSettings() *AlertSettings         { return m.AlertSettings }
+func (m *GRPCMonitor) SetAlertSettings(settings *AlertSettings) { m.AlertSettings = settings }
+
+func (m *TracerouteMonitor) GetID() string                            { return m.ID }
+func (m *TracerouteMonitor) GetName() string                          { return m.Name }
+func (m *TracerouteMonitor) SetName(name string)                      { m.Name = name }
+func (m *TracerouteMonitor) GetTags() []string                        { return m.Tags }
+func (m *TracerouteMonitor) SetTags(tags []string)                    { m.Tags = tags }
+func (m *TracerouteMonitor) GetLocations() []string                   { return m.Locations }
+func (m *TracerouteMonitor) SetLocations(locations []string)          { m.Locations = locations }
+func (m *TracerouteMonitor) GetFrequency() int                        { return m.Frequency }
+func (m *TracerouteMonitor) SetFrequency(frequency int)               { m.Frequency = frequency }
+func (m *TracerouteMonitor) IsActivated() bool                        { return m.Activated }
+func (m *TracerouteMonitor) SetActivated(activated bool)              { m.Activated = activated }
+func (m *TracerouteMonitor) IsMuted() bool                            { return m.Muted }
+func (m *TracerouteMonitor) SetMuted(muted bool)                      { m.Muted = muted }
+func (m *TracerouteMonitor) GetGroupID() int64                        { return m.GroupID }
+func (m *TracerouteMonitor) SetGroupID(ID int64)                      { m.GroupID = ID }
+func (m *TracerouteMonitor) GetAlertSettings() *AlertSettings         { return m.AlertSettings }
+func (m *TracerouteMonitor) SetAlertSettings(settings *AlertSettings) { m.AlertSettings = settings }
+
+func (m *SSLMonitor) GetID() string                            { return m.ID }
+func (m *SSLMonitor) GetName() string                          { return m.Name }
+func (m *SSLMonitor) SetName(name string)                      { m.Name = name }
+func (m *SSLMonitor) GetTags() []string                        { return m.Tags }
+func (m *SSLMonitor) SetTags(tags []string)                    { m.Tags = tags }
+func (m *SSLMonitor) GetLocations() []string                   { return m.Locations }
+func (m *SSLMonitor) SetLocations(locations []string)          { m.Locations = locations }
+func (m *SSLMonitor) GetFrequency() int                        { return m.Frequency }
+func (m *SSLMonitor) SetFrequency(frequency int)               { m.Frequency = frequency }
+func (m *SSLMonitor) IsActivated() bool                        { return m.Activated }
+func (m *SSLMonitor) SetActivated(activated bool)              { m.Activated = activated }
+func (m *SSLMonitor) IsMuted() bool                            { return m.Muted }
+func (m *SSLMonitor) SetMuted(muted bool)                      { m.Muted = muted }
+func (m *SSLMonitor) GetGroupID() int64                        { return m.GroupID }
+func (m *SSLMonitor) SetGroupID(ID int64)                      { m.GroupID = ID }
+func (m *SSLMonitor) GetAlertSettings() *AlertSettings         { return m.AlertSettings }
+func (m *SSLMonitor) SetAlertSettings(settings *AlertSettings) { m.AlertSettings = settings }
+
+// UnknownMonitor reads and writes the fields of its raw check, which are
+// assumed to follow the conventions of the known check types.
+
+func (m *UnknownMonitor) GetID() string   { return m.ID }
+func (m *UnknownMonitor) GetName() string { return m.Name }
+func (m *UnknownMonitor) SetName(name string) {
+	m.Name = name
+	m.setField("name", name)
+}
+func (m *UnknownMonitor) GetTags() (tags []string) {
+	m.field("tags", &tags)
+	return tags
+}
+func (m *UnknownMonitor) SetTags(tags []string) { m.setField("tags", tags) }
+func (m *UnknownMonitor) GetLocations() (locations []string) {
+	m.field("locations", &locations)
+	return locations
+}
+func (m *UnknownMonitor) SetLocations(locations []string) { m.setField("locations", locations) }
+func (m *UnknownMonitor) GetFrequency() (frequency int) {
+	m.field("frequency", &frequency)
+	return frequency
+}
+func (m *UnknownMonitor) SetFrequency(frequency int) { m.setField("frequency", frequency) }
+func (m *UnknownMonitor) IsActivated() (activated bool) {
+	m.field("activated", &activated)
+	return activated
+}
+func (m *UnknownMonitor) SetActivated(activated bool) { m.setField("activated", activated) }
+func (m *UnknownMonitor) IsMuted() (muted bool) {
+	m.field("muted", &muted)
+	return muted
+}
+func (m *UnknownMonitor) SetMuted(muted bool) { m.setField("muted", muted) }
+func (m *UnknownMonitor) GetGroupID() (ID int64) {
+	m.field("groupId", &ID)
+	return ID
+}
+func (m *UnknownMonitor) SetGroupID(ID int64) {
+	// GroupID must be null if empty, as for the known check types.
+	if ID == 0 {
+		m.setField("groupId", nil)
+		return
+	}
+	m.setField("groupId", ID)
+}
+func (m *UnknownMonitor) GetAlertSettings() (settings *AlertSettings) {
+	m.field("alertSettings", &settings)
+	return settings
+}
+func (m *UnknownMonitor) SetAlertSettings(settings *AlertSettings) {
+	m.setField("alertSettings", settings)
+}
+
+// field decodes the field key of the raw check into v, leaving v unchanged
+// if the field is missing or cannot be decoded.
+func (m *UnknownMonitor) field(key string, v interface{}) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(m.Raw, &fields); err != nil {
+		return
+	}
+	if raw, ok := fields[key]; ok {
+		json.Unmarshal(raw, v)
+	}
+}
+
+// setField sets the field key of the raw check to v.
+func (m *UnknownMonitor) setField(key string, v interface{}) {
+	fields := map[string]json.RawMessage{}
+	json.Unmarshal(m.Raw, &fields)
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	fields[key] = data
+	if raw, err := json.Marshal(fields); err == nil {
+		m.Raw = raw
+	}
+}
+
+func (*Check) isMonitor()             {}
+func (*MultiStepCheck) isMonitor()    {}
+func (*PlaywrightCheck) isMonitor()   {}
+func (*HeartbeatMonitor) isMonitor()  {}
+func (*TCPMonitor) isMonitor()        {}
+func (*URLMonitor) isMonitor()        {}
+func (*DNSMonitor) isMonitor()        {}
+func (*ICMPMonitor) isMonitor()       {}
+func (*GRPCMonitor) isMonitor()       {}
+func (*TracerouteMonitor) isMonitor() {}
+func (*SSLMonitor) isMonitor()        {}
+func (*UnknownMonitor) isMonitor()    {}
+
+// checkTypeInfo describes how checks of a type are handled by the API and
+// the SDK.
+type checkTypeInfo struct {
+	// endpoint is the type specific endpoint checks are created at.
+	endpoint string
+	// createHint names the Client method to use to create checks of a type
+	// CreateCheck cannot create.
+	createHint string
+	// new returns a pointer to a zero value of the Go type holding checks of
+	// the type.
+	new func() Monitor
+}
+
+// checkTypes maps check types to how they are handled.
+var checkTypes = map[string]checkTypeInfo{
+	TypeAPI: {
+		endpoint: "checks/api",
+		new:      func() Monitor { return &Check{} },
+	},
+	TypeBrowser: {
+		endpoint: "checks/browser",
+		new:      func() Monitor { return &Check{} },
+	},
+	TypeHeartbeat: {
+		endpoint: "checks/heartbeat",
+		new:      func() Monitor { return &HeartbeatMonitor{} },
+	},
+	TypeMultiStep: {
+		endpoint: "checks/multistep",
+		new:      func() Monitor { return &MultiStepCheck{} },
+	},
+	TypePlaywright: {
+		endpoint:   "checks/playwright",
+		createHint: "CreatePlaywrightCheck to create PLAYWRIGHT checks",
+		new:        func() Monitor { return &PlaywrightCheck{} },
+	},
+	TypeTCP: {
+		endpoint:   "checks/tcp",
+		createHint: "CreateTCPMonitor to create TCP monitors",
+		new:        func() Monitor { return &TCPMonitor{} },
+	},
+	TypeURL: {
+		endpoint:   "checks/url",
+		createHint: "CreateURLMonitor to create URL monitors",
+		new:        func() Monitor { return &URLMonitor{} },
+	},
+	TypeDNS: {
+		endpoint:   "checks/dns",
+		createHint: "CreateDNSMonitor to create DNS monitors",
+		new:        func() Monitor { return &DNSMonitor{} },
+	},
+	TypeICMP: {
+		endpoint:   "checks/icmp",
+		createHint: "CreateICMPMonitor to create ICMP monitors",
+		new:        func() Monitor { return &ICMPMonitor{} },
+	},
+	TypeGRPC: {
+		endpoint:   "checks/grpc",
+		createHint: "CreateGRPCMonitor to create GRPC monitors",
+		new:        func() Monitor { return &GRPCMonitor{} },
+	},
+	TypeTraceroute: {
+		endpoint:   "checks/traceroute",
+		createHint: "CreateTracerouteMonitor to create TRACEROUTE monitors",
+		new:        func() Monitor { return &TracerouteMonitor{} },
+	},
+	TypeSSL: {
+		endpoint:   "checks/ssl",
+		createHint: "CreateSSLMonitor to create SSL monitors",
+		new:        func() Monitor { return &SSLMonitor{} },
+	},
+}
+
+// CreateCheckError returns the error CreateCheck returns for checks of
+// checkType, or nil for the types it creates. It allows implementations of
+// Client other than the one returned by New, such as test fakes, to reject
+// the same checks.
+func CreateCheckError(checkType string) error {
+	info, ok := checkTypes[checkType]
+	if !ok {
+		return fmt.Errorf("unknown check type: %s", checkType)
+	}
+	if info.createHint != "" {
+		return fmt.Errorf("user error: use %s", info.createHint)
+	}
+	return nil
+}
+
+// DecodeMonitor decodes a check as returned by the API into the concrete
+// type matching its checkType, or into an *UnknownMonitor for check types
+// this version of the SDK does not know about.
+func DecodeMonitor(data []byte) (Monitor, error) {
+	var header struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+		Type string `json:"checkType"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, fmt.Errorf("decoding error for data %s: %v", data, err)
+	}
+	info, ok := checkTypes[header.Type]
+	if !ok {
+		return &UnknownMonitor{
+			ID:   header.ID,
+			Name: header.Name,
+			Type: header.Type,
+			Raw:  append(json.RawMessage(nil), data...),
+		}, nil
+	}
+	monitor := info.new()
+	if err := json.Unmarshal(data, monitor); err != nil {
+		return nil, fmt.Errorf("decoding error for data %s: %v", data, err)
+	}
+	return monitor, nil
+}
+
+// CreateMonitor creates monitor with the Client method matching its type,
+// for example CreateTCPMonitor for a *TCPMonitor, and returns the created
+// monitor, or an error.
+func CreateMonitor[T Monitor](ctx context.Context, c Client, monitor T) (T, error) {
+	var result Monitor
+	var err error
+	if isNilMonitor(monitor) {
+		return monitorResult[T](nil, errNilMonitor)
+	}
+	switch m := Monitor(monitor).(type) {
+	case *Check:
+		result, err = c.CreateCheck(ctx, *m)
+	case *MultiStepCheck:
+		result, err = c.CreateMultiStepCheck(ctx, *m)
+	case *PlaywrightCheck:
+		result, err = c.CreatePlaywrightCheck(ctx, *m)
+	case *HeartbeatMonitor:
+		result, err = c.CreateHeartbeatMonitor(ctx, *m)
+	case *TCPMonitor:
+		result, err = c.CreateTCPMonitor(ctx, *m)
+	case *URLMonitor:
+		result, err = c.CreateURLMonitor(ctx, *m)
+	case *DNSMonitor:
+		result, err = c.CreateDNSMonitor(ctx, *m)
+	case *ICMPMonitor:
+		result, err = c.CreateICMPMonitor(ctx, *m)
+	case *GRPCMonitor:
+		result, err = c.CreateGRPCMonitor(ctx, *m)
+	case *TracerouteMonitor:
+		result, err = c.CreateTracerouteMonitor(ctx, *m)
+	case *SSLMonitor:
+		result, err = c.CreateSSLMonitor(ctx, *m)
+	default:
+		err = unsupportedMonitorError(monitor)
+	}
+	return monitorResult[T](result, err)
+}
+
+// UpdateMonitor updates the existing check identified by monitor.GetID() to
+// match monitor, with the Client method matching its type, and returns the
+// updated monitor, or an error.
+func UpdateMonitor[T Monitor](ctx context.Context, c Client, monitor T) (T, error) {
+	var result Monitor
+	var err error
+	if isNilMonitor(monitor) {
+		return monitorResult[T](nil, errNilMonitor)
+	}
+	ID := monitor.GetID()
+	switch m := Monitor(monitor).(type) {
+	case *Check:
+		result, err = c.UpdateCheck(ctx, ID, *m)
+	case *MultiStepCheck:
+		result, err = c.UpdateMultiStepCheck(ctx, ID, *m)
+	case *PlaywrightCheck:
+		result, err = c.UpdatePlaywrightCheck(ctx, ID, *m)
+	case *HeartbeatMonitor:
+		result, err = c.UpdateHeartbeatMonitor(ctx, ID, *m)
+	case *TCPMonitor:
+		result, err = c.UpdateTCPMonitor(ctx, ID, *m)
+	case *URLMonitor:
+		result, err = c.UpdateURLMonitor(ctx, ID, *m)
+	case *DNSMonitor:
+		result, err = c.UpdateDNSMonitor(ctx, ID, *m)
+	case *ICMPMonitor:
+		result, err = c.UpdateICMPMonitor(ctx, ID, *m)
+	case *GRPCMonitor:
+		result, err = c.UpdateGRPCMonitor(ctx, ID, *m)
+	case *TracerouteMonitor:
+		result, err = c.UpdateTracerouteMonitor(ctx, ID, *m)
+	case *SSLMonitor:
+		result, err = c.UpdateSSLMonitor(ctx, ID, *m)
+	default:
+		err = unsupportedMonitorError(monitor)
+	}
+	return monitorResult[T](result, err)
+}
+
+// DeleteMonitor deletes the check identified by monitor.GetID() with the
+// Client method matching its type.
+func DeleteMonitor(ctx context.Context, c Client, monitor Monitor) error {
+	if isNilMonitor(monitor) {
+		return errNilMonitor
+	}
+	ID := monitor.GetID()
+	switch monitor.(type) {
+	case *Check:
+		return c.DeleteCheck(ctx, ID)
+	case *MultiStepCheck:
+		return c.DeleteMultiStepCheck(ctx, ID)
+	case *PlaywrightCheck:
+		return c.DeletePlaywrightCheck(ctx, ID)
+	case *HeartbeatMonitor:
+		return c.DeleteHeartbeatMonitor(ctx, ID)
+	case *TCPMonitor:
+		return c.DeleteTCPMonitor(ctx, ID)
+	case *URLMonitor:
+		return c.DeleteURLMonitor(ctx, ID)
+	case *DNSMonitor:
+		return c.DeleteDNSMonitor(ctx, ID)
+	case *ICMPMonitor:
+		return c.DeleteICMPMonitor(ctx, ID)
+	case *GRPCMonitor:
+		return c.DeleteGRPCMonitor(ctx, ID)
+	case *TracerouteMonitor:
+		return c.DeleteTracerouteMonitor(ctx, ID)
+	case *SSLMonitor:
+		return c.DeleteSSLMonitor(ctx, ID)
+	default:
+		return unsupportedMonitorError(monitor)
+	}
+}
+
+// GetMonitor takes the ID of an existing check, and returns it as a T, or an
+// error if the check is of another type:
+//
+//	monitor, err := checkly.GetMonitor[*checkly.TCPMonitor](ctx, client, ID)
+func GetMonitor[T Monitor](ctx context.Context, c Client, ID string) (T, error) {
+	var zero T
+	monitor, err := c.GetAnyCheck(ctx, ID)
+	if err != nil {
+		return zero, err
+	}
+	result, ok := monitor.(T)
+	if !ok {
+		return zero, fmt.Errorf("check %s is of type %s, not %T", ID, monitor.CheckType(), zero)
+	}
+	return result, nil
+}
+
+func monitorResult[T Monitor](result Monitor, err error) (T, error) {
+	var zero T
+	if err != nil {
+		return zero, err
+	}
+	return result.(T), nil
+}
+
+var errNilMonitor = errors.New("user error: monitor is nil")
+
+// isNilMonitor reports whether monitor is nil, or a nil pointer to a monitor
+// type.
+func isNilMonitor(monitor Monitor) bool {
+	if monitor == nil {
+		return true
+	}
+	v := reflect.ValueOf(monitor)
+	return v.Kind() == reflect.Pointer && v.IsNil()
+}
+
+func unsupportedMonitorError(monitor Monitor) error {
+	return fmt.Errorf("user error: %s checks (%T) are not supported", monitor.CheckType(), monitor)
+}
@@ -0,0 +1,199 @@
+package checkly_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	checkly "github.com/checkly/checkly-go-sdk"
+)
+
+func TestMonitorAccessors(t *testing.T) {
+	t.Parallel()
+	monitors := []checkly.Monitor{
+		&checkly.Check{Type: checkly.TypeAPI},
+		&checkly.MultiStepCheck{},
+		&checkly.PlaywrightCheck{},
+		&checkly.TCPMonitor{},
+		&checkly.URLMonitor{},
+		&checkly.DNSMonitor{},
+		&checkly.ICMPMonitor{},
+		&checkly.GRPCMonitor{},
+		&checkly.TracerouteMonitor{},
+		&checkly.SSLMonitor{},
+		&checkly.UnknownMonitor{Type: "QUANTUM", Raw: json.RawMessage(`{}`)},
+	}
+	settings := &checkly.AlertSettings{EscalationType: checkly.RunBased}
+	for _, m := range monitors {
+		m := m
+		t.Run(m.CheckType(), func(t *testing.T) {
+			t.Parallel()
+			m.SetName("name")
+			m.SetTags([]string{"tag"})
+			m.SetLocations([]string{"eu-west-1"})
+			m.SetFrequency(5)
+			m.SetActivated(true)
+			m.SetMuted(true)
+			m.SetGroupID(42)
+			m.SetAlertSettings(settings)
+			if m.GetName() != "name" {
+				t.Errorf("want name %q, got %q", "name", m.GetName())
+			}
+			if !cmp.Equal([]string{"tag"}, m.GetTags()) {
+				t.Error(cmp.Diff([]string{"tag"}, m.GetTags()))
+			}
+			if !cmp.Equal([]string{"eu-west-1"}, m.GetLocations()) {
+				t.Error(cmp.Diff([]string{"eu-west-1"}, m.GetLocations()))
+			}
+			if m.GetFrequency() != 5 {
+				t.Errorf("want frequency 5, got %d", m.GetFrequency())
+			}
+			if !m.IsActivated() || !m.IsMuted() {
+				t.Error("want monitor to be activated and muted")
+			}
+			if m.GetGroupID() != 42 {
+				t.Errorf("want group ID 42, got %d", m.GetGroupID())
+			}
+			if !cmp.Equal(settings, m.GetAlertSettings()) {
+				t.Error(cmp.Diff(settings, m.GetAlertSettings()))
+			}
+		})
+	}
+}
+
+func TestHeartbeatMonitorAccessors(t *testing.T) {
+	t.Parallel()
+	var m checkly.Monitor = &checkly.HeartbeatMonitor{}
+	m.SetName("heartbeat")
+	m.SetLocations([]string{"eu-west-1"})
+	m.SetFrequency(5)
+	m.SetGroupID(42)
+	m.SetAlertSettings(nil)
+	if m.GetName() != "heartbeat" {
+		t.Errorf("want name %q, got %q", "heartbeat", m.GetName())
+	}
+	if m.GetLocations() != nil || m.GetFrequency() != 0 || m.GetGroupID() != 0 {
+		t.Error("want locations, frequency and group to be ignored for heartbeat monitors")
+	}
+	if m.GetAlertSettings() == nil {
+		t.Error("want heartbeat monitors to always have alert settings")
+	}
+}
+
+func TestUnknownMonitorSettersUpdateRaw(t *testing.T) {
+	t.Parallel()
+	m := &checkly.UnknownMonitor{
+		Type: "QUANTUM",
+		Raw:  json.RawMessage(`{"checkType":"QUANTUM","groupId":7,"superposition":true}`),
+	}
+	m.SetGroupID(0)
+	m.SetMuted(true)
+	var got map[string]interface{}
+	if err := json.Unmarshal(m.Raw, &got); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"checkType":     "QUANTUM",
+		"groupId":       nil,
+		"muted":         true,
+		"superposition": true,
+	}
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestCreateMonitor(t *testing.T) {
+	t.Parallel()
+	ts := cannedResponseServer(t,
+		http.MethodPost,
+		"/v1/checks/url?autoAssignAlerts=false",
+		validateURLMonitor,
+		http.StatusCreated,
+		"CreateURLMonitor.json",
+	)
+	defer ts.Close()
+	client := checkly.NewClient(ts.URL, "dummy-key", ts.Client(), nil)
+	monitor := testURLMonitor
+	// The result has the static type of the argument.
+	var response *checkly.URLMonitor
+	response, err := checkly.CreateMonitor(context.Background(), client, &monitor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(testURLMonitor, *response, ignoreURLMonitorFields) {
+		t.Error(cmp.Diff(testURLMonitor, *response, ignoreURLMonitorFields))
+	}
+}
+
+func TestDeleteMonitor(t *testing.T) {
+	t.Parallel()
+	ts := cannedResponseServer(t,
+		http.MethodDelete,
+		fmt.Sprintf("/v1/checks/%s", testURLMonitor.ID),
+		validateEmptyBody,
+		http.StatusNoContent,
+		"Empty.json",
+	)
+	defer ts.Close()
+	client := checkly.NewClient(ts.URL, "dummy-key", ts.Client(), nil)
+	monitor := testURLMonitor
+	if err := checkly.DeleteMonitor(context.Background(), client, &monitor); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCreateMonitorUnsupportedType(t *testing.T) {
+	t.Parallel()
+	client := checkly.NewClient("http://localhost", "dummy-key", nil, nil)
+	_, err := checkly.CreateMonitor(context.Background(), client, &checkly.UnknownMonitor{Type: "QUANTUM"})
+	if err == nil {
+		t.Error("want error for an unknown check type, got nil")
+	}
+}
+
+func TestMonitorFunctionsRejectNil(t *testing.T) {
+	t.Parallel()
+	client := checkly.NewClient("http://localhost", "dummy-key", nil, nil)
+	ctx := context.Background()
+	var tcp *checkly.TCPMonitor
+	if _, err := checkly.CreateMonitor(ctx, client, tcp); err == nil {
+		t.Error("want CreateMonitor to fail for a nil *TCPMonitor")
+	}
+	if _, err := checkly.UpdateMonitor[checkly.Monitor](ctx, client, nil); err == nil {
+		t.Error("want UpdateMonitor to fail for a nil Monitor")
+	}
+	if err := checkly.DeleteMonitor(ctx, client, nil); err == nil {
+		t.Error("want DeleteMonitor to fail for a nil Monitor")
+	}
+	if err := checkly.DeleteMonitor(ctx, client, tcp); err == nil {
+		t.Error("want DeleteMonitor to fail for a nil *TCPMonitor")
+	}
+}
+
+func TestGetMonitor(t *testing.T) {
+	t.Parallel()
+	ts := cannedResponseServer(t,
+		http.MethodGet,
+		fmt.Sprintf("/v1/checks/%s", wantCheckID),
+		validateEmptyBody,
+		http.StatusOK,
+		"GetURLMonitor.json",
+	)
+	defer ts.Close()
+	client := checkly.NewClient(ts.URL, "dummy-key", ts.Client(), nil)
+	monitor, err := checkly.GetMonitor[*checkly.URLMonitor](context.Background(), client, wantCheckID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if monitor.Request.URL == "" {
+		t.Error("want URL monitor request to be decoded")
+	}
+	if _, err := checkly.GetMonitor[*checkly.TCPMonitor](context.Background(), client, wantCheckID); err == nil {
+		t.Error("want error when getting a URL monitor as a TCP monitor, got nil")
+	}
+}
@@ -0,0 +1,170 @@
+package checkly
+
+import (
+	"io"
+	"net/http"
+	"time"
+)
+
+// DefaultBaseURL is the base URL of the public Checkly API, used by New unless
+// another one is configured with WithBaseURL or CHECKLY_API_URL.
+const DefaultBaseURL = "https://api.checklyhq.com"
+
+// Option configures a client constructed with New.
+type Option func(*options)
+
+type options struct {
+	apiKey      string
+	accountID   string
+	baseURL     string
+	httpClient  *http.Client
+	debug       io.Writer
+	source      string
+	userAgent   string
+	retryPolicy RetryPolicy
+	rateLimiter *RateLimiter
+	timeout     time.Duration
+	validate    bool
+}
+
+// New constructs a Checkly API client configured by the given options, which
+// are applied in order so that later options override earlier ones:
+//
+//	client := checkly.New(
+//		checkly.FromEnvironment(),
+//		checkly.WithRetryPolicy(checkly.DefaultRetryPolicy()),
+//	)
+//
+// Without options the client talks to DefaultBaseURL using
+// http.DefaultClient, and has neither an API key nor an account ID set.
+func New(opts ...Option) Client {
+	o := options{
+		baseURL: DefaultBaseURL,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	httpClient := o.httpClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	if o.timeout > 0 {
+		// Copy the HTTP client rather than changing the timeout of one that
+		// may be shared, like http.DefaultClient.
+		withTimeout := *httpClient
+		withTimeout.Timeout = o.timeout
+		httpClient = &withTimeout
+	}
+	return &client{
+		apiKey:      o.apiKey,
+		url:         o.baseURL,
+		accountId:   o.accountID,
+		source:      o.source,
+		userAgent:   o.userAgent,
+		httpClient:  httpClient,
+		debug:       o.debug,
+		retryPolicy: o.retryPolicy,
+		rateLimiter: o.rateLimiter,
+		validate:    o.validate,
+	}
+}
+
+// WithAPIKey sets the API key used to authenticate requests.
+func WithAPIKey(apiKey string) Option {
+	return func(o *options) {
+		o.apiKey = apiKey
+	}
+}
+
+// WithAccountID sets the account ID, which is required when using User API
+// keys.
+func WithAccountID(accountID string) Option {
+	return func(o *options) {
+		o.accountID = accountID
+	}
+}
+
+// WithBaseURL sets the base URL of the Checkly API, defaults to
+// DefaultBaseURL.
+func WithBaseURL(baseURL string) Option {
+	return func(o *options) {
+		o.baseURL = baseURL
+	}
+}
+
+// WithHTTPClient sets the HTTP client used to send requests, defaults to
+// http.DefaultClient.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(o *options) {
+		o.httpClient = httpClient
+	}
+}
+
+// WithDebug dumps all API requests and responses to w when it is non-nil.
+func WithDebug(w io.Writer) Option {
+	return func(o *options) {
+		o.debug = w
+	}
+}
+
+// WithSource sets the x-checkly-source header sent for analytics, defaults to
+// "go-sdk".
+func WithSource(source string) Option {
+	return func(o *options) {
+		o.source = source
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) Option {
+	return func(o *options) {
+		o.userAgent = userAgent
+	}
+}
+
+// WithRetryPolicy sets the policy used to retry failed API requests, see
+// SetRetryPolicy.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(o *options) {
+		o.retryPolicy = policy
+	}
+}
+
+// WithRateLimiter sets a rate limiter that every API request waits on, see
+// SetRateLimiter.
+func WithRateLimiter(limiter *RateLimiter) Option {
+	return func(o *options) {
+		o.rateLimiter = limiter
+	}
+}
+
+// WithTimeout sets the time limit for a single HTTP request, including
+// reading the response body. The HTTP client passed to WithHTTPClient is
+// copied rather than modified.
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.timeout = timeout
+	}
+}
+
+// WithValidation validates checks and monitors with their Validate method
+// before creating or updating them, returning a ValidationErrors instead of
+// sending an invalid payload to the API. The runtime of multistep checks is
+// looked up to check that it supports them.
+func WithValidation() Option {
+	return func(o *options) {
+		o.validate = true
+	}
+}
+
+// FromEnvironment reads the API key, account ID and base URL from the
+// CHECKLY_API_KEY, CHECKLY_ACCOUNT_ID and CHECKLY_API_URL environment
+// variables. Variables which are not set leave the corresponding setting
+// unchanged.
+func FromEnvironment() Option {
+	return func(o *options) {
+		o.apiKey = getEnv("CHECKLY_API_KEY", o.apiKey)
+		o.accountID = getEnv("CHECKLY_ACCOUNT_ID", o.accountID)
+		o.baseURL = getEnv("CHECKLY_API_URL", o.baseURL)
+	}
+}
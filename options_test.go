@@ -0,0 +1,132 @@
+package checkly_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	checkly "github.com/checkly/checkly-go-sdk"
+)
+
+// headerCapturingServer replies to every request with GetCheck.json and
+// stores the headers of the last request received.
+func headerCapturingServer(t *testing.T, header *http.Header) *httptest.Server {
+	ts, _ := scriptedResponseServer(t, validateEmptyBody,
+		scriptedResponse{status: http.StatusOK, filename: "GetCheck.json"},
+	)
+	handler := ts.Config.Handler
+	ts.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*header = r.Header.Clone()
+		handler.ServeHTTP(w, r)
+	})
+	return ts
+}
+
+func TestNewWithOptions(t *testing.T) {
+	t.Parallel()
+	var header http.Header
+	ts := headerCapturingServer(t, &header)
+	defer ts.Close()
+	client := checkly.New(
+		checkly.WithBaseURL(ts.URL),
+		checkly.WithHTTPClient(ts.Client()),
+		checkly.WithAPIKey("cu_dummy-key"),
+		checkly.WithAccountID("account-id"),
+		checkly.WithSource("terraform"),
+		checkly.WithUserAgent("checkly-test/1.0"),
+		checkly.WithTimeout(10*time.Second),
+	)
+	if _, err := client.GetCheck(context.Background(), wantCheckID); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"Authorization":     "Bearer cu_dummy-key",
+		"X-Checkly-Account": "account-id",
+		"X-Checkly-Source":  "terraform",
+		"User-Agent":        "checkly-test/1.0",
+	}
+	for k, v := range want {
+		if got := header.Get(k); got != v {
+			t.Errorf("want header %s to be %q, got %q", k, v, got)
+		}
+	}
+	if ts.Client().Timeout != 0 {
+		t.Error("want WithTimeout to leave the HTTP client passed in unchanged")
+	}
+}
+
+func TestNewLaterOptionsOverride(t *testing.T) {
+	t.Parallel()
+	var header http.Header
+	ts := headerCapturingServer(t, &header)
+	defer ts.Close()
+	client := checkly.New(
+		checkly.WithBaseURL("https://example.com"),
+		checkly.WithAPIKey("first-key"),
+		checkly.WithBaseURL(ts.URL),
+		checkly.WithAPIKey("second-key"),
+		checkly.WithHTTPClient(ts.Client()),
+	)
+	if _, err := client.GetCheck(context.Background(), wantCheckID); err != nil {
+		t.Fatal(err)
+	}
+	if got := header.Get("Authorization"); got != "Bearer second-key" {
+		t.Errorf("want the last API key to be used, got %q", got)
+	}
+	if got := header.Get("X-Checkly-Source"); got != "go-sdk" {
+		t.Errorf("want default source %q, got %q", "go-sdk", got)
+	}
+}
+
+func TestFromEnvironment(t *testing.T) {
+	var header http.Header
+	ts := headerCapturingServer(t, &header)
+	defer ts.Close()
+	t.Setenv("CHECKLY_API_URL", ts.URL)
+	t.Setenv("CHECKLY_API_KEY", "env-key")
+	t.Setenv("CHECKLY_ACCOUNT_ID", "env-account")
+	client := checkly.New(
+		checkly.WithAccountID("overridden"),
+		checkly.FromEnvironment(),
+		checkly.WithHTTPClient(ts.Client()),
+	)
+	if _, err := client.GetCheck(context.Background(), wantCheckID); err != nil {
+		t.Fatal(err)
+	}
+	if got := header.Get("Authorization"); got != "Bearer env-key" {
+		t.Errorf("want API key from the environment, got %q", got)
+	}
+	if got := header.Get("X-Checkly-Account"); got != "env-account" {
+		t.Errorf("want account ID from the environment, got %q", got)
+	}
+}
+
+func TestFromEnvironmentKeepsUnsetValues(t *testing.T) {
+	var header http.Header
+	ts := headerCapturingServer(t, &header)
+	defer ts.Close()
+	t.Setenv("CHECKLY_API_KEY", "env-key")
+	for _, key := range []string{"CHECKLY_API_URL", "CHECKLY_ACCOUNT_ID"} {
+		// t.Setenv restores the original value when the test ends.
+		t.Setenv(key, "")
+		os.Unsetenv(key)
+	}
+	client := checkly.New(
+		checkly.WithBaseURL(ts.URL),
+		checkly.WithAccountID("account-id"),
+		checkly.FromEnvironment(),
+		checkly.WithHTTPClient(ts.Client()),
+	)
+	if _, err := client.GetCheck(context.Background(), wantCheckID); err != nil {
+		t.Fatal(err)
+	}
+	if got := header.Get("Authorization"); got != "Bearer env-key" {
+		t.Errorf("want API key from the environment, got %q", got)
+	}
+	if got := header.Get("X-Checkly-Account"); got != "account-id" {
+		t.Errorf("want account ID to be kept, got %q", got)
+	}
+}
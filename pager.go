@@ -0,0 +1,297 @@
+package checkly
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// defaultPageSize is the number of items requested per page when none is
+// configured. It is also the maximum the API accepts.
+const defaultPageSize = 100
+
+// Pager iterates over the items of a paginated list endpoint, fetching pages
+// from the API as they are needed:
+//
+//	pager := client.ListChecks(ctx, checkly.ListChecksOptions{})
+//	for pager.Next(ctx) {
+//		fmt.Println(pager.Value().CheckType())
+//	}
+//	if err := pager.Err(); err != nil {
+//		return err
+//	}
+//
+// While the items of a page are being consumed, the next page is fetched in
+// the background using the context passed to the Next call that received the
+// current page. Both page/limit and cursor based pagination are supported.
+//
+// A Pager is not safe for concurrent use.
+type Pager[T any] struct {
+	// fetch fetches the page identified by token, the empty token being the
+	// first page, and returns the token of the next page or "" for the last
+	// one.
+	fetch    func(ctx context.Context, token string) (items []T, next string, err error)
+	maxItems int
+	count    int
+	items    []T
+	value    T
+	next     string
+	last     bool
+	pending  chan pageResult[T]
+	err      error
+}
+
+type pageResult[T any] struct {
+	items []T
+	next  string
+	err   error
+}
+
+// NewPager returns a Pager calling fetch to get each page, starting with the
+// empty token, until fetch returns an empty next token. It allows
+// implementations of Client other than the one returned by New, such as test
+// fakes, to return Pagers.
+func NewPager[T any](
+	opts ListOptions,
+	fetch func(ctx context.Context, token string) ([]T, string, error),
+) *Pager[T] {
+	return &Pager[T]{
+		fetch:    fetch,
+		maxItems: opts.MaxItems,
+	}
+}
+
+// Next advances to the next item, fetching the next page when the current
+// one is exhausted. It returns false when there are no more items, the
+// MaxItems limit is reached, ctx is done or an error occurred, which Err then
+// returns.
+func (p *Pager[T]) Next(ctx context.Context) bool {
+	if p.err != nil {
+		return false
+	}
+	if err := ctx.Err(); err != nil {
+		p.err = err
+		return false
+	}
+	if p.maxItems > 0 && p.count >= p.maxItems {
+		return false
+	}
+	for len(p.items) == 0 {
+		if p.last {
+			return false
+		}
+		var res pageResult[T]
+		if p.pending != nil {
+			select {
+			case res = <-p.pending:
+			case <-ctx.Done():
+				res.err = ctx.Err()
+			}
+			p.pending = nil
+		} else {
+			res.items, res.next, res.err = p.fetch(ctx, p.next)
+		}
+		if res.err != nil {
+			p.err = res.err
+			return false
+		}
+		p.items, p.next, p.last = res.items, res.next, res.next == ""
+		if !p.last && (p.maxItems <= 0 || p.count+len(p.items) < p.maxItems) {
+			p.prefetch(ctx)
+		}
+	}
+	p.value, p.items = p.items[0], p.items[1:]
+	p.count++
+	return true
+}
+
+// prefetch starts fetching the next page in the background.
+func (p *Pager[T]) prefetch(ctx context.Context) {
+	// The channel is buffered so that the goroutine doesn't leak when the
+	// iteration is abandoned before the page is received.
+	pending := make(chan pageResult[T], 1)
+	go func(token string) {
+		var res pageResult[T]
+		res.items, res.next, res.err = p.fetch(ctx, token)
+		pending <- res
+	}(p.next)
+	p.pending = pending
+}
+
+// Value returns the current item. It is only valid after Next returned true.
+func (p *Pager[T]) Value() T {
+	return p.value
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (p *Pager[T]) Err() error {
+	return p.err
+}
+
+// Collect iterates over the remaining items and returns them, or the error
+// that stopped the iteration along with the items received until then.
+func (p *Pager[T]) Collect(ctx context.Context) ([]T, error) {
+	var result []T
+	for p.Next(ctx) {
+		result = append(result, p.Value())
+	}
+	return result, p.Err()
+}
+
+// pageSize returns the page size to request for opts.
+func (opts ListOptions) pageSize() int {
+	if opts.PageSize <= 0 || opts.PageSize > defaultPageSize {
+		return defaultPageSize
+	}
+	return opts.PageSize
+}
+
+// newListPager returns a Pager over the items of a list endpoint paginated
+// with the page and limit query parameters. query holds any other query
+// parameters, and decode decodes a single item, reporting false for items
+// that are to be skipped.
+func newListPager[T any](
+	c *client,
+	opts ListOptions,
+	path string,
+	query url.Values,
+	decode func(data []byte) (T, bool, error),
+) *Pager[T] {
+	pageSize := opts.pageSize()
+	return NewPager(opts, func(ctx context.Context, token string) ([]T, string, error) {
+		page := 1
+		if token != "" {
+			page, _ = strconv.Atoi(token)
+		}
+		q := cloneValues(query)
+		q.Set("limit", strconv.Itoa(pageSize))
+		q.Set("page", strconv.Itoa(page))
+		items, _, err := c.fetchListPage(ctx, path+"?"+q.Encode())
+		if err != nil {
+			return nil, "", err
+		}
+		result, err := decodeListItems(items, decode)
+		if err != nil || len(items) < pageSize {
+			return result, "", err
+		}
+		return result, strconv.Itoa(page + 1), nil
+	})
+}
+
+// newCursorListPager returns a Pager over the items of a list endpoint
+// paginated with a cursor: every page holds the ID to pass as the nextId
+// query parameter to get the following page.
+func newCursorListPager[T any](
+	c *client,
+	opts ListOptions,
+	path string,
+	decode func(data []byte) (T, bool, error),
+) *Pager[T] {
+	pageSize := opts.pageSize()
+	return NewPager(opts, func(ctx context.Context, token string) ([]T, string, error) {
+		q := url.Values{}
+		q.Set("limit", strconv.Itoa(pageSize))
+		if token != "" {
+			q.Set("nextId", token)
+		}
+		items, next, err := c.fetchListPage(ctx, path+"?"+q.Encode())
+		if err != nil {
+			return nil, "", err
+		}
+		result, err := decodeListItems(items, decode)
+		if err != nil {
+			return nil, "", err
+		}
+		return result, next, nil
+	})
+}
+
+// newUnpaginatedListPager returns a Pager over the items of a list endpoint
+// which returns all items at once.
+func newUnpaginatedListPager[T any](
+	c *client,
+	opts ListOptions,
+	path string,
+	decode func(data []byte) (T, bool, error),
+) *Pager[T] {
+	return NewPager(opts, func(ctx context.Context, _ string) ([]T, string, error) {
+		items, _, err := c.fetchListPage(ctx, path)
+		if err != nil {
+			return nil, "", err
+		}
+		result, err := decodeListItems(items, decode)
+		return result, "", err
+	})
+}
+
+// fetchListPage fetches a page of a list endpoint and returns its items
+// undecoded, along with the cursor of the next page if any. Both plain JSON
+// arrays and objects with the items in an "entries" field are accepted.
+func (c *client) fetchListPage(ctx context.Context, URL string) ([]json.RawMessage, string, error) {
+	status, res, err := c.apiCall(
+		ctx,
+		http.MethodGet,
+		URL,
+		nil,
+	)
+	if err != nil {
+		return nil, "", err
+	}
+	if status != http.StatusOK {
+		return nil, "", fmt.Errorf("unexpected response status %d: %q", status, res)
+	}
+	var envelope struct {
+		Entries []json.RawMessage `json:"entries"`
+		NextID  *string           `json:"nextId"`
+	}
+	if strings.HasPrefix(strings.TrimSpace(res), "{") {
+		err = json.NewDecoder(strings.NewReader(res)).Decode(&envelope)
+	} else {
+		err = json.NewDecoder(strings.NewReader(res)).Decode(&envelope.Entries)
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("decoding error for data %s: %v", res, err)
+	}
+	if envelope.NextID == nil {
+		return envelope.Entries, "", nil
+	}
+	return envelope.Entries, *envelope.NextID, nil
+}
+
+func decodeListItems[T any](
+	items []json.RawMessage,
+	decode func(data []byte) (T, bool, error),
+) ([]T, error) {
+	result := make([]T, 0, len(items))
+	for _, item := range items {
+		value, ok, err := decode(item)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			result = append(result, value)
+		}
+	}
+	return result, nil
+}
+
+// decodeListItem decodes a list item into a T.
+func decodeListItem[T any](data []byte) (T, bool, error) {
+	var result T
+	if err := json.Unmarshal(data, &result); err != nil {
+		return result, false, fmt.Errorf("decoding error for data %s: %v", data, err)
+	}
+	return result, true, nil
+}
+
+func cloneValues(values url.Values) url.Values {
+	result := url.Values{}
+	for k, v := range values {
+		result[k] = append([]string(nil), v...)
+	}
+	return result
+}
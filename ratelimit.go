@@ -0,0 +1,147 @@
+package checkly
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimiter is a token bucket limiting the rate of requests sent to the
+// Checkly API. It is safe for concurrent use, and a single RateLimiter can be
+// shared between several clients using the same account so that they draw
+// from one budget.
+//
+// The limiter adapts its rate downward when the API reports that the rate
+// limit is (nearly) exhausted through the X-RateLimit-Remaining and
+// X-RateLimit-Reset headers, or responds with HTTP 429, and recovers towards
+// the configured rate afterwards.
+type RateLimiter struct {
+	mu          sync.Mutex
+	maxRate     float64 // configured requests per second
+	rate        float64 // current, possibly adapted, requests per second
+	burst       float64
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time
+}
+
+// NewRateLimiter returns a RateLimiter allowing requestsPerSecond requests per
+// second on average, with bursts of up to burst requests. It returns an error
+// unless requestsPerSecond is greater than zero; a burst lower than 1 is
+// treated as 1.
+func NewRateLimiter(requestsPerSecond float64, burst int) (*RateLimiter, error) {
+	if !(requestsPerSecond > 0) {
+		return nil, fmt.Errorf("user error: the rate of a RateLimiter must be greater than zero, got %v", requestsPerSecond)
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{
+		maxRate: requestsPerSecond,
+		rate:    requestsPerSecond,
+		burst:   float64(burst),
+		tokens:  float64(burst),
+		last:    time.Now(),
+	}, nil
+}
+
+// Rate returns the current number of requests per second allowed by the
+// limiter, which is lower than the configured rate after the API signalled
+// that the rate limit is close to being exhausted.
+func (l *RateLimiter) Rate() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.rate
+}
+
+// Wait blocks until a request may be sent, or until ctx is done in which case
+// the context's error is returned.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	l.advance(now)
+	// Reserve a token up front; the balance may become negative, which makes
+	// later callers queue up behind this one.
+	l.tokens--
+	var delay time.Duration
+	if l.tokens < 0 {
+		delay = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	if pause := l.pausedUntil.Sub(now); pause > 0 {
+		delay += pause
+	}
+	l.mu.Unlock()
+
+	if err := sleepContext(ctx, delay); err != nil {
+		// Hand the reservation back so that it doesn't delay other callers.
+		l.mu.Lock()
+		l.tokens = math.Min(l.tokens+1, l.burst)
+		l.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// advance refills the bucket for the time elapsed since the last call. It
+// must be called with l.mu held.
+func (l *RateLimiter) advance(now time.Time) {
+	elapsed := now.Sub(l.last).Seconds()
+	if elapsed <= 0 {
+		return
+	}
+	l.last = now
+	l.tokens = math.Min(l.tokens+elapsed*l.rate, l.burst)
+}
+
+// minRate is the lowest rate the limiter adapts down to.
+func (l *RateLimiter) minRate() float64 {
+	return math.Min(l.maxRate, 0.1)
+}
+
+// observe adapts the limiter to the rate limit information of an API
+// response.
+func (l *RateLimiter) observe(statusCode int, header http.Header) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	l.advance(now)
+
+	if statusCode == http.StatusTooManyRequests {
+		// Multiplicative decrease, and drop any burst credit we still had.
+		l.rate = math.Max(l.rate/2, l.minRate())
+		l.tokens = math.Min(l.tokens, 0)
+		if d, ok := serverRetryDelay(header); ok {
+			l.pause(now.Add(d))
+		}
+		return
+	}
+
+	remaining, err := strconv.Atoi(header.Get(headerRateLimitRemaining))
+	reset, ok := parseRateLimitReset(header.Get(headerRateLimitReset))
+	if err != nil || !ok {
+		// Additive increase back towards the configured rate.
+		l.rate = math.Min(l.rate+l.maxRate/10, l.maxRate)
+		return
+	}
+	if remaining <= 0 {
+		l.pause(now.Add(reset))
+		return
+	}
+	// Spread the remaining budget evenly over what is left of the window.
+	serverRate := math.Inf(1)
+	if reset > 0 {
+		serverRate = float64(remaining) / reset.Seconds()
+	}
+	l.rate = math.Max(math.Min(serverRate, l.maxRate), l.minRate())
+}
+
+// pause stops handing out tokens until t. It must be called with l.mu held.
+func (l *RateLimiter) pause(t time.Time) {
+	if t.After(l.pausedUntil) {
+		l.pausedUntil = t
+	}
+}
@@ -0,0 +1,166 @@
+package checkly_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	checkly "github.com/checkly/checkly-go-sdk"
+)
+
+func mustRateLimiter(t *testing.T, requestsPerSecond float64, burst int) *checkly.RateLimiter {
+	t.Helper()
+	limiter, err := checkly.NewRateLimiter(requestsPerSecond, burst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return limiter
+}
+
+func TestRateLimiterWait(t *testing.T) {
+	t.Parallel()
+	limiter := mustRateLimiter(t, 50, 1)
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		if err := limiter.Wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The first token is available immediately, the other four are spaced
+	// 20ms apart.
+	if elapsed := time.Since(start); elapsed < 70*time.Millisecond {
+		t.Errorf("want 5 requests at 50/s to take at least 80ms, took %s", elapsed)
+	}
+}
+
+func TestRateLimiterBurst(t *testing.T) {
+	t.Parallel()
+	limiter := mustRateLimiter(t, 1, 5)
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	for i := 0; i < 5; i++ {
+		if err := limiter.Wait(ctx); err != nil {
+			t.Fatalf("want burst of 5 requests to pass, request %d failed: %v", i+1, err)
+		}
+	}
+}
+
+func TestNewRateLimiterInvalidRate(t *testing.T) {
+	t.Parallel()
+	for _, rate := range []float64{0, -1, math.NaN()} {
+		if limiter, err := checkly.NewRateLimiter(rate, 1); err == nil || limiter != nil {
+			t.Errorf("want an error for NewRateLimiter(%v, 1), got %v", rate, limiter)
+		}
+	}
+}
+
+func TestRateLimiterContextCancellation(t *testing.T) {
+	t.Parallel()
+	limiter := mustRateLimiter(t, 0.01, 1)
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := limiter.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("want context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestRateLimiterSharedBetweenClients(t *testing.T) {
+	t.Parallel()
+	ts, _ := scriptedResponseServer(t, validateEmptyBody,
+		scriptedResponse{status: http.StatusOK, filename: "GetCheck.json"},
+	)
+	defer ts.Close()
+	limiter := mustRateLimiter(t, 100, 1)
+	clients := []checkly.Client{
+		checkly.NewClient(ts.URL, "dummy-key", ts.Client(), nil),
+		checkly.NewClient(ts.URL, "dummy-key", ts.Client(), nil),
+	}
+	start := time.Now()
+	var wg sync.WaitGroup
+	for _, client := range clients {
+		client.SetRateLimiter(limiter)
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func(client checkly.Client) {
+				defer wg.Done()
+				if _, err := client.GetCheck(context.Background(), wantCheckID); err != nil {
+					t.Error(err)
+				}
+			}(client)
+		}
+	}
+	wg.Wait()
+	// 10 requests at 100/s with a burst of 1 take at least 90ms in total.
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+		t.Errorf("want 10 requests to take at least 90ms, took %s", elapsed)
+	}
+}
+
+func TestRateLimiterPausesWhenExhausted(t *testing.T) {
+	t.Parallel()
+	ts, _ := scriptedResponseServer(t, validateEmptyBody,
+		scriptedResponse{
+			status: http.StatusOK,
+			header: map[string]string{
+				"X-RateLimit-Limit":     "60",
+				"X-RateLimit-Remaining": "0",
+				"X-RateLimit-Reset":     "0.2",
+			},
+			filename: "GetCheck.json",
+		},
+		scriptedResponse{status: http.StatusOK, filename: "GetCheck.json"},
+	)
+	defer ts.Close()
+	client := checkly.NewClient(ts.URL, "dummy-key", ts.Client(), nil)
+	client.SetRateLimiter(mustRateLimiter(t, 1000, 10))
+	if _, err := client.GetCheck(context.Background(), wantCheckID); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	if _, err := client.GetCheck(context.Background(), wantCheckID); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Errorf("want request to wait for the rate limit reset, took %s", elapsed)
+	}
+}
+
+func TestRateLimiterAdaptsDownward(t *testing.T) {
+	t.Parallel()
+	tcs := map[string]scriptedResponse{
+		"too many requests": {
+			status:   http.StatusTooManyRequests,
+			filename: "Empty.json",
+		},
+		"remaining budget": {
+			status: http.StatusOK,
+			header: map[string]string{
+				"X-RateLimit-Remaining": "10",
+				"X-RateLimit-Reset":     "10",
+			},
+			filename: "GetCheck.json",
+		},
+	}
+	for name, res := range tcs {
+		res := res
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ts, _ := scriptedResponseServer(t, validateEmptyBody, res)
+			defer ts.Close()
+			client := checkly.NewClient(ts.URL, "dummy-key", ts.Client(), nil)
+			limiter := mustRateLimiter(t, 20, 1)
+			client.SetRateLimiter(limiter)
+			client.GetCheck(context.Background(), wantCheckID)
+			if rate := limiter.Rate(); rate >= 20 {
+				t.Errorf("want rate below 20/s after response, got %v", rate)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package checkly
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Rate limit headers sent by the Checkly API.
+const (
+	headerRetryAfter         = "Retry-After"
+	headerRateLimitRemaining = "X-RateLimit-Remaining"
+	headerRateLimitReset     = "X-RateLimit-Reset"
+)
+
+// RetryPolicy configures how the client retries API requests that failed with
+// a transient error: a network error, HTTP 429 (Too Many Requests), 502, 503
+// or 504.
+//
+// The zero value disables retries.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts for a request, including
+	// the first one. Values lower than 2 disable retries.
+	MaxAttempts int
+
+	// MinBackoff is the base delay before the first retry. The delay doubles
+	// with every further attempt, and a random jitter of up to half the delay
+	// is subtracted from it.
+	MinBackoff time.Duration
+
+	// MaxBackoff caps the exponential backoff delay, zero meaning no cap.
+	// Delays requested by the API through the Retry-After or
+	// X-RateLimit-Reset headers are honoured as is.
+	MaxBackoff time.Duration
+
+	// RetryNonIdempotent enables retries for POST and PATCH requests. They
+	// are not retried by default, as a request that failed in flight may
+	// still have created the resource.
+	RetryNonIdempotent bool
+}
+
+// DefaultRetryPolicy returns the recommended retry policy: up to 4 attempts
+// with an exponential backoff between 500ms and 30s, for idempotent requests
+// only.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 4,
+		MinBackoff:  500 * time.Millisecond,
+		MaxBackoff:  30 * time.Second,
+	}
+}
+
+// shouldRetry reports whether a request with the given method may be sent
+// again after the given (1-based) attempt failed.
+func (p RetryPolicy) shouldRetry(method string, attempt int) bool {
+	if attempt >= p.MaxAttempts {
+		return false
+	}
+	return isIdempotent(method) || p.RetryNonIdempotent
+}
+
+// backoff returns the jittered delay to wait after the given (1-based)
+// attempt failed.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.MinBackoff
+	for i := 1; i < attempt; i++ {
+		if (p.MaxBackoff > 0 && delay >= p.MaxBackoff) || delay > math.MaxInt64/2 {
+			break
+		}
+		delay *= 2
+	}
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+	if delay <= 0 {
+		return 0
+	}
+	jitter := time.Duration(rand.Int63n(int64(delay/2) + 1))
+	return delay - jitter
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions,
+		http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+func isRetryableStatus(statusCode int) bool {
+	switch statusCode {
+	case http.StatusTooManyRequests,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// serverRetryDelay returns how long the API asked us to wait before sending
+// another request, based on the Retry-After header or, when the rate limit is
+// exhausted, the X-RateLimit-Reset header.
+func serverRetryDelay(header http.Header) (time.Duration, bool) {
+	if d, ok := parseRetryAfter(header.Get(headerRetryAfter)); ok {
+		return d, true
+	}
+	if header.Get(headerRateLimitRemaining) == "0" {
+		return parseRateLimitReset(header.Get(headerRateLimitReset))
+	}
+	return 0, false
+}
+
+// parseRetryAfter parses a Retry-After header, which is either a number of
+// seconds or an HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		d := time.Until(date)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
+
+// parseRateLimitReset parses an X-RateLimit-Reset header. The value is
+// normally the number of seconds until the rate limit window resets, but
+// Unix timestamps are accepted as well.
+func parseRateLimitReset(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	seconds, err := strconv.ParseFloat(value, 64)
+	if err != nil || seconds < 0 {
+		return 0, false
+	}
+	// Anything beyond a year is assumed to be a Unix timestamp.
+	if seconds > 365*24*60*60 {
+		d := time.Until(time.Unix(int64(seconds), 0))
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return time.Duration(seconds * float64(time.Second)), true
+}
+
+// sleepContext waits for d, or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
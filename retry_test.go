@@ -0,0 +1,211 @@
+package checkly_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	checkly "github.com/checkly/checkly-go-sdk"
+)
+
+type scriptedResponse struct {
+	status   int
+	header   map[string]string
+	filename string
+}
+
+// scriptedResponseServer replies to consecutive requests with the given
+// responses, repeating the last one once the script is exhausted. The returned
+// counter holds the number of requests received.
+func scriptedResponseServer(
+	t *testing.T,
+	validate func(*testing.T, []byte),
+	responses ...scriptedResponse,
+) (*httptest.Server, *int32) {
+	var calls int32
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(atomic.AddInt32(&calls, 1))
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		validate(t, body)
+		res := responses[len(responses)-1]
+		if n <= len(responses) {
+			res = responses[n-1]
+		}
+		for k, v := range res.header {
+			w.Header().Set(k, v)
+		}
+		w.WriteHeader(res.status)
+		data, err := os.Open(fmt.Sprintf("fixtures/%s", res.filename))
+		if err != nil {
+			t.Error(err)
+		}
+		defer data.Close()
+		io.Copy(w, data)
+	}))
+	return ts, &calls
+}
+
+var testRetryPolicy = checkly.RetryPolicy{
+	MaxAttempts: 3,
+	MinBackoff:  time.Millisecond,
+	MaxBackoff:  5 * time.Millisecond,
+}
+
+func TestRetryTransientErrors(t *testing.T) {
+	t.Parallel()
+	ts, calls := scriptedResponseServer(t, validateEmptyBody,
+		scriptedResponse{status: http.StatusServiceUnavailable, filename: "Empty.json"},
+		scriptedResponse{status: http.StatusBadGateway, filename: "Empty.json"},
+		scriptedResponse{status: http.StatusOK, filename: "GetCheck.json"},
+	)
+	defer ts.Close()
+	client := checkly.NewClient(ts.URL, "dummy-key", ts.Client(), nil)
+	client.SetRetryPolicy(testRetryPolicy)
+	_, err := client.GetCheck(context.Background(), wantCheckID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := atomic.LoadInt32(calls); got != 3 {
+		t.Errorf("want 3 attempts, got %d", got)
+	}
+}
+
+func TestRetryGivesUpAfterMaxAttempts(t *testing.T) {
+	t.Parallel()
+	ts, calls := scriptedResponseServer(t, validateEmptyBody,
+		scriptedResponse{status: http.StatusServiceUnavailable, filename: "Empty.json"},
+	)
+	defer ts.Close()
+	client := checkly.NewClient(ts.URL, "dummy-key", ts.Client(), nil)
+	client.SetRetryPolicy(testRetryPolicy)
+	_, err := client.GetCheck(context.Background(), wantCheckID)
+	var apiErr *checkly.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("want a 503 APIError, got %v", err)
+	}
+	if got := atomic.LoadInt32(calls); got != 3 {
+		t.Errorf("want 3 attempts, got %d", got)
+	}
+}
+
+func TestRetryDisabledByDefault(t *testing.T) {
+	t.Parallel()
+	ts, calls := scriptedResponseServer(t, validateEmptyBody,
+		scriptedResponse{status: http.StatusServiceUnavailable, filename: "Empty.json"},
+		scriptedResponse{status: http.StatusOK, filename: "GetCheck.json"},
+	)
+	defer ts.Close()
+	client := checkly.NewClient(ts.URL, "dummy-key", ts.Client(), nil)
+	if _, err := client.GetCheck(context.Background(), wantCheckID); err == nil {
+		t.Fatal("want error without a retry policy, got nil")
+	}
+	if got := atomic.LoadInt32(calls); got != 1 {
+		t.Errorf("want 1 attempt, got %d", got)
+	}
+}
+
+func TestRetrySkipsNonIdempotentRequests(t *testing.T) {
+	t.Parallel()
+	ts, calls := scriptedResponseServer(t, validateAnything,
+		scriptedResponse{status: http.StatusServiceUnavailable, filename: "Empty.json"},
+		scriptedResponse{status: http.StatusCreated, filename: "CreateCheck.json"},
+	)
+	defer ts.Close()
+	client := checkly.NewClient(ts.URL, "dummy-key", ts.Client(), nil)
+	client.SetRetryPolicy(testRetryPolicy)
+	if _, err := client.CreateCheck(context.Background(), wantCheck); err == nil {
+		t.Fatal("want error for a POST without RetryNonIdempotent, got nil")
+	}
+	if got := atomic.LoadInt32(calls); got != 1 {
+		t.Errorf("want 1 attempt, got %d", got)
+	}
+}
+
+func TestRetryNonIdempotentRewindsBody(t *testing.T) {
+	t.Parallel()
+	// validateCheck fails the test if any attempt is sent without the full
+	// request body.
+	ts, calls := scriptedResponseServer(t, validateCheck,
+		scriptedResponse{status: http.StatusTooManyRequests, filename: "Empty.json"},
+		scriptedResponse{status: http.StatusCreated, filename: "CreateCheck.json"},
+	)
+	defer ts.Close()
+	client := checkly.NewClient(ts.URL, "dummy-key", ts.Client(), nil)
+	policy := testRetryPolicy
+	policy.RetryNonIdempotent = true
+	client.SetRetryPolicy(policy)
+	if _, err := client.CreateCheck(context.Background(), wantCheck); err != nil {
+		t.Fatal(err)
+	}
+	if got := atomic.LoadInt32(calls); got != 2 {
+		t.Errorf("want 2 attempts, got %d", got)
+	}
+}
+
+func TestRetryHonoursServerDelay(t *testing.T) {
+	t.Parallel()
+	tcs := map[string]map[string]string{
+		"Retry-After": {
+			"Retry-After": "0",
+		},
+		"X-RateLimit-Reset": {
+			"X-RateLimit-Remaining": "0",
+			"X-RateLimit-Reset":     "0",
+		},
+	}
+	for name, header := range tcs {
+		header := header
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ts, _ := scriptedResponseServer(t, validateEmptyBody,
+				scriptedResponse{status: http.StatusTooManyRequests, header: header, filename: "Empty.json"},
+				scriptedResponse{status: http.StatusOK, filename: "GetCheck.json"},
+			)
+			defer ts.Close()
+			client := checkly.NewClient(ts.URL, "dummy-key", ts.Client(), nil)
+			// The backoff alone would outlast the context, so the request only
+			// succeeds if the server provided delay is used instead.
+			client.SetRetryPolicy(checkly.RetryPolicy{
+				MaxAttempts: 2,
+				MinBackoff:  time.Hour,
+			})
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if _, err := client.GetCheck(ctx, wantCheckID); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestRetryStopsOnContextCancellation(t *testing.T) {
+	t.Parallel()
+	ts, calls := scriptedResponseServer(t, validateEmptyBody,
+		scriptedResponse{status: http.StatusServiceUnavailable, filename: "Empty.json"},
+	)
+	defer ts.Close()
+	client := checkly.NewClient(ts.URL, "dummy-key", ts.Client(), nil)
+	client.SetRetryPolicy(checkly.RetryPolicy{
+		MaxAttempts: 10,
+		MinBackoff:  time.Hour,
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := client.GetCheck(ctx, wantCheckID)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("want context.DeadlineExceeded, got %v", err)
+	}
+	if got := atomic.LoadInt32(calls); got != 1 {
+		t.Errorf("want 1 attempt, got %d", got)
+	}
+}
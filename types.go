@@ -151,6 +151,13 @@ type Client interface {
 		check PlaywrightCheck,
 	) (*PlaywrightCheck, error)
 
+	// CreateMultiStepCheck creates a new multistep check with the specified
+	// details.
+	CreateMultiStepCheck(
+		ctx context.Context,
+		check MultiStepCheck,
+	) (*MultiStepCheck, error)
+
 	// Update updates an existing check with the specified details.
 	// It returns the updated check, or an error.
 	UpdateCheck(
@@ -248,6 +255,14 @@ type Client interface {
 		check PlaywrightCheck,
 	) (*PlaywrightCheck, error)
 
+	// UpdateMultiStepCheck updates an existing multistep check with the
+	// specified details.
+	UpdateMultiStepCheck(
+		ctx context.Context,
+		ID string,
+		check MultiStepCheck,
+	) (*MultiStepCheck, error)
+
 	// Delete deletes the check with the specified ID.
 	DeleteCheck(
 		ctx context.Context,
@@ -308,6 +323,12 @@ type Client interface {
 		ID string,
 	) error
 
+	// DeleteMultiStepCheck deletes the multistep check with the specified ID.
+	DeleteMultiStepCheck(
+		ctx context.Context,
+		ID string,
+	) error
+
 	// Get takes the ID of an existing check, and returns the check parameters,
 	// or an error.
 	GetCheck(
@@ -379,6 +400,28 @@ type Client interface {
 		ID string,
 	) (*PlaywrightCheck, error)
 
+	// GetMultiStepCheck takes the ID of an existing multistep check, and
+	// returns the check parameters, or an error.
+	GetMultiStepCheck(
+		ctx context.Context,
+		ID string,
+	) (*MultiStepCheck, error)
+
+	// GetAnyCheck takes the ID of an existing check or monitor of any type,
+	// and returns it decoded into the concrete type matching its check type,
+	// for example *Check or *TCPMonitor, or an error.
+	GetAnyCheck(
+		ctx context.Context,
+		ID string,
+	) (Monitor, error)
+
+	// ListChecks returns a Pager over all checks and monitors matching opts,
+	// each decoded into the concrete type matching its check type.
+	ListChecks(
+		ctx context.Context,
+		opts ListChecksOptions,
+	) *Pager[Monitor]
+
 	// CreateGroup creates a new check group with the specified details.
 	// It returns the newly-created group, or an error.
 	CreateGroup(
@@ -400,6 +443,12 @@ type Client interface {
 		ID int64,
 	) (*Group, error)
 
+	// ListGroups returns a Pager over all check groups in the account.
+	ListGroups(
+		ctx context.Context,
+		opts ListOptions,
+	) *Pager[Group]
+
 	// GetGroupV2 takes the ID of an existing check group, and returns the
 	// corresponding group, or an error.
 	GetGroupV2(
@@ -407,6 +456,13 @@ type Client interface {
 		ID int64,
 	) (*GroupV2, error)
 
+	// ListGroupsV2 returns a Pager over all check groups in the account, decoded as
+	// GroupV2.
+	ListGroupsV2(
+		ctx context.Context,
+		opts ListOptions,
+	) *Pager[GroupV2]
+
 	// UpdateGroup takes the ID of an existing check group, and updates the
 	// corresponding check group to match the supplied group. It returns the updated
 	// group, or an error.
@@ -445,12 +501,25 @@ type Client interface {
 	) (*CheckResult, error)
 
 	// GetCheckResults gets the results of the given Check
+	//
+	// Deprecated: GetCheckResults only returns a single page of results, use
+	// ListCheckResults instead.
 	GetCheckResults(
 		ctx context.Context,
 		checkID string,
 		filters *CheckResultsFilter,
 	) ([]CheckResult, error)
 
+	// ListCheckResults returns a Pager over the results of the given Check
+	// matching filters, whose Page and Limit fields are ignored in favour of
+	// opts.
+	ListCheckResults(
+		ctx context.Context,
+		checkID string,
+		filters CheckResultsFilter,
+		opts ListOptions,
+	) *Pager[CheckResult]
+
 	// CreateSnippet creates a new snippet with the specified details. It returns
 	// the newly-created snippet, or an error.
 	CreateSnippet(
@@ -465,6 +534,12 @@ type Client interface {
 		ID int64,
 	) (*Snippet, error)
 
+	// ListSnippets returns a Pager over all snippets in the account.
+	ListSnippets(
+		ctx context.Context,
+		opts ListOptions,
+	) *Pager[Snippet]
+
 	// UpdateSnippet takes the ID of an existing snippet, and updates the
 	// corresponding snippet to match the supplied snippet. It returns the updated
 	// snippet, or an error.
@@ -495,6 +570,12 @@ type Client interface {
 		key string,
 	) (*EnvironmentVariable, error)
 
+	// ListEnvironmentVariables returns a Pager over all environment variables in the account.
+	ListEnvironmentVariables(
+		ctx context.Context,
+		opts ListOptions,
+	) *Pager[EnvironmentVariable]
+
 	// UpdateEnvironmentVariable takes the ID of an existing environment variable, and updates the
 	// corresponding environment variable to match the supplied environment variable. It returns the updated
 	// environment variable, or an error.
@@ -524,6 +605,12 @@ type Client interface {
 		ID int64,
 	) (*AlertChannel, error)
 
+	// ListAlertChannels returns a Pager over all alert channels in the account.
+	ListAlertChannels(
+		ctx context.Context,
+		opts ListOptions,
+	) *Pager[AlertChannel]
+
 	// UpdateAlertChannel takes the ID of an existing alert channel, and updates the
 	// corresponding alert channel to match the supplied alert channel. It returns the updated
 	// alert channel, or an error.
@@ -551,6 +638,12 @@ type Client interface {
 		ID string,
 	) (*Dashboard, error)
 
+	// ListDashboards returns a Pager over all dashboards in the account.
+	ListDashboards(
+		ctx context.Context,
+		opts ListOptions,
+	) *Pager[Dashboard]
+
 	// UpdateDashboard takes the ID of an existing dashboard, and updates the
 	// corresponding dashboard to match the supplied dashboard.
 	UpdateDashboard(
@@ -577,6 +670,12 @@ type Client interface {
 		ID int64,
 	) (*MaintenanceWindow, error)
 
+	// ListMaintenanceWindows returns a Pager over all maintenance windows in the account.
+	ListMaintenanceWindows(
+		ctx context.Context,
+		opts ListOptions,
+	) *Pager[MaintenanceWindow]
+
 	// UpdateMaintenanceWindow takes the ID of an existing maintenance window, and updates the
 	// corresponding maintenance window to match the supplied maintenance window.
 	UpdateMaintenanceWindow(
@@ -603,6 +702,13 @@ type Client interface {
 		ID string,
 	) (*PrivateLocation, error)
 
+	// ListPrivateLocations returns a Pager over all private locations in the account.
+	// The API returns them in one response, so PageSize has no effect.
+	ListPrivateLocations(
+		ctx context.Context,
+		opts ListOptions,
+	) *Pager[PrivateLocation]
+
 	// UpdatePrivateLocation takes the ID of an existing private location and updates it
 	// to match the new one.
 	UpdatePrivateLocation(
@@ -666,6 +772,12 @@ type Client interface {
 		ID string,
 	) (*ClientCertificate, error)
 
+	// ListClientCertificates returns a Pager over all client certificates in the account.
+	ListClientCertificates(
+		ctx context.Context,
+		opts ListOptions,
+	) *Pager[ClientCertificate]
+
 	// DeleteClientCertificate deletes a client certificate.
 	DeleteClientCertificate(
 		ctx context.Context,
@@ -685,6 +797,12 @@ type Client interface {
 		ID string,
 	) (*StatusPage, error)
 
+	// ListStatusPages returns a Pager over all status pages in the account.
+	ListStatusPages(
+		ctx context.Context,
+		opts ListOptions,
+	) *Pager[StatusPage]
+
 	// UpdateStatusPage updates a status page.
 	UpdateStatusPage(
 		ctx context.Context,
@@ -711,6 +829,12 @@ type Client interface {
 		ID string,
 	) (*StatusPageService, error)
 
+	// ListStatusPageServices returns a Pager over all status page services in the account.
+	ListStatusPageServices(
+		ctx context.Context,
+		opts ListOptions,
+	) *Pager[StatusPageService]
+
 	// UpdateStatusPageService updates a status page service.
 	UpdateStatusPageService(
 		ctx context.Context,
@@ -730,6 +854,13 @@ type Client interface {
 	// SetChecklySource sets the source of the check for analytics purposes.
 	SetChecklySource(source string)
 
+	// SetRetryPolicy sets the policy used to retry failed API requests.
+	SetRetryPolicy(policy RetryPolicy)
+
+	// SetRateLimiter sets a rate limiter that every API request waits on.
+	// The same limiter may be shared by several clients.
+	SetRateLimiter(limiter *RateLimiter)
+
 	// Get a specific runtime specs.
 	GetRuntime(
 		ctx context.Context,
@@ -761,12 +892,16 @@ type Client interface {
 // a timeout), assign to the HTTPClient field. To set a non-default URL (for
 // example, for testing), assign to the URL field.
 type client struct {
-	apiKey     string
-	url        string
-	accountId  string
-	source     string
-	httpClient *http.Client
-	debug      io.Writer
+	apiKey      string
+	url         string
+	accountId   string
+	source      string
+	userAgent   string
+	httpClient  *http.Client
+	debug       io.Writer
+	retryPolicy RetryPolicy
+	rateLimiter *RateLimiter
+	validate    bool
 }
 
 // Check type constants
@@ -781,6 +916,33 @@ const TypeAPI = "API"
 // TypeHeartbeat is used to identify a browser check.
 const TypeHeartbeat = "HEARTBEAT"
 
+// TypeMultiStep is used to identify a multistep check.
+const TypeMultiStep = "MULTI_STEP"
+
+// TypePlaywright is used to identify a Playwright check.
+const TypePlaywright = "PLAYWRIGHT"
+
+// TypeTCP is used to identify a TCP monitor.
+const TypeTCP = "TCP"
+
+// TypeURL is used to identify a URL monitor.
+const TypeURL = "URL"
+
+// TypeDNS is used to identify a DNS monitor.
+const TypeDNS = "DNS"
+
+// TypeICMP is used to identify an ICMP monitor.
+const TypeICMP = "ICMP"
+
+// TypeGRPC is used to identify a gRPC monitor.
+const TypeGRPC = "GRPC"
+
+// TypeTraceroute is used to identify a traceroute monitor.
+const TypeTraceroute = "TRACEROUTE"
+
+// TypeSSL is used to identify an SSL monitor.
+const TypeSSL = "SSL"
+
 // Escalation type constants
 
 // RunBased identifies a run-based escalation type, for use with an AlertSettings.
@@ -825,6 +987,30 @@ const JSONResponse = "JSON_RESPONSE"
 // assertion property) as an assertion source, for use with an SSL monitor.
 const TextResponse = "TEXT_RESPONSE"
 
+// ResponseCode identifies the response code as an assertion source, for use
+// with a DNS monitor.
+const ResponseCode = "RESPONSE_CODE"
+
+// TextAnswer identifies the answers as text as an assertion source, for use
+// with a DNS monitor.
+const TextAnswer = "TEXT_ANSWER"
+
+// JSONAnswer identifies a JSONPath over the answers (via the assertion
+// property) as an assertion source, for use with a DNS monitor.
+const JSONAnswer = "JSON_ANSWER"
+
+// Latency identifies a latency statistic (via the assertion property) as an
+// assertion source, for use with ICMP and traceroute monitors.
+const Latency = "LATENCY"
+
+// Hops identifies the number of hops as an assertion source, for use with a
+// traceroute monitor.
+const Hops = "HOPS"
+
+// GRPCStatus identifies the gRPC status code as an assertion source, for use
+// with a gRPC monitor.
+const GRPCStatus = "GRPC_STATUS"
+
 // Assertion comparison constants
 
 // Equals asserts that the source and target are equal.
@@ -902,7 +1088,7 @@ type Check struct {
 	DoubleCheck bool `json:"doubleCheck"`
 }
 
-// Check represents the parameters for an existing check.
+// MultiStepCheck represents a multistep check.
 type MultiStepCheck struct {
 	ID                        string                     `json:"id"`
 	Name                      string                     `json:"name"`
@@ -915,6 +1101,8 @@ type MultiStepCheck struct {
 	ShouldFail                bool                       `json:"shouldFail"`
 	RunParallel               bool                       `json:"runParallel"`
 	Locations                 []string                   `json:"locations"`
+	DegradedResponseTime      int                        `json:"degradedResponseTime,omitempty"`
+	MaxResponseTime           int                        `json:"maxResponseTime,omitempty"`
 	Script                    string                     `json:"script,omitempty"`
 	EnvironmentVariables      []EnvironmentVariable      `json:"environmentVariables"`
 	Tags                      []string                   `json:"tags,omitempty"`
@@ -1752,6 +1940,34 @@ type CheckResultsFilter struct {
 	HasFailures bool
 }
 
+// ListOptions represents the parameters that can be passed to the List
+// methods.
+type ListOptions struct {
+	// PageSize is the number of items requested per page, up to and
+	// defaulting to 100.
+	PageSize int
+	// MaxItems stops the iteration after that many items, zero meaning no
+	// limit.
+	MaxItems int
+}
+
+// ListChecksOptions represents the filters that can be passed while listing
+// checks. Filters are combined, and zero values match every check.
+type ListChecksOptions struct {
+	ListOptions
+
+	// Tags only matches checks having all of the given tags.
+	Tags []string
+	// CheckType only matches checks of the given type, for example TypeTCP.
+	CheckType string
+	// GroupID only matches checks in the given group.
+	GroupID int64
+	// Activated only matches activated (true) or deactivated (false) checks.
+	Activated *bool
+	// Muted only matches muted (true) or unmuted (false) checks.
+	Muted *bool
+}
+
 // Snippet defines Snippet type
 type Snippet struct {
 	ID        int64     `json:"id"`
@@ -0,0 +1,343 @@
+package checkly
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// FieldError is an invalid field of a monitor found by its Validate method.
+type FieldError struct {
+	// Path is the path of the field in the JSON payload, in the format the
+	// API uses in APIErrorValidation.Keys, e.g. "request.assertions.0.source".
+	Path string
+
+	// Message describes what is wrong with the field.
+	Message string
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// ValidationErrors lists every invalid field of a monitor. It is returned by
+// the Validate methods of the monitor types, and by the client before sending
+// an invalid monitor when it was configured WithValidation. Use errors.As to
+// inspect it, or IsValidationError to classify it.
+type ValidationErrors []*FieldError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return "invalid monitor: " + strings.Join(messages, "; ")
+}
+
+// Unwrap returns the errors of the individual fields.
+func (e ValidationErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+// allowedFrequencies are the check frequencies, in minutes, accepted by the
+// API. 0 runs a check every FrequencyOffset seconds, which only API checks and
+// monitors support.
+var allowedFrequencies = []int{0, 1, 2, 5, 10, 15, 30, 60, 120, 180, 360, 720, 1440}
+
+// assertionSources are the assertion sources accepted by the API, by check
+// type.
+var assertionSources = map[string][]string{
+	TypeAPI:        {StatusCode, JSONBody, TextBody, Headers, ResponseTime},
+	TypeURL:        {StatusCode},
+	TypeTCP:        {ResponseData, ResponseTime},
+	TypeDNS:        {ResponseCode, ResponseTime, TextAnswer, JSONAnswer, JSONResponse},
+	TypeICMP:       {Latency, JSONResponse},
+	TypeGRPC:       {GRPCStatus, ResponseTime, JSONResponse, TextResponse},
+	TypeTraceroute: {Latency, Hops, ResponseTime, JSONResponse, TextResponse},
+	TypeSSL:        {Certificate, Connection, JSONResponse, TextResponse},
+}
+
+// heartbeatUnits are the units of the period and grace of heartbeat
+// monitors.
+var heartbeatUnits = []string{"seconds", "minutes", "hours", "days"}
+
+// validator collects the invalid fields of a monitor.
+type validator struct {
+	errs ValidationErrors
+}
+
+func (v *validator) add(path, format string, args ...any) {
+	v.errs = append(v.errs, &FieldError{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return v.errs
+}
+
+func (v *validator) required(path, value string) {
+	if value == "" {
+		v.add(path, "is required")
+	}
+}
+
+func (v *validator) oneOf(path, value string, allowed ...string) {
+	if value != "" && !contains(allowed, value) {
+		v.add(path, "must be one of %s", strings.Join(allowed, ", "))
+	}
+}
+
+func (v *validator) name(name string) {
+	v.required("name", name)
+}
+
+func (v *validator) frequency(frequency int) {
+	v.frequencyIn(frequency, allowedFrequencies)
+}
+
+// minuteFrequency checks the frequency of browser-based checks, which cannot
+// run more often than every minute.
+func (v *validator) minuteFrequency(frequency int) {
+	v.frequencyIn(frequency, allowedFrequencies[1:])
+}
+
+func (v *validator) frequencyIn(frequency int, allowed []int) {
+	for _, f := range allowed {
+		if frequency == f {
+			return
+		}
+	}
+	v.add("frequency", "must be one of %v", allowed)
+}
+
+func (v *validator) ipFamily(path, family string) {
+	v.oneOf(path, family, "IPv4", "IPv6")
+}
+
+func (v *validator) assertions(checkType string, assertions []Assertion) {
+	for i, a := range assertions {
+		path := fmt.Sprintf("request.assertions.%d.source", i)
+		if !contains(assertionSources[checkType], a.Source) {
+			v.add(path, "%q is not a valid source for %s checks, use one of %s",
+				a.Source, checkType, strings.Join(assertionSources[checkType], ", "))
+		}
+	}
+}
+
+func (v *validator) heartbeat(h Heartbeat) {
+	if h.Period <= 0 {
+		v.add("heartbeat.period", "must be greater than 0")
+	}
+	if h.Grace < 0 {
+		v.add("heartbeat.grace", "must not be negative")
+	}
+	v.required("heartbeat.periodUnit", h.PeriodUnit)
+	v.oneOf("heartbeat.periodUnit", h.PeriodUnit, heartbeatUnits...)
+	v.required("heartbeat.graceUnit", h.GraceUnit)
+	v.oneOf("heartbeat.graceUnit", h.GraceUnit, heartbeatUnits...)
+}
+
+// Validate checks c for mistakes the API would reject, and returns a
+// ValidationErrors listing every invalid field, or nil.
+func (c *Check) Validate() error {
+	var v validator
+	v.name(c.Name)
+	switch c.Type {
+	case TypeAPI:
+		v.frequency(c.Frequency)
+		v.required("request.url", c.Request.URL)
+		v.ipFamily("request.ipFamily", c.Request.IPFamily)
+		v.assertions(c.Type, c.Request.Assertions)
+	case TypeBrowser, TypeMultiStep:
+		v.minuteFrequency(c.Frequency)
+		v.required("script", c.Script)
+	case TypeHeartbeat:
+		v.heartbeat(c.Heartbeat)
+	default:
+		v.add("checkType", "must be one of %s, %s, %s, %s, use the monitor types for other checks",
+			TypeAPI, TypeBrowser, TypeMultiStep, TypeHeartbeat)
+	}
+	return v.err()
+}
+
+// Validate checks c for mistakes the API would reject, and returns a
+// ValidationErrors listing every invalid field, or nil.
+func (c *MultiStepCheck) Validate() error {
+	var v validator
+	v.name(c.Name)
+	v.minuteFrequency(c.Frequency)
+	v.required("script", c.Script)
+	return v.err()
+}
+
+// Validate checks c for mistakes the API would reject, and returns a
+// ValidationErrors listing every invalid field, or nil.
+func (c *PlaywrightCheck) Validate() error {
+	var v validator
+	v.name(c.Name)
+	v.minuteFrequency(c.Frequency)
+	return v.err()
+}
+
+// Validate checks m for mistakes the API would reject, and returns a
+// ValidationErrors listing every invalid field, or nil.
+func (m *HeartbeatMonitor) Validate() error {
+	var v validator
+	v.name(m.Name)
+	v.heartbeat(m.Heartbeat)
+	return v.err()
+}
+
+// Validate checks m for mistakes the API would reject, and returns a
+// ValidationErrors listing every invalid field, or nil.
+func (m *TCPMonitor) Validate() error {
+	var v validator
+	v.name(m.Name)
+	v.frequency(m.Frequency)
+	v.required("request.hostname", m.Request.Hostname)
+	if m.Request.Port == 0 {
+		v.add("request.port", "is required")
+	}
+	v.ipFamily("request.ipFamily", m.Request.IPFamily)
+	v.assertions(TypeTCP, m.Request.Assertions)
+	return v.err()
+}
+
+// Validate checks m for mistakes the API would reject, and returns a
+// ValidationErrors listing every invalid field, or nil.
+func (m *URLMonitor) Validate() error {
+	var v validator
+	v.name(m.Name)
+	v.frequency(m.Frequency)
+	v.required("request.url", m.Request.URL)
+	v.ipFamily("request.ipFamily", m.Request.IPFamily)
+	v.assertions(TypeURL, m.Request.Assertions)
+	return v.err()
+}
+
+// Validate checks m for mistakes the API would reject, and returns a
+// ValidationErrors listing every invalid field, or nil.
+func (m *DNSMonitor) Validate() error {
+	var v validator
+	v.name(m.Name)
+	v.frequency(m.Frequency)
+	v.required("request.query", m.Request.Query)
+	v.required("request.recordType", m.Request.RecordType)
+	v.oneOf("request.protocol", m.Request.Protocol, "UDP", "TCP")
+	v.assertions(TypeDNS, m.Request.Assertions)
+	return v.err()
+}
+
+// Validate checks m for mistakes the API would reject, and returns a
+// ValidationErrors listing every invalid field, or nil.
+func (m *ICMPMonitor) Validate() error {
+	var v validator
+	v.name(m.Name)
+	v.frequency(m.Frequency)
+	v.required("request.hostname", m.Request.Hostname)
+	v.ipFamily("request.ipFamily", m.Request.IPFamily)
+	v.assertions(TypeICMP, m.Request.Assertions)
+	return v.err()
+}
+
+// Validate checks m for mistakes the API would reject, and returns a
+// ValidationErrors listing every invalid field, or nil. The fields of
+// Request.GRPCConfig must match its mode: BEHAVIOR, the default, requires
+// Method and forbids Service, while HEALTH forbids the fields describing the
+// method to call.
+func (m *GRPCMonitor) Validate() error {
+	var v validator
+	v.name(m.Name)
+	v.frequency(m.Frequency)
+	v.required("request.url", m.Request.URL)
+	v.ipFamily("request.ipFamily", m.Request.IPFamily)
+	config := m.Request.GRPCConfig
+	forbidden := map[string]string{}
+	switch config.Mode {
+	case "", "BEHAVIOR":
+		v.required("request.grpcConfig.method", config.Method)
+		forbidden["service"] = config.Service
+	case "HEALTH":
+		forbidden["serviceDefinition"] = config.ServiceDefinition
+		forbidden["method"] = config.Method
+		forbidden["protoContent"] = config.ProtoContent
+		forbidden["message"] = config.Message
+	default:
+		v.add("request.grpcConfig.mode", "must be one of BEHAVIOR, HEALTH")
+	}
+	for _, field := range []string{"serviceDefinition", "method", "protoContent", "message", "service"} {
+		if forbidden[field] != "" {
+			mode := config.Mode
+			if mode == "" {
+				mode = "BEHAVIOR"
+			}
+			v.add("request.grpcConfig."+field, "is not allowed in %s mode", mode)
+		}
+	}
+	v.assertions(TypeGRPC, m.Request.Assertions)
+	return v.err()
+}
+
+// Validate checks m for mistakes the API would reject, and returns a
+// ValidationErrors listing every invalid field, or nil. Traceroute monitors
+// cannot run from private locations, which TracerouteMonitor enforces by
+// having no PrivateLocations field.
+func (m *TracerouteMonitor) Validate() error {
+	var v validator
+	v.name(m.Name)
+	v.frequency(m.Frequency)
+	v.required("request.url", m.Request.URL)
+	v.ipFamily("request.ipFamily", m.Request.IPFamily)
+	v.assertions(TypeTraceroute, m.Request.Assertions)
+	return v.err()
+}
+
+// Validate checks m for mistakes the API would reject, and returns a
+// ValidationErrors listing every invalid field, or nil. An "explicit" client
+// certificate mode requires Request.SSLClientCertificateId, which is not
+// allowed otherwise.
+func (m *SSLMonitor) Validate() error {
+	var v validator
+	v.name(m.Name)
+	v.frequency(m.Frequency)
+	config := m.Request.SSLConfig
+	v.required("request.sslConfig.hostname", config.Hostname)
+	v.ipFamily("request.sslConfig.ipFamily", config.IPFamily)
+	v.oneOf("request.sslConfig.clientCertificateMode", config.ClientCertificateMode, "auto", "explicit")
+	hasCertificate := m.Request.SSLClientCertificateId != nil && *m.Request.SSLClientCertificateId != ""
+	switch {
+	case config.ClientCertificateMode == "explicit" && !hasCertificate:
+		v.add("request.sslClientCertificateId", "is required in explicit client certificate mode")
+	case config.ClientCertificateMode != "explicit" && m.Request.SSLClientCertificateId != nil:
+		v.add("request.sslClientCertificateId", "is only allowed in explicit client certificate mode")
+	}
+	v.assertions(TypeSSL, m.Request.Assertions)
+	return v.err()
+}
+
+// Validate returns nil, as the SDK doesn't know the rules for checks of
+// unknown types.
+func (m *UnknownMonitor) Validate() error {
+	return nil
+}
+
+// validateMonitor validates m before it is sent, if the client was configured
+// WithValidation.
+func (c *client) validateMonitor(m Monitor) error {
+	if !c.validate {
+		return nil
+	}
+	return m.Validate()
+}
+
+// isValidationErrors reports whether err is a ValidationErrors.
+func isValidationErrors(err error) bool {
+	var errs ValidationErrors
+	return errors.As(err, &errs)
+}
@@ -0,0 +1,205 @@
+package checkly_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	checkly "github.com/checkly/checkly-go-sdk"
+)
+
+func TestValidateFixtures(t *testing.T) {
+	t.Parallel()
+	for _, name := range []string{
+		"GetCheck",
+		"GetDNSMonitor",
+		"GetGRPCMonitor",
+		"GetICMPMonitor",
+		"GetMultiStepCheck",
+		"GetSSLMonitor",
+		"GetTracerouteMonitor",
+		"GetURLMonitor",
+	} {
+		name := name
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			data, err := os.ReadFile("fixtures/" + name + ".json")
+			if err != nil {
+				t.Fatal(err)
+			}
+			m, err := checkly.DecodeMonitor(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := m.Validate(); err != nil {
+				t.Errorf("want the monitor returned by the API to be valid, got %v", err)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+	certificateID := "cert-1"
+	tests := []struct {
+		name    string
+		monitor checkly.Monitor
+		paths   []string
+	}{
+		{
+			name: "GRPC service in BEHAVIOR mode",
+			monitor: &checkly.GRPCMonitor{
+				Name:      "gRPC",
+				Frequency: 10,
+				Request: checkly.GRPCRequest{
+					URL:        "grpc.example.com",
+					GRPCConfig: checkly.GRPCConfig{Method: "Get", Service: "example.Service"},
+				},
+			},
+			paths: []string{"request.grpcConfig.service"},
+		},
+		{
+			name: "GRPC method in HEALTH mode",
+			monitor: &checkly.GRPCMonitor{
+				Name:      "gRPC",
+				Frequency: 10,
+				Request: checkly.GRPCRequest{
+					URL:        "grpc.example.com",
+					GRPCConfig: checkly.GRPCConfig{Mode: "HEALTH", Method: "Get"},
+				},
+			},
+			paths: []string{"request.grpcConfig.method"},
+		},
+		{
+			name:    "multistep check",
+			monitor: &checkly.Check{Name: "Multistep", Type: checkly.TypeMultiStep, Frequency: 10, Script: "steps()"},
+		},
+		{
+			name:    "multistep check without script",
+			monitor: &checkly.Check{Name: "Multistep", Type: checkly.TypeMultiStep, Frequency: 7},
+			paths:   []string{"frequency", "script"},
+		},
+		{
+			name:    "sub-minute browser check",
+			monitor: &checkly.Check{Name: "Browser", Type: checkly.TypeBrowser, Frequency: 0, Script: "visit()"},
+			paths:   []string{"frequency"},
+		},
+		{
+			name:    "sub-minute API check",
+			monitor: &checkly.Check{Name: "API", Type: checkly.TypeAPI, Frequency: 0, FrequencyOffset: 10, Request: checkly.Request{URL: "https://example.com"}},
+		},
+		{
+			name:    "frequency not allowed",
+			monitor: &checkly.URLMonitor{Name: "URL", Frequency: 7, Request: checkly.URLRequest{URL: "https://example.com"}},
+			paths:   []string{"frequency"},
+		},
+		{
+			name: "assertion source of another type",
+			monitor: &checkly.ICMPMonitor{
+				Name:      "ICMP",
+				Frequency: 10,
+				Request: checkly.ICMPRequest{
+					Hostname: "example.com",
+					Assertions: []checkly.Assertion{
+						{Source: "LATENCY", Comparison: checkly.LessThan, Target: "100"},
+						{Source: checkly.JSONBody, Comparison: checkly.NotEmpty},
+					},
+				},
+			},
+			paths: []string{"request.assertions.1.source"},
+		},
+		{
+			name: "SSL explicit client certificate mode without certificate",
+			monitor: &checkly.SSLMonitor{
+				Name:      "SSL",
+				Frequency: 60,
+				Request: checkly.SSLRequest{
+					SSLConfig: checkly.SSLConfig{Hostname: "example.com", ClientCertificateMode: "explicit"},
+				},
+			},
+			paths: []string{"request.sslClientCertificateId"},
+		},
+		{
+			name: "SSL explicit client certificate mode",
+			monitor: &checkly.SSLMonitor{
+				Name:      "SSL",
+				Frequency: 60,
+				Request: checkly.SSLRequest{
+					SSLConfig:              checkly.SSLConfig{Hostname: "example.com", ClientCertificateMode: "explicit"},
+					SSLClientCertificateId: &certificateID,
+				},
+			},
+		},
+		{
+			name:    "several invalid fields",
+			monitor: &checkly.TCPMonitor{Frequency: 3},
+			paths:   []string{"name", "frequency", "request.hostname", "request.port"},
+		},
+		{
+			name:    "heartbeat",
+			monitor: &checkly.HeartbeatMonitor{Name: "Job", Heartbeat: checkly.Heartbeat{Period: 1, PeriodUnit: "weeks", GraceUnit: "hours"}},
+			paths:   []string{"heartbeat.periodUnit"},
+		},
+		{
+			name:    "check of a monitor type",
+			monitor: &checkly.Check{Name: "TCP", Type: checkly.TypeTCP},
+			paths:   []string{"checkType"},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := tc.monitor.Validate()
+			if len(tc.paths) == 0 {
+				if err != nil {
+					t.Fatalf("want no error, got %v", err)
+				}
+				return
+			}
+			var errs checkly.ValidationErrors
+			if !errors.As(err, &errs) {
+				t.Fatalf("want ValidationErrors, got %v", err)
+			}
+			if len(errs) != len(tc.paths) {
+				t.Fatalf("want invalid fields %q, got %v", tc.paths, err)
+			}
+			for i, e := range errs {
+				if e.Path != tc.paths[i] {
+					t.Errorf("want invalid fields %q, got %v", tc.paths, err)
+				}
+			}
+			if !checkly.IsValidationError(err) {
+				t.Error("want IsValidationError to report true")
+			}
+		})
+	}
+}
+
+func TestWithValidation(t *testing.T) {
+	t.Parallel()
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+	client := checkly.New(
+		checkly.WithBaseURL(ts.URL),
+		checkly.WithHTTPClient(ts.Client()),
+		checkly.WithValidation(),
+	)
+	monitor := checkly.URLMonitor{Name: "URL", Frequency: 7, Request: checkly.URLRequest{URL: "https://example.com"}}
+	if _, err := client.CreateURLMonitor(context.Background(), monitor); !checkly.IsValidationError(err) {
+		t.Errorf("want a validation error creating the monitor, got %v", err)
+	}
+	if _, err := client.UpdateURLMonitor(context.Background(), "1", monitor); !checkly.IsValidationError(err) {
+		t.Errorf("want a validation error updating the monitor, got %v", err)
+	}
+	if requests != 0 {
+		t.Errorf("want no requests for invalid monitors, got %d", requests)
+	}
+}